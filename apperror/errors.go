@@ -0,0 +1,23 @@
+package apperror
+
+var (
+	ErrInternal           = New(KindInternal, "internal_error", "internal server error")
+	ErrInvalidRequest     = New(KindInvalid, "invalid_request", "invalid request")
+	ErrUnauthenticated    = New(KindUnauthorized, "unauthenticated", "authentication is required")
+	ErrInvalidCredentials = New(KindUnauthorized, "invalid_credentials", "username or password incorrect")
+	ErrSessionBlocked     = New(KindForbidden, "session_blocked", "you must log in first")
+	ErrForbidden          = New(KindForbidden, "forbidden", "user is not authorized to access this")
+	ErrNotFound           = New(KindNotFound, "not_found", "resource not found")
+	ErrConflict           = New(KindConflict, "conflict", "resource already exists")
+	ErrPayloadTooLarge    = New(KindTooLarge, "payload_too_large", "request body is too large")
+	ErrConstraint         = New(KindUnprocessable, "constraint_violation", "request violates a data constraint")
+	ErrUnavailable        = New(KindUnavailable, "unavailable", "service is temporarily unavailable")
+
+	ErrAccountNotFound   = New(KindNotFound, "account_not_found", "account not found")
+	ErrInvalidCurrency   = New(KindInvalid, "invalid_currency", "invalid currency")
+	ErrCurrencyMismatch  = New(KindUnprocessable, "currency_mismatch", "currency mismatch")
+	ErrInsufficientFunds = New(KindUnprocessable, "insufficient_funds", "insufficient available balance")
+	ErrLimitExceeded     = New(KindRateLimited, "limit_exceeded", "transfer limit exceeded")
+	ErrPayeeCoolingOff   = New(KindForbidden, "payee_cooling_off", "payee is in its cooling-off period")
+	ErrAccountFrozen     = New(KindForbidden, "account_frozen", "account is frozen")
+)
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/manager"
+	"github.com/terajari/bank-api/utils"
+)
+
+func (c *cli) newAccountCommand() *cobra.Command {
+	cmd := newGroupCommand("account", "Manage accounts")
+	cmd.AddCommand(
+		c.newOpenCommand(),
+		c.newFreezeCommand("freeze", "Freeze an account: transfers from and to it are refused", true),
+		c.newFreezeCommand("unfreeze", "Unfreeze a frozen account", false),
+	)
+	return cmd
+}
+
+func (c *cli) newOpenCommand() *cobra.Command {
+	var req dto.OpenFundedAccountRequest
+	cmd := &cobra.Command{
+		Use:     "open OWNER",
+		Short:   "Open an account for a user, funded from the bank's settlement account",
+		Example: `  bank-api account open alice --currency IDR --type savings --deposit 500000`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req.Owner = args[0]
+			if !utils.IsSupportedCurrency(req.Currency) {
+				return errors.New("unsupported currency")
+			}
+			if req.InitialDeposit < 0 {
+				return errors.New("deposit must not be negative")
+			}
+			return c.withUsecases(func(usecases manager.UsecaseManager) error {
+				account, err := usecases.AccountsUsecase().OpenFundedAccount(cmd.Context(), req)
+				if err != nil {
+					return err
+				}
+				return printJSON(cmd, account)
+			})
+		},
+	}
+	cmd.Flags().StringVar(&req.Currency, "currency", "", "currency of the account")
+	cmd.Flags().StringVar(&req.Type, "type", "", "account type, checking by default")
+	cmd.Flags().StringVar(&req.Nickname, "nickname", "", "nickname of the account")
+	cmd.Flags().Int64Var(&req.InitialDeposit, "deposit", 0, "initial deposit in minor units")
+	cmd.MarkFlagRequired("currency")
+	return cmd
+}
+
+func (c *cli) newFreezeCommand(use, short string, frozen bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " ACCOUNT",
+		Short: short,
+		Long:  short + ". ACCOUNT is an account ID or account number.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.withUsecases(func(usecases manager.UsecaseManager) error {
+				account, err := usecases.AccountsUsecase().SetFrozen(cmd.Context(), args[0], frozen)
+				if err != nil {
+					return err
+				}
+				return printJSON(cmd, account)
+			})
+		},
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadPassword(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "first line", input: "s3cret pass\nignored\n", want: "s3cret pass"},
+		{name: "crlf", input: "s3cret\r\n", want: "s3cret"},
+		{name: "no newline", input: "s3cret", want: "s3cret"},
+		{name: "empty", input: "", wantErr: true},
+		{name: "empty line", input: "\nsecond\n", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := readPassword(strings.NewReader(tc.input))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("readPassword() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("readPassword() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMigrationCount(t *testing.T) {
+	testCases := []struct {
+		args    []string
+		want    int
+		wantErr bool
+	}{
+		{args: nil, want: 1},
+		{args: []string{"3"}, want: 3},
+		{args: []string{"0"}, wantErr: true},
+		{args: []string{"all"}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		got, err := migrationCount(tc.args, 1)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("migrationCount(%v) error = %v, wantErr %v", tc.args, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("migrationCount(%v) = %d, want %d", tc.args, got, tc.want)
+		}
+	}
+}
+
+// TestCommandArgs checks that malformed invocations fail before the
+// configuration is loaded or a database is reached.
+func TestCommandArgs(t *testing.T) {
+	testCases := [][]string{
+		{"unknown"},
+		{"migrate", "sideways"},
+		{"migrate", "up", "1", "2"},
+		{"user", "create"},
+		{"account", "freeze"},
+		{"account", "open"},
+		{"session", "revoke"},
+		{"token", "issue"},
+		{"ledger", "verify", "now"},
+		{"serve", "extra"},
+	}
+
+	for _, args := range testCases {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			root := newRootCommand()
+			root.SetArgs(append(args, "--env-file", "testdata/missing.env"))
+			var out bytes.Buffer
+			root.SetOut(&out)
+			root.SetErr(&out)
+			err := root.Execute()
+			if err == nil {
+				t.Fatal("Execute() error = nil")
+			}
+			if strings.Contains(err.Error(), "cannot load config") {
+				t.Errorf("Execute() error = %v, want a usage error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+	"github.com/terajari/bank-api/manager"
+)
+
+// errLedgerInconsistent makes ledger verify exit non-zero after the report.
+var errLedgerInconsistent = errors.New("ledger is inconsistent")
+
+func (c *cli) newLedgerCommand() *cobra.Command {
+	cmd := newGroupCommand("ledger", "Inspect the ledger")
+	cmd.AddCommand(&cobra.Command{
+		Use:   "verify",
+		Short: "Check balances, transfer entries and per-currency totals against the entries",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.withUsecases(func(usecases manager.UsecaseManager) error {
+				report, err := usecases.LedgerUsecase().Verify(cmd.Context())
+				if err != nil {
+					return err
+				}
+				if err := printJSON(cmd, report); err != nil {
+					return err
+				}
+				if !report.Consistent {
+					return errLedgerInconsistent
+				}
+				return nil
+			})
+		},
+	})
+	return cmd
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/terajari/bank-api/migration"
+	"github.com/terajari/bank-api/utils"
+)
+
+func (c *cli) newMigrateCommand() *cobra.Command {
+	cmd := newGroupCommand("migrate", "Apply, revert and inspect the embedded database migrations")
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "up [N]",
+			Short: "Apply all pending migrations, or the next N",
+			Args:  cobra.MaximumNArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				n, err := migrationCount(args, 0)
+				if err != nil {
+					return err
+				}
+				return c.withMigrator(func(mg *migration.Migrator) error { return mg.Up(n) })
+			},
+		},
+		&cobra.Command{
+			Use:   "down [N]",
+			Short: "Revert the newest migration, or the N newest",
+			Args:  cobra.MaximumNArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				n, err := migrationCount(args, 1)
+				if err != nil {
+					return err
+				}
+				return c.withMigrator(func(mg *migration.Migrator) error { return mg.Down(n) })
+			},
+		},
+		&cobra.Command{
+			Use:   "status",
+			Short: "List the migrations and whether they are applied",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return c.withMigrator(func(mg *migration.Migrator) error {
+					statuses, err := mg.Status()
+					if err != nil {
+						return err
+					}
+					for _, s := range statuses {
+						state := "pending"
+						switch {
+						case s.Dirty:
+							state = "dirty"
+						case s.Applied:
+							state = "applied"
+						}
+						fmt.Fprintf(cmd.OutOrStdout(), "%d\t%-8s %s\n", s.Version, state, s.Name)
+					}
+					return nil
+				})
+			},
+		},
+		&cobra.Command{
+			Use:   "version",
+			Short: "Print the version of the newest applied migration",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return c.withMigrator(func(mg *migration.Migrator) error {
+					version, dirty, err := mg.Version()
+					if err != nil {
+						return err
+					}
+					if dirty {
+						fmt.Fprintf(cmd.OutOrStdout(), "%d (dirty)\n", version)
+						return nil
+					}
+					fmt.Fprintln(cmd.OutOrStdout(), version)
+					return nil
+				})
+			},
+		},
+	)
+	return cmd
+}
+
+// migrationCount parses the optional N of up and down, def when it is
+// missing.
+func migrationCount(args []string, def int) (int, error) {
+	if len(args) == 0 {
+		return def, nil
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("N must be a positive number, got %q", args[0])
+	}
+	return n, nil
+}
+
+func (c *cli) withMigrator(fn func(*migration.Migrator) error) error {
+	mg, err := migration.Open(c.cfg.DBDriver, c.cfg.DBSource, c.cfg.MigrationLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer mg.Close()
+	return fn(mg)
+}
+
+// autoMigrate applies the pending migrations on startup.
+func autoMigrate(cfg utils.Config) error {
+	mg, err := migration.Open(cfg.DBDriver, cfg.DBSource, cfg.MigrationLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer mg.Close()
+	return mg.Up(0)
+}
@@ -0,0 +1,125 @@
+// Package cmd is the command line of the bank API: the server, the
+// migrations and the operator commands. Every command is wired through the
+// manager package exactly like the server, so an operator command runs the
+// same usecases, validations and logging as the matching API call.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/terajari/bank-api/logging"
+	"github.com/terajari/bank-api/manager"
+	"github.com/terajari/bank-api/utils"
+)
+
+// cli holds what the commands share: the configuration and logger set up
+// before any of them runs.
+type cli struct {
+	envFile string
+	cfg     utils.Config
+	logger  *slog.Logger
+}
+
+// Execute runs the command named by the arguments of the process.
+func Execute() error {
+	return newRootCommand().ExecuteContext(context.Background())
+}
+
+func newRootCommand() *cobra.Command {
+	c := &cli{}
+	root := &cobra.Command{
+		Use:   "bank-api",
+		Short: "Bank API server and operator commands",
+		// Arguments are validated by now; a failure from here on is not a
+		// usage error.
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return c.setup()
+		},
+	}
+	root.PersistentFlags().StringVar(&c.envFile, "env-file", "./.env", "configuration file")
+
+	root.AddCommand(
+		c.newServeCommand(),
+		c.newMigrateCommand(),
+		c.newUserCommand(),
+		c.newAccountCommand(),
+		c.newSessionCommand(),
+		c.newTokenCommand(),
+		c.newLedgerCommand(),
+	)
+	return root
+}
+
+// setup loads the configuration and installs the logger. The standard log
+// package writes through the default logger from here on.
+func (c *cli) setup() error {
+	cfg, err := utils.LoadConfig(c.envFile)
+	if err != nil {
+		return fmt.Errorf("cannot load config: %w", err)
+	}
+	level, err := logging.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		return err
+	}
+	c.cfg = cfg
+	c.logger = logging.New(os.Stderr, level)
+	slog.SetDefault(c.logger)
+	return nil
+}
+
+// managers connects to the database and builds the repositories and
+// usecases the way the server does. The caller closes the returned infra.
+func (c *cli) managers() (manager.InfrastuctureManager, manager.UsecaseManager, error) {
+	infra, err := manager.NewInfraManager(&c.cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot connect to the database: %w", err)
+	}
+	repoManager, err := manager.NewRepositoryManager(infra)
+	if err != nil {
+		infra.Close()
+		return nil, nil, fmt.Errorf("cannot create repositories: %w", err)
+	}
+	usecaseManager, err := manager.NewUsecaseManager(infra, repoManager)
+	if err != nil {
+		infra.Close()
+		return nil, nil, fmt.Errorf("cannot create usecases: %w", err)
+	}
+	return infra, usecaseManager, nil
+}
+
+// withUsecases runs fn with the usecases and closes the database after it.
+func (c *cli) withUsecases(fn func(manager.UsecaseManager) error) error {
+	infra, usecases, err := c.managers()
+	if err != nil {
+		return err
+	}
+	defer infra.Close()
+	return fn(usecases)
+}
+
+// newGroupCommand returns a command that only groups subcommands. Unlike a
+// plain cobra group it fails on an unknown subcommand instead of printing the
+// help and exiting successfully.
+func newGroupCommand(use, short string) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+}
+
+// printJSON writes v to the output of cmd, for operators and scripts alike.
+func printJSON(cmd *cobra.Command, v any) error {
+	enc := json.NewEncoder(cmd.OutOrStdout())
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
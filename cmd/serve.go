@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/terajari/bank-api/delivery"
+	"github.com/terajari/bank-api/tracing"
+	"github.com/terajari/bank-api/worker"
+)
+
+func (c *cli) newServeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Start the HTTP server and the background workers",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.serve(cmd.Context())
+		},
+	}
+}
+
+func (c *cli) serve(ctx context.Context) error {
+	cfg, logger := c.cfg, c.logger
+
+	shutdownTracing, err := tracing.Setup(&cfg)
+	if err != nil {
+		return fmt.Errorf("cannot set up tracing: %w", err)
+	}
+
+	infra, usecaseManager, err := c.managers()
+	if err != nil {
+		return err
+	}
+	// Up runs under an advisory lock, so replicas starting together apply
+	// each migration once.
+	if cfg.AutoMigrate {
+		if err := autoMigrate(cfg); err != nil {
+			infra.Close()
+			return fmt.Errorf("cannot apply migrations: %w", err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Workers are stopped in reverse order: the batch worker, which still
+	// makes transfers, goes first.
+	var workers worker.Group
+	if cfg.LedgerVerifyInterval > 0 {
+		workers.Go(context.Background(), "ledger", worker.NewLedgerWorker(usecaseManager.LedgerUsecase(), cfg.LedgerVerifyInterval).Run)
+	}
+	if cfg.InterestAccrualEnabled {
+		workers.Go(context.Background(), "interest", worker.NewInterestWorker(usecaseManager.InterestUsecase(), cfg.InterestDryRun).Run)
+	}
+	workers.Go(context.Background(), "holds", worker.NewHoldExpiryWorker(usecaseManager.TransferUsecase(), cfg.HoldExpiryInterval).Run)
+	workers.Go(context.Background(), "batch", worker.NewBatchWorker(usecaseManager.BatchUsecase(), cfg.BatchPollInterval).Run)
+
+	server, err := delivery.NewServer(cfg, usecaseManager)
+	if err != nil {
+		return fmt.Errorf("cannot create server: %w", err)
+	}
+	server.SetupRouter()
+
+	logger.Info("listening", "address", cfg.HTTPServer)
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Start(cfg.HTTPServer)
+	}()
+
+	// A server that failed to listen has no traffic to drain, the workers
+	// and the pool are still shut down before its error is returned.
+	var failed error
+	select {
+	case err := <-serveErr:
+		failed = fmt.Errorf("server failed: %w", err)
+	case <-ctx.Done():
+		logger.Info("shutting down")
+
+		// Fail readiness first and give the load balancer time to notice
+		// before the listener is closed.
+		server.Drain()
+		time.Sleep(cfg.ShutdownDrainDelay)
+	}
+	stop()
+
+	// Drain requests first, then stop the workers and only then close the
+	// pool they all share.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("server shutdown failed", "error", err)
+	}
+	if err := workers.Stop(shutdownCtx); err != nil {
+		logger.Error("workers did not stop", "error", err)
+	}
+	if err := infra.Close(); err != nil {
+		logger.Error("cannot close database", "error", err)
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("cannot flush traces", "error", err)
+	}
+	return failed
+}
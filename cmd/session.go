@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/manager"
+)
+
+func (c *cli) newSessionCommand() *cobra.Command {
+	cmd := newGroupCommand("session", "Manage login sessions")
+	cmd.AddCommand(&cobra.Command{
+		Use:   "revoke SESSION_ID",
+		Short: "Block a session so that its refresh token can no longer renew access tokens",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := uuid.Parse(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid session ID %q: %w", args[0], err)
+			}
+			return c.withUsecases(func(usecases manager.UsecaseManager) error {
+				sessions := usecases.SessionsUsecase()
+				session, err := sessions.GetSessions(cmd.Context(), id)
+				if err != nil {
+					return err
+				}
+				if err := sessions.UpdateBlockStatus(cmd.Context(), dto.UpdateSessionBlockRequest{Id: id, IsBlocked: true}); err != nil {
+					return err
+				}
+				c.logger.Info("session revoked", "session_id", id, "username", session.Username)
+				fmt.Fprintf(cmd.OutOrStdout(), "revoked session %s of %s\n", id, session.Username)
+				return nil
+			})
+		},
+	})
+	return cmd
+}
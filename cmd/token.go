@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/manager"
+)
+
+func (c *cli) newTokenCommand() *cobra.Command {
+	cmd := newGroupCommand("token", "Issue access tokens")
+
+	var duration time.Duration
+	issue := &cobra.Command{
+		Use:   "issue USERNAME",
+		Short: "Issue an access token for an existing user, for testing",
+		Long: `Issue an access token for an existing user, for testing.
+
+The token is signed with TOKEN_SYMMETRIC_KEY and is not tied to a session,
+so it cannot be renewed and stays valid until it expires.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if duration <= 0 {
+				duration = c.cfg.AccessTokenDuration
+			}
+			return c.withUsecases(func(usecases manager.UsecaseManager) error {
+				user, err := usecases.UsersUsecase().GetUser(cmd.Context(), args[0])
+				if err != nil {
+					if errors.Is(err, sql.ErrNoRows) {
+						return fmt.Errorf("user %q not found", args[0])
+					}
+					return err
+				}
+				token, payload, err := usecases.TokenMaker().CreateToken(user.Username, duration)
+				if err != nil {
+					return err
+				}
+				c.logger.Info("access token issued", "username", user.Username, "expires_at", payload.ExpiredAt)
+				return printJSON(cmd, dto.RenewAccessTokenResponse{
+					AccessToken:          token,
+					AccessTokenExpiresAt: payload.ExpiredAt,
+				})
+			})
+		},
+	}
+	issue.Flags().DurationVar(&duration, "duration", 0, "validity of the token (default ACCESS_TOKEN_DURATION)")
+
+	cmd.AddCommand(issue)
+	return cmd
+}
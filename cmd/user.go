@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/spf13/cobra"
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/manager"
+	"github.com/terajari/bank-api/model"
+	"github.com/terajari/bank-api/utils"
+)
+
+func (c *cli) newUserCommand() *cobra.Command {
+	cmd := newGroupCommand("user", "Manage users")
+
+	var req dto.CreateUserRequest
+	var admin bool
+	create := &cobra.Command{
+		Use:     "create USERNAME",
+		Short:   "Create a user, reading the password from the first line of stdin",
+		Example: `  printf '%s\n' "$ADMIN_PASSWORD" | bank-api user create alice --full-name "Alice" --email alice@example.com --admin`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req.Username = args[0]
+			password, err := readPassword(cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+			req.Password = password
+			if admin {
+				req.Role = model.RoleAdmin
+			}
+			if err := c.validateUser(req); err != nil {
+				return err
+			}
+			return c.withUsecases(func(usecases manager.UsecaseManager) error {
+				user, err := usecases.UsersUsecase().CreateUser(cmd.Context(), req)
+				if err != nil {
+					return err
+				}
+				return printJSON(cmd, user)
+			})
+		},
+	}
+	create.Flags().StringVar(&req.FullName, "full-name", "", "full name of the user")
+	create.Flags().StringVar(&req.Email, "email", "", "email address of the user")
+	create.Flags().BoolVar(&admin, "admin", false, "give the user the admin role")
+	create.MarkFlagRequired("full-name")
+	create.MarkFlagRequired("email")
+
+	cmd.AddCommand(create)
+	return cmd
+}
+
+// readPassword reads the password from the first line of r, so that it ends
+// up neither in the shell history nor in the process list.
+func readPassword(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("no password on stdin")
+	}
+	return password, nil
+}
+
+// validateUser applies the rules the API binds a sign-up request with.
+func (c *cli) validateUser(req dto.CreateUserRequest) error {
+	validate := validator.New()
+	if err := validate.Var(req.Username, "required,alphanum"); err != nil {
+		return errors.New("username must be alphanumeric")
+	}
+	if err := validate.Var(req.Email, "required,email"); err != nil {
+		return fmt.Errorf("invalid email address %q", req.Email)
+	}
+	policy, err := utils.NewPasswordPolicy(c.cfg.PasswordMinLength, c.cfg.PasswordMaxLength, c.cfg.PasswordBreachedList)
+	if err != nil {
+		return err
+	}
+	return policy.Validate(req.Password)
+}
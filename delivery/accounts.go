@@ -1,13 +1,15 @@
 package delivery
 
 import (
-	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/lib/pq"
+	"github.com/terajari/bank-api/apperror"
 	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/logging"
 	"github.com/terajari/bank-api/middleware"
+	"github.com/terajari/bank-api/statement"
 	"github.com/terajari/bank-api/token"
 	"github.com/terajari/bank-api/usecase"
 )
@@ -26,22 +28,24 @@ func NewAccountsHandler(uc usecase.AccountsUsecase, ss usecase.SessionsUsecase)
 
 type reqCreate struct {
 	Currency string `json:"currency" binding:"required"`
+	Nickname string `json:"nickname" binding:"max=64"`
+	Type     string `json:"type"`
 }
 
 func (a *AccountsHandler) createHandler(ctx *gin.Context) {
 	var req reqCreate
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
 		return
 	}
 
 	ls, err := a.sessionsUsecase.LastSession(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 	if ls.IsBlocked {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "you must login first"})
+		ctx.Error(apperror.ErrSessionBlocked)
 		return
 	}
 
@@ -50,18 +54,11 @@ func (a *AccountsHandler) createHandler(ctx *gin.Context) {
 	resp, err := a.usecase.RegisterNewAccounts(ctx, dto.RegisterNewAccountsRequest{
 		Owner:    authPayload.Username,
 		Currency: req.Currency,
+		Nickname: req.Nickname,
+		Type:     req.Type,
 	})
 	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok {
-			switch pqErr.Code.Name() {
-			case "foreign_key_violation", "unique_violation":
-				ctx.JSON(http.StatusForbidden, gin.H{
-					"error": errors.New(pqErr.Message),
-				})
-				return
-			}
-		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusOK, resp)
@@ -70,29 +67,29 @@ func (a *AccountsHandler) createHandler(ctx *gin.Context) {
 func (a *AccountsHandler) getHandler(ctx *gin.Context) {
 	var req dto.GetAccountRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
 		return
 	}
 
 	ls, err := a.sessionsUsecase.LastSession(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 	if ls.IsBlocked {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "you must login first"})
+		ctx.Error(apperror.ErrSessionBlocked)
 		return
 	}
 
 	resp, err := a.usecase.GetAccount(ctx, req.Id)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
 	authPayload := ctx.MustGet(middleware.AuthorizationPayloadKey).(*token.Payload)
 	if resp.Owner != authPayload.Username {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user is not authorized to access this"})
+		ctx.Error(apperror.ErrForbidden)
 		return
 	}
 
@@ -102,18 +99,18 @@ func (a *AccountsHandler) getHandler(ctx *gin.Context) {
 func (a *AccountsHandler) listHandlers(ctx *gin.Context) {
 	var req dto.ListAccountsRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
 		return
 	}
 
 	ls, err := a.sessionsUsecase.LastSession(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
 	if ls.IsBlocked {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "you must login first"})
+		ctx.Error(apperror.ErrSessionBlocked)
 		return
 	}
 
@@ -122,9 +119,114 @@ func (a *AccountsHandler) listHandlers(ctx *gin.Context) {
 
 	resp, err := a.usecase.ListAccounts(ctx, req)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (a *AccountsHandler) updateHandler(ctx *gin.Context) {
+	var uri dto.GetAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+	var req dto.UpdateAccountNicknameRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	ls, err := a.sessionsUsecase.LastSession(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	if ls.IsBlocked {
+		ctx.Error(apperror.ErrSessionBlocked)
+		return
+	}
+
+	acc, err := a.usecase.GetAccount(ctx, uri.Id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	authPayload := ctx.MustGet(middleware.AuthorizationPayloadKey).(*token.Payload)
+	if acc.Owner != authPayload.Username {
+		ctx.Error(apperror.ErrForbidden)
+		return
+	}
+
+	req.Id = acc.Id
+	resp, err := a.usecase.UpdateNickname(ctx, req)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, resp)
 }
+
+func (a *AccountsHandler) statementHandler(ctx *gin.Context) {
+	var uri dto.GetAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+	var req dto.StatementRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	ls, err := a.sessionsUsecase.LastSession(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	if ls.IsBlocked {
+		ctx.Error(apperror.ErrSessionBlocked)
+		return
+	}
+
+	acc, err := a.usecase.GetAccount(ctx, uri.Id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	authPayload := ctx.MustGet(middleware.AuthorizationPayloadKey).(*token.Payload)
+	if acc.Owner != authPayload.Username {
+		ctx.Error(apperror.ErrForbidden)
+		return
+	}
+
+	if req.Format == "" {
+		req.Format = statement.FormatCSV
+	}
+	req.AccountId = acc.Id
+
+	// The export streams for as long as its query runs, which
+	// DB_STATEMENT_TIMEOUT bounds, so HTTP_WRITE_TIMEOUT must not cut it off.
+	if err := http.NewResponseController(ctx.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		logging.FromContext(ctx).Warn("statement: cannot clear write deadline", "error", err)
+	}
+	ctx.Header("Content-Type", statement.ContentType(req.Format))
+	ctx.Header("Content-Disposition", `attachment; filename="statement-`+acc.Id+"."+statement.FileExtension(req.Format)+`"`)
+
+	if err := a.usecase.ExportStatement(ctx, req, ctx.Writer); err != nil {
+		// Once the body has started the status is sent and the error can only
+		// be logged; the client sees a truncated download.
+		if ctx.Writer.Written() {
+			logging.FromContext(ctx).Error("statement: export aborted", "account_id", acc.Id, "error", err)
+			ctx.Abort()
+			return
+		}
+		ctx.Writer.Header().Del("Content-Disposition")
+		ctx.Writer.Header().Del("Content-Type")
+		ctx.Error(err)
+	}
+}
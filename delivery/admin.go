@@ -0,0 +1,98 @@
+package delivery
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/terajari/bank-api/apperror"
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/middleware"
+	"github.com/terajari/bank-api/token"
+	"github.com/terajari/bank-api/usecase"
+)
+
+type AdminHandler struct {
+	transferUsecase usecase.TransferUsecase
+	usersUsecase    usecase.UsersUsecase
+	sessionsUsecase usecase.SessionsUsecase
+}
+
+func NewAdminHandler(tu usecase.TransferUsecase, uu usecase.UsersUsecase, su usecase.SessionsUsecase) (*AdminHandler, error) {
+	return &AdminHandler{
+		transferUsecase: tu,
+		usersUsecase:    uu,
+		sessionsUsecase: su,
+	}, nil
+}
+
+func (a *AdminHandler) pendingTransfersHandler(ctx *gin.Context) {
+	var req dto.ListTransfersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	if !a.activeSession(ctx) {
+		return
+	}
+
+	resp, err := a.transferUsecase.ListPendingTransfers(ctx, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (a *AdminHandler) approveTransferHandler(ctx *gin.Context) {
+	var req dto.ReviewTransferRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	if !a.activeSession(ctx) {
+		return
+	}
+
+	authPayload := ctx.MustGet(middleware.AuthorizationPayloadKey).(*token.Payload)
+	resp, err := a.transferUsecase.ApproveTransfer(ctx, req.Id, authPayload.Username)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (a *AdminHandler) rejectTransferHandler(ctx *gin.Context) {
+	var req dto.ReviewTransferRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	if !a.activeSession(ctx) {
+		return
+	}
+
+	authPayload := ctx.MustGet(middleware.AuthorizationPayloadKey).(*token.Payload)
+	resp, err := a.transferUsecase.RejectTransfer(ctx, req.Id, authPayload.Username)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (a *AdminHandler) activeSession(ctx *gin.Context) bool {
+	ls, err := a.sessionsUsecase.LastSession(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return false
+	}
+	if ls.IsBlocked {
+		ctx.Error(apperror.ErrSessionBlocked)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+package delivery
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/middleware"
+	mockusecase "github.com/terajari/bank-api/mock/usecase"
+	"github.com/terajari/bank-api/model"
+	"github.com/terajari/bank-api/usecase"
+)
+
+func TestReviewTransferHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name       string
+		path       string
+		setup      func(tu *mockusecase.MockTransferUsecase)
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name: "approved",
+			path: "/admin/transfers/tr1/approve",
+			setup: func(tu *mockusecase.MockTransferUsecase) {
+				tu.EXPECT().ApproveTransfer(gomock.Any(), "tr1", "admin").
+					Return(dto.MakeTransferResponse{Transfer: model.Transfer{ID: "tr1", Status: model.TransferStatusCompleted}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "own transfer",
+			path: "/admin/transfers/tr1/approve",
+			setup: func(tu *mockusecase.MockTransferUsecase) {
+				tu.EXPECT().ApproveTransfer(gomock.Any(), "tr1", "admin").Return(dto.MakeTransferResponse{}, usecase.ErrSelfApproval)
+			},
+			wantStatus: http.StatusForbidden,
+			wantCode:   "self_approval",
+		},
+		{
+			name: "approval of a transfer no longer pending",
+			path: "/admin/transfers/tr1/approve",
+			setup: func(tu *mockusecase.MockTransferUsecase) {
+				tu.EXPECT().ApproveTransfer(gomock.Any(), "tr1", "admin").Return(dto.MakeTransferResponse{}, usecase.ErrTransferNotPending)
+			},
+			wantStatus: http.StatusConflict,
+			wantCode:   "transfer_not_pending",
+		},
+		{
+			name: "rejected",
+			path: "/admin/transfers/tr1/reject",
+			setup: func(tu *mockusecase.MockTransferUsecase) {
+				tu.EXPECT().RejectTransfer(gomock.Any(), "tr1", "admin").
+					Return(model.Transfer{ID: "tr1", Status: model.TransferStatusRejected}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "rejection of a transfer no longer pending",
+			path: "/admin/transfers/tr1/reject",
+			setup: func(tu *mockusecase.MockTransferUsecase) {
+				tu.EXPECT().RejectTransfer(gomock.Any(), "tr1", "admin").Return(model.Transfer{}, usecase.ErrTransferNotPending)
+			},
+			wantStatus: http.StatusConflict,
+			wantCode:   "transfer_not_pending",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tu := mockusecase.NewMockTransferUsecase(ctrl)
+			ss := mockusecase.NewMockSessionsUsecase(ctrl)
+			ss.EXPECT().LastSession(gomock.Any()).Return(dto.SessionResponse{}, nil)
+			tc.setup(tu)
+			handler, err := NewAdminHandler(tu, nil, ss)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			router := gin.New()
+			router.Use(middleware.ErrorMiddleware(), authorizedAs("admin"))
+			router.POST("/admin/transfers/:id/approve", handler.approveTransferHandler)
+			router.POST("/admin/transfers/:id/reject", handler.rejectTransferHandler)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tc.path, nil))
+			checkProblem(t, rec, tc.wantStatus, tc.wantCode)
+		})
+	}
+}
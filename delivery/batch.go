@@ -0,0 +1,99 @@
+package delivery
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/terajari/bank-api/apperror"
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/middleware"
+	"github.com/terajari/bank-api/token"
+	"github.com/terajari/bank-api/usecase"
+)
+
+// batchTransfer runs small batches right away and queues larger ones,
+// answering 202 with the job to poll.
+func (t *TransferHandler) batchTransfer(ctx *gin.Context) {
+	var req dto.BatchTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+	if len(req.Items) > t.config.BatchMaxItems {
+		ctx.Error(apperror.ErrInvalidRequest.Withf("a batch can have at most %d items", t.config.BatchMaxItems))
+		return
+	}
+
+	ls, err := t.sessionsUsecase.LastSession(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	if ls.IsBlocked {
+		ctx.Error(apperror.ErrSessionBlocked)
+		return
+	}
+
+	sender, ok := t.validAccount(ctx, req.SenderId, req.Currency)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(middleware.AuthorizationPayloadKey).(*token.Payload)
+	if sender.Owner != authPayload.Username {
+		ctx.Error(usecase.ErrNotAccountOwner)
+		return
+	}
+
+	var total int64
+	for _, item := range req.Items {
+		total += item.Amount
+	}
+	if !t.stepUp(ctx, authPayload.Username, dto.MakeTransferRequest{Amount: total, TotpCode: req.TotpCode}) {
+		return
+	}
+
+	req.Owner = authPayload.Username
+	if len(req.Items) > t.config.BatchAsyncThreshold {
+		job, err := t.batchUsecase.Enqueue(ctx, req)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusAccepted, job)
+		return
+	}
+
+	resp, err := t.transferUsecase.MakeTransferBatch(ctx, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (t *TransferHandler) batchStatus(ctx *gin.Context) {
+	var req dto.BatchJobRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	ls, err := t.sessionsUsecase.LastSession(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	if ls.IsBlocked {
+		ctx.Error(apperror.ErrSessionBlocked)
+		return
+	}
+
+	authPayload := ctx.MustGet(middleware.AuthorizationPayloadKey).(*token.Payload)
+	job, err := t.batchUsecase.GetJob(ctx, req.Id, authPayload.Username)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,49 @@
+package delivery
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/terajari/bank-api/buildinfo"
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/usecase"
+)
+
+type HealthHandler struct {
+	usecase  usecase.HealthUsecase
+	draining atomic.Bool
+}
+
+func NewHealthHandler(uc usecase.HealthUsecase) (*HealthHandler, error) {
+	return &HealthHandler{
+		usecase: uc,
+	}, nil
+}
+
+// livenessHandler only reports that the process serves requests, it never
+// looks at the database so that an outage does not get the pod restarted.
+func (h *HealthHandler) livenessHandler(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"status": dto.HealthStatusOK})
+}
+
+func (h *HealthHandler) readinessHandler(ctx *gin.Context) {
+	if h.draining.Load() {
+		ctx.JSON(http.StatusServiceUnavailable, dto.ReadinessResponse{
+			Status: dto.HealthStatusFailing,
+			Checks: map[string]string{"shutdown": "draining"},
+		})
+		return
+	}
+
+	resp := h.usecase.Readiness(ctx)
+	status := http.StatusOK
+	if resp.Status != dto.HealthStatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	ctx.JSON(status, resp)
+}
+
+func (h *HealthHandler) versionHandler(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, buildinfo.Get())
+}
@@ -0,0 +1,116 @@
+package delivery
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/terajari/bank-api/buildinfo"
+	"github.com/terajari/bank-api/dto"
+	mockusecase "github.com/terajari/bank-api/mock/usecase"
+)
+
+func TestReadinessHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ready := dto.ReadinessResponse{Status: "ok", Checks: map[string]string{"database": "ok", "migrations": "ok"}}
+	failing := dto.ReadinessResponse{Status: "failing", Checks: map[string]string{"database": "unreachable", "migrations": "unknown"}}
+
+	testCases := []struct {
+		name       string
+		draining   bool
+		setup      func(uc *mockusecase.MockHealthUsecase)
+		wantStatus int
+		wantChecks map[string]string
+	}{
+		{
+			name: "ready",
+			setup: func(uc *mockusecase.MockHealthUsecase) {
+				uc.EXPECT().Readiness(gomock.Any()).Return(ready)
+			},
+			wantStatus: http.StatusOK,
+			wantChecks: ready.Checks,
+		},
+		{
+			name: "check failing",
+			setup: func(uc *mockusecase.MockHealthUsecase) {
+				uc.EXPECT().Readiness(gomock.Any()).Return(failing)
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantChecks: failing.Checks,
+		},
+		{
+			name:       "draining",
+			draining:   true,
+			setup:      func(uc *mockusecase.MockHealthUsecase) {},
+			wantStatus: http.StatusServiceUnavailable,
+			wantChecks: map[string]string{"shutdown": "draining"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mockusecase.NewMockHealthUsecase(ctrl)
+			tc.setup(uc)
+			handler, err := NewHealthHandler(uc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			server := &Server{HealthHandler: handler}
+			if tc.draining {
+				server.Drain()
+			}
+
+			router := gin.New()
+			router.GET("/readyz", handler.readinessHandler)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tc.wantStatus)
+			}
+			var got dto.ReadinessResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if len(got.Checks) != len(tc.wantChecks) {
+				t.Fatalf("checks = %v, want %v", got.Checks, tc.wantChecks)
+			}
+			for name, want := range tc.wantChecks {
+				if got.Checks[name] != want {
+					t.Errorf("checks[%s] = %q, want %q", name, got.Checks[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestVersionHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	commit, buildTime := buildinfo.Commit, buildinfo.BuildTime
+	defer func() { buildinfo.Commit, buildinfo.BuildTime = commit, buildTime }()
+	buildinfo.Commit = "0123abc"
+	buildinfo.BuildTime = "2023-11-08T10:00:00Z"
+
+	handler, err := NewHealthHandler(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := gin.New()
+	router.GET("/version", handler.versionHandler)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	var got buildinfo.Info
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Commit != "0123abc" || got.BuildTime != "2023-11-08T10:00:00Z" || got.GoVersion == "" {
+		t.Errorf("version = %+v", got)
+	}
+}
@@ -0,0 +1,150 @@
+package delivery
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/terajari/bank-api/apperror"
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/middleware"
+	"github.com/terajari/bank-api/model"
+	"github.com/terajari/bank-api/token"
+	"github.com/terajari/bank-api/usecase"
+)
+
+func (t *TransferHandler) authorizeHold(ctx *gin.Context) {
+	var req dto.AuthorizeHoldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	ls, err := t.sessionsUsecase.LastSession(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	if ls.IsBlocked {
+		ctx.Error(apperror.ErrSessionBlocked)
+		return
+	}
+
+	sender, ok := t.validAccount(ctx, req.SenderId, req.Currency)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(middleware.AuthorizationPayloadKey).(*token.Payload)
+	if sender.Owner != authPayload.Username {
+		ctx.Error(usecase.ErrNotAccountOwner)
+		return
+	}
+
+	_, ok = t.validAccount(ctx, req.ReceiverId, req.Currency)
+	if !ok {
+		return
+	}
+
+	if !t.stepUp(ctx, authPayload.Username, dto.MakeTransferRequest{Amount: req.Amount, TotpCode: req.TotpCode}) {
+		return
+	}
+
+	resp, err := t.transferUsecase.AuthorizeHold(ctx, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (t *TransferHandler) getHold(ctx *gin.Context) {
+	hold, ok := t.ownHold(ctx, true)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, hold)
+}
+
+func (t *TransferHandler) captureHold(ctx *gin.Context) {
+	var req dto.CaptureHoldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	hold, ok := t.ownHold(ctx, false)
+	if !ok {
+		return
+	}
+	req.Id = hold.ID
+
+	resp, err := t.transferUsecase.CaptureHold(ctx, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (t *TransferHandler) voidHold(ctx *gin.Context) {
+	hold, ok := t.ownHold(ctx, false)
+	if !ok {
+		return
+	}
+
+	resp, err := t.transferUsecase.VoidHold(ctx, hold.ID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// ownHold loads the hold from the uri. Only the receiver's owner may capture
+// or void a hold; the sender's owner may also view it when allowSender is set.
+func (t *TransferHandler) ownHold(ctx *gin.Context, allowSender bool) (model.Hold, bool) {
+	var req dto.HoldRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
+		return model.Hold{}, false
+	}
+
+	ls, err := t.sessionsUsecase.LastSession(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return model.Hold{}, false
+	}
+	if ls.IsBlocked {
+		ctx.Error(apperror.ErrSessionBlocked)
+		return model.Hold{}, false
+	}
+
+	hold, err := t.transferUsecase.GetHold(ctx, req.Id)
+	if err != nil {
+		ctx.Error(err)
+		return model.Hold{}, false
+	}
+
+	authPayload := ctx.MustGet(middleware.AuthorizationPayloadKey).(*token.Payload)
+	receiver, ok := t.validAccount(ctx, hold.ReceiverId, hold.Currency)
+	if !ok {
+		return model.Hold{}, false
+	}
+	if receiver.Owner == authPayload.Username {
+		return hold, true
+	}
+	if allowSender {
+		sender, ok := t.validAccount(ctx, hold.AccountId, hold.Currency)
+		if !ok {
+			return model.Hold{}, false
+		}
+		if sender.Owner == authPayload.Username {
+			return hold, true
+		}
+	}
+
+	ctx.Error(apperror.ErrForbidden.Withf("hold doesn't belong to the authenticated user"))
+	return model.Hold{}, false
+}
@@ -0,0 +1,73 @@
+package delivery
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/terajari/bank-api/apperror"
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/middleware"
+	"github.com/terajari/bank-api/token"
+	"github.com/terajari/bank-api/usecase"
+)
+
+type MfaHandler struct {
+	usecase         usecase.MfaUsecase
+	sessionsUsecase usecase.SessionsUsecase
+}
+
+func NewMfaHandler(uc usecase.MfaUsecase, ss usecase.SessionsUsecase) (*MfaHandler, error) {
+	return &MfaHandler{
+		usecase:         uc,
+		sessionsUsecase: ss,
+	}, nil
+}
+
+func (m *MfaHandler) enrollHandler(ctx *gin.Context) {
+	ls, err := m.sessionsUsecase.LastSession(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	if ls.IsBlocked {
+		ctx.Error(apperror.ErrSessionBlocked)
+		return
+	}
+
+	authPayload := ctx.MustGet(middleware.AuthorizationPayloadKey).(*token.Payload)
+
+	resp, err := m.usecase.Enroll(ctx, authPayload.Username)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (m *MfaHandler) confirmHandler(ctx *gin.Context) {
+	var req dto.ConfirmMfaRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	ls, err := m.sessionsUsecase.LastSession(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	if ls.IsBlocked {
+		ctx.Error(apperror.ErrSessionBlocked)
+		return
+	}
+
+	authPayload := ctx.MustGet(middleware.AuthorizationPayloadKey).(*token.Payload)
+	req.Username = authPayload.Username
+
+	resp, err := m.usecase.Confirm(ctx, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,167 @@
+package delivery
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/middleware"
+	mockusecase "github.com/terajari/bank-api/mock/usecase"
+	"github.com/terajari/bank-api/token"
+	"github.com/terajari/bank-api/usecase"
+)
+
+// checkProblem fails the test unless rec carries the wanted status and, when
+// wantCode is set, a problem with that code.
+func checkProblem(t *testing.T, rec *httptest.ResponseRecorder, wantStatus int, wantCode string) {
+	t.Helper()
+	if rec.Code != wantStatus {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, wantStatus, rec.Body.String())
+	}
+	if wantCode == "" {
+		return
+	}
+	var problem middleware.Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatal(err)
+	}
+	if problem.Code != wantCode {
+		t.Errorf("code = %q, want %q", problem.Code, wantCode)
+	}
+}
+
+func authorizedAs(username string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Set(middleware.AuthorizationPayloadKey, &token.Payload{Username: username})
+	}
+}
+
+func TestEnrollMfaHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name       string
+		setup      func(uc *mockusecase.MockMfaUsecase, ss *mockusecase.MockSessionsUsecase)
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name: "enrolled",
+			setup: func(uc *mockusecase.MockMfaUsecase, ss *mockusecase.MockSessionsUsecase) {
+				ss.EXPECT().LastSession(gomock.Any()).Return(dto.SessionResponse{}, nil)
+				uc.EXPECT().Enroll(gomock.Any(), "fulan1234").Return(dto.EnrollMfaResponse{Secret: "SECRET"}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "blocked session",
+			setup: func(uc *mockusecase.MockMfaUsecase, ss *mockusecase.MockSessionsUsecase) {
+				ss.EXPECT().LastSession(gomock.Any()).Return(dto.SessionResponse{IsBlocked: true}, nil)
+			},
+			wantStatus: http.StatusForbidden,
+			wantCode:   "session_blocked",
+		},
+		{
+			name: "already enabled",
+			setup: func(uc *mockusecase.MockMfaUsecase, ss *mockusecase.MockSessionsUsecase) {
+				ss.EXPECT().LastSession(gomock.Any()).Return(dto.SessionResponse{}, nil)
+				uc.EXPECT().Enroll(gomock.Any(), "fulan1234").Return(dto.EnrollMfaResponse{}, usecase.ErrMfaAlreadyEnabled)
+			},
+			wantStatus: http.StatusConflict,
+			wantCode:   "mfa_already_enabled",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mockusecase.NewMockMfaUsecase(ctrl)
+			ss := mockusecase.NewMockSessionsUsecase(ctrl)
+			tc.setup(uc, ss)
+			handler, err := NewMfaHandler(uc, ss)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			router := gin.New()
+			router.Use(middleware.ErrorMiddleware(), authorizedAs("fulan1234"))
+			router.POST("/mfa/enroll", handler.enrollHandler)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/mfa/enroll", nil))
+			checkProblem(t, rec, tc.wantStatus, tc.wantCode)
+		})
+	}
+}
+
+func TestConfirmMfaHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name       string
+		body       string
+		setup      func(uc *mockusecase.MockMfaUsecase, ss *mockusecase.MockSessionsUsecase)
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name: "confirmed",
+			body: `{"code":"123456"}`,
+			setup: func(uc *mockusecase.MockMfaUsecase, ss *mockusecase.MockSessionsUsecase) {
+				ss.EXPECT().LastSession(gomock.Any()).Return(dto.SessionResponse{}, nil)
+				uc.EXPECT().Confirm(gomock.Any(), dto.ConfirmMfaRequest{Username: "fulan1234", Code: "123456"}).
+					Return(dto.ConfirmMfaResponse{RecoveryCodes: []string{"abcde-fghij"}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "malformed code",
+			body:       `{"code":"12345"}`,
+			setup:      func(uc *mockusecase.MockMfaUsecase, ss *mockusecase.MockSessionsUsecase) {},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_request",
+		},
+		{
+			name: "wrong code",
+			body: `{"code":"123456"}`,
+			setup: func(uc *mockusecase.MockMfaUsecase, ss *mockusecase.MockSessionsUsecase) {
+				ss.EXPECT().LastSession(gomock.Any()).Return(dto.SessionResponse{}, nil)
+				uc.EXPECT().Confirm(gomock.Any(), gomock.Any()).Return(dto.ConfirmMfaResponse{}, usecase.ErrInvalidMfaCode)
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   "invalid_mfa_code",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mockusecase.NewMockMfaUsecase(ctrl)
+			ss := mockusecase.NewMockSessionsUsecase(ctrl)
+			tc.setup(uc, ss)
+			handler, err := NewMfaHandler(uc, ss)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			router := gin.New()
+			router.Use(middleware.ErrorMiddleware(), authorizedAs("fulan1234"))
+			router.POST("/mfa/confirm", handler.confirmHandler)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/mfa/confirm", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(rec, req)
+			checkProblem(t, rec, tc.wantStatus, tc.wantCode)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package delivery
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/terajari/bank-api/apperror"
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/middleware"
+	"github.com/terajari/bank-api/token"
+	"github.com/terajari/bank-api/usecase"
+)
+
+type PayeesHandler struct {
+	usecase         usecase.PayeesUsecase
+	sessionsUsecase usecase.SessionsUsecase
+}
+
+func NewPayeesHandler(uc usecase.PayeesUsecase, ss usecase.SessionsUsecase) (*PayeesHandler, error) {
+	return &PayeesHandler{
+		usecase:         uc,
+		sessionsUsecase: ss,
+	}, nil
+}
+
+func (p *PayeesHandler) createHandler(ctx *gin.Context) {
+	var req dto.CreatePayeeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	ls, err := p.sessionsUsecase.LastSession(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	if ls.IsBlocked {
+		ctx.Error(apperror.ErrSessionBlocked)
+		return
+	}
+
+	authPayload := ctx.MustGet(middleware.AuthorizationPayloadKey).(*token.Payload)
+	req.Owner = authPayload.Username
+
+	resp, err := p.usecase.CreatePayee(ctx, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (p *PayeesHandler) getHandler(ctx *gin.Context) {
+	var uri dto.PayeeRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	ls, err := p.sessionsUsecase.LastSession(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	if ls.IsBlocked {
+		ctx.Error(apperror.ErrSessionBlocked)
+		return
+	}
+
+	authPayload := ctx.MustGet(middleware.AuthorizationPayloadKey).(*token.Payload)
+
+	resp, err := p.usecase.GetPayee(ctx, uri.Id, authPayload.Username)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (p *PayeesHandler) listHandler(ctx *gin.Context) {
+	var req dto.ListPayeesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	ls, err := p.sessionsUsecase.LastSession(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	if ls.IsBlocked {
+		ctx.Error(apperror.ErrSessionBlocked)
+		return
+	}
+
+	authPayload := ctx.MustGet(middleware.AuthorizationPayloadKey).(*token.Payload)
+	req.Owner = authPayload.Username
+
+	resp, err := p.usecase.ListPayees(ctx, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (p *PayeesHandler) updateHandler(ctx *gin.Context) {
+	var uri dto.PayeeRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+	var req dto.UpdatePayeeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	ls, err := p.sessionsUsecase.LastSession(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	if ls.IsBlocked {
+		ctx.Error(apperror.ErrSessionBlocked)
+		return
+	}
+
+	authPayload := ctx.MustGet(middleware.AuthorizationPayloadKey).(*token.Payload)
+	req.Id = uri.Id
+	req.Owner = authPayload.Username
+
+	resp, err := p.usecase.UpdatePayee(ctx, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (p *PayeesHandler) deleteHandler(ctx *gin.Context) {
+	var uri dto.PayeeRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	ls, err := p.sessionsUsecase.LastSession(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	if ls.IsBlocked {
+		ctx.Error(apperror.ErrSessionBlocked)
+		return
+	}
+
+	authPayload := ctx.MustGet(middleware.AuthorizationPayloadKey).(*token.Payload)
+
+	if err := p.usecase.DeletePayee(ctx, uri.Id, authPayload.Username); err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
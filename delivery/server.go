@@ -1,10 +1,16 @@
 package delivery
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 	"github.com/terajari/bank-api/manager"
+	"github.com/terajari/bank-api/metrics"
 	"github.com/terajari/bank-api/middleware"
 	"github.com/terajari/bank-api/token"
 	"github.com/terajari/bank-api/utils"
@@ -16,40 +22,71 @@ type Server struct {
 	TransferHandler *TransferHandler
 	UsersHandler    *UsersHandler
 	SessionsHandler *SessionsHandler
+	MfaHandler      *MfaHandler
+	AdminHandler    *AdminHandler
+	PayeesHandler   *PayeesHandler
+	HealthHandler   *HealthHandler
 	UsecaseManager  *manager.UsecaseManager
 	Router          *gin.Engine
 	Config          utils.Config
 	TokenMaker      token.Maker
+
+	mu         sync.Mutex
+	httpServer *http.Server
+	stopped    bool
 }
 
 func NewServer(config utils.Config, usecase manager.UsecaseManager) (*Server, error) {
-	tokenMaker, err := token.NewJWTMaker(config.TokenSymmtricKey)
+	tokenMaker := usecase.TokenMaker()
+
+	sessionsHandler, err := NewSessionHandler(usecase.SessionsUsecase())
 	if err != nil {
 		return nil, err
 	}
 
-	sessionsHandler, err := NewSessionHandler(usecase.SessionsUsecase(), tokenMaker, config)
+	accHandler, err := NewAccountsHandler(usecase.AccountsUsecase(), usecase.SessionsUsecase())
 	if err != nil {
 		return nil, err
 	}
 
-	accHandler, err := NewAccountsHandler(usecase.AccountsUsecase(), usecase.SessionsUsecase())
+	trfHandler, err := NewTransferHandler(usecase.TransferUsecase(), usecase.AccountsUsecase(), usecase.SessionsUsecase(), usecase.MfaUsecase(), usecase.BatchUsecase(), &config)
+	if err != nil {
+		return nil, err
+	}
+
+	usersHandler, err := NewUsersHandler(usecase.UsersUsecase(), usecase.SessionsUsecase(), usecase.MfaUsecase(), tokenMaker, &config)
 	if err != nil {
 		return nil, err
 	}
 
-	trfHandler, err := NewTransferHandler(usecase.TransferUsecase(), usecase.AccountsUsecase(), usecase.SessionsUsecase())
+	mfaHandler, err := NewMfaHandler(usecase.MfaUsecase(), usecase.SessionsUsecase())
 	if err != nil {
 		return nil, err
 	}
 
-	usersHandler, err := NewUsersHandler(usecase.UsersUsecase(), usecase.SessionsUsecase(), tokenMaker, &config)
+	adminHandler, err := NewAdminHandler(usecase.TransferUsecase(), usecase.UsersUsecase(), usecase.SessionsUsecase())
+	if err != nil {
+		return nil, err
+	}
+
+	payeesHandler, err := NewPayeesHandler(usecase.PayeesUsecase(), usecase.SessionsUsecase())
+	if err != nil {
+		return nil, err
+	}
+
+	healthHandler, err := NewHealthHandler(usecase.HealthUsecase())
+	if err != nil {
+		return nil, err
+	}
+
+	passwordPolicy, err := utils.NewPasswordPolicy(config.PasswordMinLength, config.PasswordMaxLength, config.PasswordBreachedList)
 	if err != nil {
 		return nil, err
 	}
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		v.RegisterValidation("currency", validators.ValidCurrency)
+		v.RegisterValidation("password", validators.Password(passwordPolicy))
 	}
 
 	server := &Server{
@@ -57,8 +94,12 @@ func NewServer(config utils.Config, usecase manager.UsecaseManager) (*Server, er
 		TransferHandler: trfHandler,
 		UsersHandler:    usersHandler,
 		SessionsHandler: sessionsHandler,
+		MfaHandler:      mfaHandler,
+		AdminHandler:    adminHandler,
+		PayeesHandler:   payeesHandler,
+		HealthHandler:   healthHandler,
 		UsecaseManager:  &usecase,
-		Router:          gin.Default(),
+		Router:          gin.New(),
 		Config:          config,
 		TokenMaker:      tokenMaker,
 	}
@@ -66,21 +107,101 @@ func NewServer(config utils.Config, usecase manager.UsecaseManager) (*Server, er
 }
 
 func (s *Server) SetupRouter() {
-	router := gin.Default()
+	router := gin.New()
+	// Handlers pass the *gin.Context on as ctx; the fallback makes the values
+	// of the request context, such as its logger, visible through it.
+	router.ContextWithFallback = true
+	router.Use(middleware.RequestID(), middleware.Tracing(), middleware.AccessLog(), middleware.Recovery())
+	router.Use(middleware.MetricsMiddleware(), middleware.ErrorMiddleware())
+	if s.Config.HTTPMaxBodyBytes > 0 {
+		router.Use(middleware.BodyLimit(s.Config.HTTPMaxBodyBytes))
+	}
+	router.GET("/healthz", s.HealthHandler.livenessHandler)
+	router.GET("/readyz", s.HealthHandler.readinessHandler)
+	router.GET("/version", s.HealthHandler.versionHandler)
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
 	router.POST("/user", s.UsersHandler.createHandler)
 	router.POST("/user/login", s.UsersHandler.loginHandler)
+	router.POST("/user/login/mfa", s.UsersHandler.loginMfaHandler)
 	router.POST("/token/renew", s.SessionsHandler.renewHandler)
 
 	authRoute := router.Group("/").Use(middleware.AuthMiddleware(s.TokenMaker))
 	authRoute.POST("/account", s.AccountsHandler.createHandler)
 	authRoute.GET("/account/:id", s.AccountsHandler.getHandler)
+	authRoute.PATCH("/account/:id", s.AccountsHandler.updateHandler)
 	authRoute.GET("/account/", s.AccountsHandler.listHandlers)
+	authRoute.GET("/account/:id/statement", s.AccountsHandler.statementHandler)
 	authRoute.POST("/user/logout", s.UsersHandler.logoutHandler)
+	authRoute.POST("/user/mfa/enroll", s.MfaHandler.enrollHandler)
+	authRoute.POST("/user/mfa/confirm", s.MfaHandler.confirmHandler)
+
+	authRoute.POST("/payee", s.PayeesHandler.createHandler)
+	authRoute.GET("/payee/", s.PayeesHandler.listHandler)
+	authRoute.GET("/payee/:id", s.PayeesHandler.getHandler)
+	authRoute.PATCH("/payee/:id", s.PayeesHandler.updateHandler)
+	authRoute.DELETE("/payee/:id", s.PayeesHandler.deleteHandler)
 
 	authRoute.POST("/transfer", s.TransferHandler.performTransfer)
+	authRoute.GET("/transfer/", s.TransferHandler.listTransfers)
+	authRoute.POST("/transfer/preview", s.TransferHandler.previewTransfer)
+	authRoute.POST("/transfer/batch", s.TransferHandler.batchTransfer)
+	authRoute.GET("/transfer/batch/:id", s.TransferHandler.batchStatus)
+	authRoute.POST("/hold", s.TransferHandler.authorizeHold)
+	authRoute.GET("/hold/:id", s.TransferHandler.getHold)
+	authRoute.POST("/hold/:id/capture", s.TransferHandler.captureHold)
+	authRoute.POST("/hold/:id/void", s.TransferHandler.voidHold)
+
+	adminRoute := router.Group("/admin").Use(middleware.AuthMiddleware(s.TokenMaker), middleware.AdminMiddleware(s.AdminHandler.usersUsecase))
+	adminRoute.GET("/transfers/pending", s.AdminHandler.pendingTransfersHandler)
+	adminRoute.POST("/transfers/:id/approve", s.AdminHandler.approveTransferHandler)
+	adminRoute.POST("/transfers/:id/reject", s.AdminHandler.rejectTransferHandler)
 	s.Router = router
 }
 
+// Start serves the router on address until Shutdown is called. It returns
+// nil after a shutdown and the listener's error otherwise.
 func (s *Server) Start(address string) error {
-	return s.Router.Run(address)
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return nil
+	}
+	s.httpServer = &http.Server{
+		Addr:              address,
+		Handler:           s.Router,
+		ReadTimeout:       s.Config.HTTPReadTimeout,
+		ReadHeaderTimeout: s.Config.HTTPReadHeaderTimeout,
+		WriteTimeout:      s.Config.HTTPWriteTimeout,
+		IdleTimeout:       s.Config.HTTPIdleTimeout,
+		MaxHeaderBytes:    s.Config.HTTPMaxHeaderBytes,
+	}
+	httpServer := s.httpServer
+	s.mu.Unlock()
+
+	if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Drain makes the readiness check fail so that the load balancer stops
+// sending traffic before Shutdown closes the listener.
+func (s *Server) Drain() {
+	if s.HealthHandler != nil {
+		s.HealthHandler.draining.Store(true)
+	}
+}
+
+// Shutdown stops accepting connections and waits for in-flight requests,
+// running transfers included, until ctx is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.stopped = true
+	httpServer := s.httpServer
+	s.mu.Unlock()
+
+	if httpServer == nil {
+		return nil
+	}
+	return httpServer.Shutdown(ctx)
 }
@@ -0,0 +1,95 @@
+package delivery
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestServerShutdownDrainsRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := l.Addr().String()
+	l.Close()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	router := gin.New()
+	router.GET("/slow", func(ctx *gin.Context) {
+		close(started)
+		<-release
+		ctx.Status(http.StatusOK)
+	})
+	server := &Server{Router: router}
+
+	startErr := make(chan error, 1)
+	go func() { startErr <- server.Start(address) }()
+
+	respStatus := make(chan int, 1)
+	go func() {
+		for {
+			resp, err := http.Get("http://" + address + "/slow")
+			if err != nil {
+				time.Sleep(10 * time.Millisecond)
+				continue
+			}
+			resp.Body.Close()
+			respStatus <- resp.StatusCode
+			return
+		}
+	}()
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request did not reach the server")
+	}
+
+	shutdownErr := make(chan error, 1)
+	go func() { shutdownErr <- server.Shutdown(context.Background()) }()
+	select {
+	case err := <-shutdownErr:
+		t.Fatalf("Shutdown() returned %v before the request finished", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if status := <-respStatus; status != http.StatusOK {
+		t.Errorf("status = %d, want %d", status, http.StatusOK)
+	}
+	if err := <-shutdownErr; err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+	if err := <-startErr; err != nil {
+		t.Errorf("Start() error = %v", err)
+	}
+}
+
+func TestServerShutdownBeforeStart(t *testing.T) {
+	server := &Server{Router: gin.New()}
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if err := server.Start("127.0.0.1:0"); err != nil {
+		t.Errorf("Start() error = %v", err)
+	}
+}
+
+func TestServerStartReturnsListenError(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	server := &Server{Router: gin.New()}
+	if err := server.Start(l.Addr().String()); err == nil {
+		t.Fatal("Start() error = nil on an address in use")
+	}
+}
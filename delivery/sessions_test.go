@@ -0,0 +1,154 @@
+package delivery
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/middleware"
+	mockusecase "github.com/terajari/bank-api/mock/usecase"
+	"github.com/terajari/bank-api/token"
+	"github.com/terajari/bank-api/usecase"
+)
+
+func TestRenewHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	expires := time.Date(2023, 11, 8, 10, 20, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name       string
+		body       string
+		setup      func(uc *mockusecase.MockSessionsUsecase)
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name: "renewed",
+			body: `{"refresh_token":"refresh"}`,
+			setup: func(uc *mockusecase.MockSessionsUsecase) {
+				uc.EXPECT().RenewAccessToken(gomock.Any(), dto.RenewAccessTokenRequest{RefreshToken: "refresh"}).
+					Return(dto.RenewAccessTokenResponse{AccessToken: "access", AccessTokenExpiresAt: expires}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "missing refresh token",
+			body:       `{}`,
+			setup:      func(uc *mockusecase.MockSessionsUsecase) {},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_request",
+		},
+		{
+			name:       "malformed body",
+			body:       `{"refresh_token":`,
+			setup:      func(uc *mockusecase.MockSessionsUsecase) {},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_request",
+		},
+		{
+			name: "invalid token",
+			body: `{"refresh_token":"bad"}`,
+			setup: func(uc *mockusecase.MockSessionsUsecase) {
+				uc.EXPECT().RenewAccessToken(gomock.Any(), gomock.Any()).Return(dto.RenewAccessTokenResponse{}, token.ErrInvalidToken)
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   "invalid_token",
+		},
+		{
+			name: "expired token",
+			body: `{"refresh_token":"refresh"}`,
+			setup: func(uc *mockusecase.MockSessionsUsecase) {
+				uc.EXPECT().RenewAccessToken(gomock.Any(), gomock.Any()).Return(dto.RenewAccessTokenResponse{}, token.ErrExpiredToken)
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   "token_expired",
+		},
+		{
+			name: "session not found",
+			body: `{"refresh_token":"refresh"}`,
+			setup: func(uc *mockusecase.MockSessionsUsecase) {
+				uc.EXPECT().RenewAccessToken(gomock.Any(), gomock.Any()).Return(dto.RenewAccessTokenResponse{}, usecase.ErrSessionNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+			wantCode:   "session_not_found",
+		},
+		{
+			name: "blocked session",
+			body: `{"refresh_token":"refresh"}`,
+			setup: func(uc *mockusecase.MockSessionsUsecase) {
+				uc.EXPECT().RenewAccessToken(gomock.Any(), gomock.Any()).Return(dto.RenewAccessTokenResponse{}, usecase.ErrSessionRevoked)
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   "session_revoked",
+		},
+		{
+			name: "expired session",
+			body: `{"refresh_token":"refresh"}`,
+			setup: func(uc *mockusecase.MockSessionsUsecase) {
+				uc.EXPECT().RenewAccessToken(gomock.Any(), gomock.Any()).Return(dto.RenewAccessTokenResponse{}, usecase.ErrSessionExpired)
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   "session_expired",
+		},
+		{
+			name: "internal error",
+			body: `{"refresh_token":"refresh"}`,
+			setup: func(uc *mockusecase.MockSessionsUsecase) {
+				uc.EXPECT().RenewAccessToken(gomock.Any(), gomock.Any()).Return(dto.RenewAccessTokenResponse{}, errors.New("connection reset"))
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   "internal_error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mockusecase.NewMockSessionsUsecase(ctrl)
+			tc.setup(uc)
+			handler, err := NewSessionHandler(uc)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			router := gin.New()
+			router.Use(middleware.ErrorMiddleware())
+			router.POST("/token/renew", handler.renewHandler)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/token/renew", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tc.wantStatus, rec.Body.String())
+			}
+			if tc.wantCode == "" {
+				var got dto.RenewAccessTokenResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+					t.Fatal(err)
+				}
+				if got.AccessToken != "access" || !got.AccessTokenExpiresAt.Equal(expires) {
+					t.Errorf("response = %+v", got)
+				}
+				return
+			}
+			var problem middleware.Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+				t.Fatal(err)
+			}
+			if problem.Code != tc.wantCode {
+				t.Errorf("code = %q, want %q", problem.Code, tc.wantCode)
+			}
+		})
+	}
+}
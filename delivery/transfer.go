@@ -1,45 +1,52 @@
 package delivery
 
 import (
-	"database/sql"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/terajari/bank-api/apperror"
 	"github.com/terajari/bank-api/dto"
 	"github.com/terajari/bank-api/middleware"
 	"github.com/terajari/bank-api/model"
 	"github.com/terajari/bank-api/token"
 	"github.com/terajari/bank-api/usecase"
+	"github.com/terajari/bank-api/utils"
 )
 
 type TransferHandler struct {
 	transferUsecase usecase.TransferUsecase
 	accountUsecase  usecase.AccountsUsecase
 	sessionsUsecase usecase.SessionsUsecase
+	mfaUsecase      usecase.MfaUsecase
+	batchUsecase    usecase.BatchUsecase
+	config          *utils.Config
 }
 
-func NewTransferHandler(tu usecase.TransferUsecase, au usecase.AccountsUsecase, su usecase.SessionsUsecase) (*TransferHandler, error) {
+func NewTransferHandler(tu usecase.TransferUsecase, au usecase.AccountsUsecase, su usecase.SessionsUsecase, mu usecase.MfaUsecase, bu usecase.BatchUsecase, cfg *utils.Config) (*TransferHandler, error) {
 	return &TransferHandler{
 		transferUsecase: tu,
 		accountUsecase:  au,
 		sessionsUsecase: su,
+		mfaUsecase:      mu,
+		batchUsecase:    bu,
+		config:          cfg,
 	}, nil
 }
 
 func (t *TransferHandler) performTransfer(ctx *gin.Context) {
 	var req dto.MakeTransferRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
 		return
 	}
 
 	ls, err := t.sessionsUsecase.LastSession(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 	if ls.IsBlocked {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "you must log in first"})
+		ctx.Error(apperror.ErrSessionBlocked)
 		return
 	}
 
@@ -50,18 +57,104 @@ func (t *TransferHandler) performTransfer(ctx *gin.Context) {
 
 	authPayload := ctx.MustGet(middleware.AuthorizationPayloadKey).(*token.Payload)
 	if sender.Owner != authPayload.Username {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "sender is not authorized to transfer"})
+		ctx.Error(usecase.ErrNotAccountOwner)
 		return
 	}
 
-	_, ok = t.validAccount(ctx, req.ReceiverId, req.Currency)
-	if !ok {
+	// A payee is resolved and checked by the usecase.
+	if req.PayeeId == "" {
+		_, ok = t.validAccount(ctx, req.ReceiverId, req.Currency)
+		if !ok {
+			return
+		}
+	}
+
+	if !t.stepUp(ctx, authPayload.Username, req) {
 		return
 	}
 
+	req.Owner = authPayload.Username
 	resp, err := t.transferUsecase.MakeTransfer(ctx, req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
+		return
+	}
+	if resp.Transfer.Status == model.TransferStatusPendingReview {
+		ctx.JSON(http.StatusAccepted, resp)
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (t *TransferHandler) previewTransfer(ctx *gin.Context) {
+	var req dto.TransferQuoteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	ls, err := t.sessionsUsecase.LastSession(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	if ls.IsBlocked {
+		ctx.Error(apperror.ErrSessionBlocked)
+		return
+	}
+
+	sender, ok := t.validAccount(ctx, req.SenderId, req.Currency)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(middleware.AuthorizationPayloadKey).(*token.Payload)
+	if sender.Owner != authPayload.Username {
+		ctx.Error(usecase.ErrNotAccountOwner)
+		return
+	}
+
+	resp, err := t.transferUsecase.QuoteTransfer(ctx, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (t *TransferHandler) listTransfers(ctx *gin.Context) {
+	var req dto.TransferHistoryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	ls, err := t.sessionsUsecase.LastSession(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	if ls.IsBlocked {
+		ctx.Error(apperror.ErrSessionBlocked)
+		return
+	}
+
+	acc, err := t.accountUsecase.GetAccount(ctx, req.AccountId)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	authPayload := ctx.MustGet(middleware.AuthorizationPayloadKey).(*token.Payload)
+	if acc.Owner != authPayload.Username {
+		ctx.Error(apperror.ErrForbidden)
+		return
+	}
+
+	req.AccountId = acc.Id
+	resp, err := t.transferUsecase.ListTransfers(ctx, req)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusOK, resp)
@@ -70,16 +163,12 @@ func (t *TransferHandler) performTransfer(ctx *gin.Context) {
 func (a *TransferHandler) validAccount(ctx *gin.Context, accId, currency string) (model.Accounts, bool) {
 	acc, err := a.accountUsecase.GetAccount(ctx, accId)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
-			return model.Accounts{}, false
-		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return model.Accounts{}, false
 	}
 
 	if acc.Currency != currency {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid currency"})
+		ctx.Error(usecase.ErrInvalidCurrency)
 		return model.Accounts{}, false
 	}
 
@@ -88,3 +177,32 @@ func (a *TransferHandler) validAccount(ctx *gin.Context, accId, currency string)
 		Owner: acc.Owner,
 	}, true
 }
+
+// stepUp requires a fresh TOTP code for transfers at or above the configured
+// amount when the sender has enrolled MFA.
+func (t *TransferHandler) stepUp(ctx *gin.Context, username string, req dto.MakeTransferRequest) bool {
+	if t.config.MfaStepUpAmount <= 0 || req.Amount < t.config.MfaStepUpAmount {
+		return true
+	}
+
+	enabled, err := t.mfaUsecase.IsEnabled(ctx, username)
+	if err != nil {
+		ctx.Error(err)
+		return false
+	}
+	if !enabled {
+		return true
+	}
+
+	if req.TotpCode == "" {
+		ctx.Error(usecase.ErrInvalidMfaCode.Withf("totp code is required for this transfer"))
+		return false
+	}
+
+	err = t.mfaUsecase.VerifyStepUp(ctx, username, req.TotpCode)
+	if err != nil {
+		ctx.Error(err)
+		return false
+	}
+	return true
+}
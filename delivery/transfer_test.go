@@ -0,0 +1,111 @@
+package delivery
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/middleware"
+	mockusecase "github.com/terajari/bank-api/mock/usecase"
+	"github.com/terajari/bank-api/usecase"
+	"github.com/terajari/bank-api/utils"
+)
+
+func TestStepUp(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name       string
+		amount     int64
+		totpCode   string
+		setup      func(mfa *mockusecase.MockMfaUsecase)
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "below step-up amount",
+			amount:     999,
+			setup:      func(mfa *mockusecase.MockMfaUsecase) {},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:   "mfa not enabled",
+			amount: 1000,
+			setup: func(mfa *mockusecase.MockMfaUsecase) {
+				mfa.EXPECT().IsEnabled(gomock.Any(), "fulan1234").Return(false, nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:   "missing code",
+			amount: 1000,
+			setup: func(mfa *mockusecase.MockMfaUsecase) {
+				mfa.EXPECT().IsEnabled(gomock.Any(), "fulan1234").Return(true, nil)
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   "invalid_mfa_code",
+		},
+		{
+			name:     "totp code",
+			amount:   1000,
+			totpCode: "123456",
+			setup: func(mfa *mockusecase.MockMfaUsecase) {
+				mfa.EXPECT().IsEnabled(gomock.Any(), "fulan1234").Return(true, nil)
+				mfa.EXPECT().VerifyStepUp(gomock.Any(), "fulan1234", "123456").Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:     "recovery code",
+			amount:   1000,
+			totpCode: "abcde-fghij",
+			setup: func(mfa *mockusecase.MockMfaUsecase) {
+				mfa.EXPECT().IsEnabled(gomock.Any(), "fulan1234").Return(true, nil)
+				mfa.EXPECT().VerifyStepUp(gomock.Any(), "fulan1234", "abcde-fghij").Return(usecase.ErrInvalidMfaCode)
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   "invalid_mfa_code",
+		},
+		{
+			name:     "locked out",
+			amount:   1000,
+			totpCode: "123456",
+			setup: func(mfa *mockusecase.MockMfaUsecase) {
+				mfa.EXPECT().IsEnabled(gomock.Any(), "fulan1234").Return(true, nil)
+				mfa.EXPECT().VerifyStepUp(gomock.Any(), "fulan1234", "123456").Return(usecase.ErrMfaLocked)
+			},
+			wantStatus: http.StatusTooManyRequests,
+			wantCode:   "mfa_locked",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mfa := mockusecase.NewMockMfaUsecase(ctrl)
+			tc.setup(mfa)
+			handler, err := NewTransferHandler(nil, nil, nil, mfa, nil, &utils.Config{MfaStepUpAmount: 1000})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			router := gin.New()
+			router.Use(middleware.ErrorMiddleware())
+			router.POST("/transfers", func(ctx *gin.Context) {
+				req := dto.MakeTransferRequest{Amount: tc.amount, TotpCode: tc.totpCode}
+				if handler.stepUp(ctx, "fulan1234", req) {
+					ctx.Status(http.StatusNoContent)
+				}
+			})
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/transfers", nil))
+			checkProblem(t, rec, tc.wantStatus, tc.wantCode)
+		})
+	}
+}
@@ -5,8 +5,9 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/lib/pq"
+	"github.com/terajari/bank-api/apperror"
 	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/model"
 	"github.com/terajari/bank-api/token"
 	"github.com/terajari/bank-api/usecase"
 	"github.com/terajari/bank-api/utils"
@@ -15,14 +16,16 @@ import (
 type UsersHandler struct {
 	usecase    usecase.UsersUsecase
 	sessions   usecase.SessionsUsecase
+	mfa        usecase.MfaUsecase
 	tokenMaker token.Maker
 	config     *utils.Config
 }
 
-func NewUsersHandler(uc usecase.UsersUsecase, ss usecase.SessionsUsecase, token token.Maker, cfg *utils.Config) (*UsersHandler, error) {
+func NewUsersHandler(uc usecase.UsersUsecase, ss usecase.SessionsUsecase, mfa usecase.MfaUsecase, token token.Maker, cfg *utils.Config) (*UsersHandler, error) {
 	return &UsersHandler{
 		usecase:    uc,
 		sessions:   ss,
+		mfa:        mfa,
 		tokenMaker: token,
 		config:     cfg,
 	}, nil
@@ -31,22 +34,13 @@ func NewUsersHandler(uc usecase.UsersUsecase, ss usecase.SessionsUsecase, token
 func (u *UsersHandler) createHandler(ctx *gin.Context) {
 	var req dto.CreateUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
 		return
 	}
 
 	response, err := u.usecase.CreateUser(ctx, req)
 	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok {
-			switch pqErr.Code.Name() {
-			case "foreign_key_violation", "unique_violation":
-				ctx.JSON(http.StatusForbidden, gin.H{
-					"error": errors.New(pqErr.Message),
-				})
-				return
-			}
-		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusCreated, response)
@@ -55,25 +49,83 @@ func (u *UsersHandler) createHandler(ctx *gin.Context) {
 func (u *UsersHandler) loginHandler(ctx *gin.Context) {
 	var req dto.LoginUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
 		return
 	}
 
 	user, err := u.usecase.Login(ctx, req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
+		return
+	}
+
+	mfaEnabled, err := u.mfa.IsEnabled(ctx, user.Username)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	if mfaEnabled {
+		mfaToken, mfaPayload, err := u.tokenMaker.CreateChallengeToken(user.Username, u.config.MfaChallengeDuration)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, dto.LoginMfaChallengeResponse{
+			MfaRequired:       true,
+			MfaToken:          mfaToken,
+			MfaTokenExpiresAt: mfaPayload.ExpiredAt,
+		})
 		return
 	}
 
-	accessToken, Accesspayload, err := u.tokenMaker.CreateToken(req.Username, u.config.AccessTokenDuration)
+	u.startSession(ctx, user)
+}
+
+func (u *UsersHandler) loginMfaHandler(ctx *gin.Context) {
+	var req dto.LoginMfaRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	payload, err := u.tokenMaker.VerifyToken(req.MfaToken)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	if payload.Purpose != token.PurposeMfaChallenge {
+		ctx.Error(token.ErrInvalidToken)
+		return
+	}
+
+	err = u.mfa.VerifyChallenge(ctx, payload, req.Code)
+	if err != nil {
+		if errors.Is(err, usecase.ErrMfaNotEnabled) {
+			err = usecase.ErrInvalidMfaCode
+		}
+		ctx.Error(err)
+		return
+	}
+
+	user, err := u.usecase.GetUser(ctx, payload.Username)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	u.startSession(ctx, user)
+}
+
+func (u *UsersHandler) startSession(ctx *gin.Context, user model.Users) {
+	accessToken, Accesspayload, err := u.tokenMaker.CreateToken(user.Username, u.config.AccessTokenDuration)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
 	refreshToken, refreshPayload, err := u.tokenMaker.CreateToken(user.Username, u.config.RefreshTokenDuration)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -87,7 +139,7 @@ func (u *UsersHandler) loginHandler(ctx *gin.Context) {
 		ExpiresAt:    refreshPayload.ExpiredAt,
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -95,6 +147,7 @@ func (u *UsersHandler) loginHandler(ctx *gin.Context) {
 		Username:     user.Username,
 		FullName:     user.FullName,
 		Email:        user.Email,
+		Role:         user.Role,
 		PwdChangedAt: user.PasswordChangedAt,
 		CreatedAt:    user.CreatedAt,
 	}
@@ -114,12 +167,12 @@ func (u *UsersHandler) loginHandler(ctx *gin.Context) {
 func (u *UsersHandler) logoutHandler(ctx *gin.Context) {
 	ls, err := u.sessions.LastSession(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
 	if ls.IsBlocked {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "you already logged out"})
+		ctx.Error(apperror.ErrSessionBlocked.Withf("you already logged out"))
 		return
 	}
 
@@ -128,7 +181,7 @@ func (u *UsersHandler) logoutHandler(ctx *gin.Context) {
 		IsBlocked: true,
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -0,0 +1,139 @@
+package delivery
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/middleware"
+	mockusecase "github.com/terajari/bank-api/mock/usecase"
+	"github.com/terajari/bank-api/model"
+	"github.com/terajari/bank-api/token"
+	"github.com/terajari/bank-api/usecase"
+	"github.com/terajari/bank-api/utils"
+)
+
+func TestLoginMfaHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	maker, err := token.NewJWTMaker(strings.Repeat("k", token.MinSecretKeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	challenge, payload, err := maker.CreateChallengeToken("fulan1234", 5*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	access, _, err := maker.CreateToken("fulan1234", 5*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type mocks struct {
+		users    *mockusecase.MockUsersUsecase
+		sessions *mockusecase.MockSessionsUsecase
+		mfa      *mockusecase.MockMfaUsecase
+	}
+
+	testCases := []struct {
+		name       string
+		mfaToken   string
+		setup      func(m mocks)
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:     "logged in",
+			mfaToken: challenge,
+			setup: func(m mocks) {
+				m.mfa.EXPECT().VerifyChallenge(gomock.Any(), gomock.Any(), "123456").DoAndReturn(func(_ interface{}, got *token.Payload, _ string) error {
+					if got.ID != payload.ID || got.Username != "fulan1234" {
+						t.Errorf("challenge = %+v, want %+v", got, payload)
+					}
+					return nil
+				})
+				m.users.EXPECT().GetUser(gomock.Any(), "fulan1234").Return(model.Users{Username: "fulan1234"}, nil)
+				m.sessions.EXPECT().AddSessions(gomock.Any(), gomock.Any()).Return(dto.SessionResponse{}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "access token instead of challenge",
+			mfaToken:   access,
+			setup:      func(m mocks) {},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   "invalid_token",
+		},
+		{
+			name:     "wrong code",
+			mfaToken: challenge,
+			setup: func(m mocks) {
+				m.mfa.EXPECT().VerifyChallenge(gomock.Any(), gomock.Any(), "123456").Return(usecase.ErrInvalidMfaCode)
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   "invalid_mfa_code",
+		},
+		{
+			name:     "mfa disabled since the challenge",
+			mfaToken: challenge,
+			setup: func(m mocks) {
+				m.mfa.EXPECT().VerifyChallenge(gomock.Any(), gomock.Any(), "123456").Return(usecase.ErrMfaNotEnabled)
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   "invalid_mfa_code",
+		},
+		{
+			name:     "challenge already used",
+			mfaToken: challenge,
+			setup: func(m mocks) {
+				m.mfa.EXPECT().VerifyChallenge(gomock.Any(), gomock.Any(), "123456").Return(usecase.ErrMfaChallengeUsed)
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   "mfa_challenge_used",
+		},
+		{
+			name:     "locked out",
+			mfaToken: challenge,
+			setup: func(m mocks) {
+				m.mfa.EXPECT().VerifyChallenge(gomock.Any(), gomock.Any(), "123456").Return(usecase.ErrMfaLocked)
+			},
+			wantStatus: http.StatusTooManyRequests,
+			wantCode:   "mfa_locked",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks{
+				users:    mockusecase.NewMockUsersUsecase(ctrl),
+				sessions: mockusecase.NewMockSessionsUsecase(ctrl),
+				mfa:      mockusecase.NewMockMfaUsecase(ctrl),
+			}
+			tc.setup(m)
+			handler, err := NewUsersHandler(m.users, m.sessions, m.mfa, maker, &utils.Config{
+				AccessTokenDuration:  15 * time.Minute,
+				RefreshTokenDuration: 24 * time.Hour,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			router := gin.New()
+			router.Use(middleware.ErrorMiddleware())
+			router.POST("/users/login/mfa", handler.loginMfaHandler)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/users/login/mfa", strings.NewReader(`{"mfa_token":"`+tc.mfaToken+`","code":"123456"}`))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(rec, req)
+			checkProblem(t, rec, tc.wantStatus, tc.wantCode)
+		})
+	}
+}
@@ -7,14 +7,28 @@ import (
 type RegisterNewAccountsRequest struct {
 	Owner    string `json:"owner"`
 	Currency string `json:"currency" binding:"required,currency"`
+	Nickname string `json:"nickname" binding:"max=64"`
+	Type     string `json:"type"`
+}
+
+// OpenFundedAccountRequest opens an account that is funded from the bank's
+// settlement account. It is an operator command, customers cannot bring money
+// into the bank.
+type OpenFundedAccountRequest struct {
+	RegisterNewAccountsRequest
+	InitialDeposit int64 `json:"initial_deposit"`
 }
 
 type RegisterNewAccountsResponse struct {
-	Id        string    `json:"id"`
-	Owner     string    `json:"owner"`
-	Balance   int64     `json:"balance"`
-	Currency  string    `json:"currency"`
-	CreatedAt time.Time `json:"created_at"`
+	Id               string    `json:"id"`
+	Number           string    `json:"number"`
+	Owner            string    `json:"owner"`
+	Balance          int64     `json:"balance"`
+	AvailableBalance int64     `json:"available_balance"`
+	Currency         string    `json:"currency"`
+	Nickname         string    `json:"nickname"`
+	Type             string    `json:"type"`
+	CreatedAt        time.Time `json:"created_at"`
 }
 
 type GetAccountRequest struct {
@@ -22,16 +36,23 @@ type GetAccountRequest struct {
 }
 
 type GetAccountResponse struct {
-	Id       string `json:"id"`
-	Owner    string `json:"owner"`
-	Balance  int64  `json:"balance"`
-	Currency string `json:"currency"`
+	Id               string     `json:"id"`
+	Number           string     `json:"number"`
+	Owner            string     `json:"owner"`
+	Balance          int64      `json:"balance"`
+	AvailableBalance int64      `json:"available_balance"`
+	Currency         string     `json:"currency"`
+	Nickname         string     `json:"nickname"`
+	Type             string     `json:"type"`
+	FrozenAt         *time.Time `json:"frozen_at,omitempty"`
 }
 
 type ListAccountsRequest struct {
-	Owner string `json:"owner"`
-	Page  int    `form:"page"`
-	Size  int    `form:"size"`
+	Owner    string `json:"owner"`
+	Currency string `form:"currency" binding:"omitempty,currency"`
+	Type     string `form:"type"`
+	Page     int    `form:"page"`
+	Size     int    `form:"size"`
 }
 
 type UpdateAccountRequest struct {
@@ -40,8 +61,26 @@ type UpdateAccountRequest struct {
 }
 
 type UpdateAccountResponse struct {
+	Id               string `json:"id"`
+	Number           string `json:"number"`
+	Owner            string `json:"owner"`
+	Balance          int64  `json:"balance"`
+	AvailableBalance int64  `json:"available_balance"`
+	Currency         string `json:"currency"`
+	Nickname         string `json:"nickname"`
+	Type             string `json:"type"`
+}
+
+type UpdateAccountNicknameRequest struct {
 	Id       string `json:"id"`
-	Owner    string `json:"owner"`
-	Balance  int64  `json:"balance"`
-	Currency string `json:"currency"`
+	Nickname string `json:"nickname" binding:"max=64"`
+}
+
+// StatementRequest selects the days From through To, both inclusive and in
+// UTC. Zero dates default to the current month up to today.
+type StatementRequest struct {
+	AccountId string    `json:"-"`
+	Format    string    `form:"format" binding:"omitempty,oneof=csv jsonl camt053"`
+	From      time.Time `form:"from" time_format:"2006-01-02" time_utc:"1"`
+	To        time.Time `form:"to" time_format:"2006-01-02" time_utc:"1"`
 }
@@ -0,0 +1,43 @@
+package dto
+
+type BatchTransferItem struct {
+	ReceiverId string `json:"receiver_id" binding:"required_without=PayeeId,excluded_with=PayeeId"`
+	PayeeId    string `json:"payee_id"`
+	Amount     int64  `json:"amount" binding:"required,gt=0"`
+	TransferDetails
+}
+
+type BatchTransferRequest struct {
+	Owner    string              `json:"-"`
+	JobId    string              `json:"-"`
+	SenderId string              `json:"sender_id" binding:"required"`
+	Currency string              `json:"currency" binding:"required,currency"`
+	Mode     string              `json:"mode" binding:"required,oneof=all_or_nothing best_effort"`
+	Items    []BatchTransferItem `json:"items" binding:"required,min=1,dive"`
+	TotpCode string              `json:"totp_code"`
+}
+
+type BatchItemResult struct {
+	Index      int    `json:"index"`
+	ReceiverId string `json:"receiver_id,omitempty"`
+	PayeeId    string `json:"payee_id,omitempty"`
+	Amount     int64  `json:"amount"`
+	Status     string `json:"status"`
+	TransferId string `json:"transfer_id,omitempty"`
+	TotalFee   int64  `json:"total_fee"`
+	Error      string `json:"error,omitempty"`
+}
+
+type BatchTransferResult struct {
+	Mode      string            `json:"mode"`
+	SenderId  string            `json:"sender_id"`
+	Total     int               `json:"total"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Error     string            `json:"error,omitempty"`
+	Items     []BatchItemResult `json:"items"`
+}
+
+type BatchJobRequest struct {
+	Id string `uri:"id" binding:"required"`
+}
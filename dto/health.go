@@ -0,0 +1,13 @@
+package dto
+
+const (
+	HealthStatusOK      = "ok"
+	HealthStatusFailing = "failing"
+)
+
+// ReadinessResponse reports every readiness check by name. Status is ok only
+// when all checks are.
+type ReadinessResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
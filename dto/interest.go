@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+type InterestAccrualItem struct {
+	AccountId string `json:"account_id"`
+	Currency  string `json:"currency"`
+	Balance   int64  `json:"balance"`
+	RateBps   int64  `json:"rate_bps"`
+	Amount    int64  `json:"amount"`
+	Accrued   bool   `json:"accrued"`
+}
+
+type InterestAccrualReport struct {
+	Date   time.Time             `json:"date"`
+	DryRun bool                  `json:"dry_run"`
+	Totals map[string]int64      `json:"totals"`
+	Items  []InterestAccrualItem `json:"items"`
+}
+
+type InterestPostingItem struct {
+	AccountId  string `json:"account_id"`
+	Currency   string `json:"currency"`
+	Amount     int64  `json:"amount"`
+	Accruals   int    `json:"accruals"`
+	TransferId string `json:"transfer_id,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+type InterestPostingReport struct {
+	Before time.Time             `json:"before"`
+	DryRun bool                  `json:"dry_run"`
+	Totals map[string]int64      `json:"totals"`
+	Items  []InterestPostingItem `json:"items"`
+}
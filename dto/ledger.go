@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/terajari/bank-api/model"
+)
+
+// LedgerReport is the result of a ledger verification. Consistent is true
+// when no check found a problem; CurrencyTotals lists every currency, the
+// other lists only the problems.
+type LedgerReport struct {
+	CheckedAt      time.Time             `json:"checked_at"`
+	DurationMs     int64                 `json:"duration_ms"`
+	Consistent     bool                  `json:"consistent"`
+	BalanceDrifts  []model.BalanceDrift  `json:"balance_drifts"`
+	TransferIssues []model.TransferIssue `json:"transfer_issues"`
+	CurrencyTotals []LedgerCurrencyTotal `json:"currency_totals"`
+}
+
+type LedgerCurrencyTotal struct {
+	model.CurrencyTotal
+	Imbalance int64 `json:"imbalance"`
+}
+
+// Imbalanced returns the currencies whose entries do not sum to zero.
+func (r LedgerReport) Imbalanced() []LedgerCurrencyTotal {
+	var imbalanced []LedgerCurrencyTotal
+	for _, total := range r.CurrencyTotals {
+		if total.Imbalance != 0 {
+			imbalanced = append(imbalanced, total)
+		}
+	}
+	return imbalanced
+}
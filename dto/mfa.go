@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+type EnrollMfaResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningUri string `json:"provisioning_uri"`
+}
+
+type ConfirmMfaRequest struct {
+	Username string `json:"-"`
+	Code     string `json:"code" binding:"required,numeric,len=6"`
+}
+
+type ConfirmMfaResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type LoginMfaRequest struct {
+	MfaToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type LoginMfaChallengeResponse struct {
+	MfaRequired       bool      `json:"mfa_required"`
+	MfaToken          string    `json:"mfa_token"`
+	MfaTokenExpiresAt time.Time `json:"mfa_token_expires_at"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+type CreatePayeeRequest struct {
+	Owner     string `json:"-"`
+	Nickname  string `json:"nickname" binding:"required,max=64"`
+	AccountId string `json:"account_id" binding:"required"`
+	Currency  string `json:"currency" binding:"required,currency"`
+}
+
+type PayeeRequest struct {
+	Id string `uri:"id" binding:"required"`
+}
+
+type UpdatePayeeRequest struct {
+	Id       string `json:"-"`
+	Owner    string `json:"-"`
+	Nickname string `json:"nickname" binding:"required,max=64"`
+}
+
+type ListPayeesRequest struct {
+	Owner string `json:"-"`
+	Page  int    `form:"page"`
+	Size  int    `form:"size"`
+}
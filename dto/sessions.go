@@ -31,9 +31,13 @@ type RenewAccessTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
+// RenewAccessTokenResponse carries a new refresh token only when sliding
+// expiry extended the session.
 type RenewAccessTokenResponse struct {
-	AccessToken          string    `json:"access_token"`
-	AccessTokenExpiresAt time.Time `json:"access_token_expires_at"`
+	AccessToken           string     `json:"access_token"`
+	AccessTokenExpiresAt  time.Time  `json:"access_token_expires_at"`
+	RefreshToken          string     `json:"refresh_token,omitempty"`
+	RefreshTokenExpiresAt *time.Time `json:"refresh_token_expires_at,omitempty"`
 }
 
 type UpdateSessionBlockRequest struct {
@@ -6,15 +6,105 @@ import (
 
 type MakeTransferRequest struct {
 	SenderId   string `json:"sender_id" binding:"required"`
-	ReceiverId string `json:"receiver_id" binding:"required"`
+	ReceiverId string `json:"receiver_id" binding:"required_without=PayeeId,excluded_with=PayeeId"`
+	PayeeId    string `json:"payee_id"`
 	Amount     int64  `json:"amount" binding:"required,gt=0"`
 	Currency   string `json:"currency" binding:"required,currency"`
+	TransferDetails
+	TotpCode string `json:"totp_code"`
+	Owner    string `json:"-"`
+	// TransferId is the ID the transfer gets, a new one when empty. Batch
+	// jobs set it so that a rerun cannot send an item twice.
+	TransferId string `json:"-"`
+}
+
+// TransferDetails are the optional free-form fields of a transfer. The
+// reference fits an ISO 20022 end-to-end identification.
+type TransferDetails struct {
+	Description string            `json:"description" binding:"max=140"`
+	Reference   string            `json:"reference" binding:"max=35"`
+	Metadata    map[string]string `json:"metadata" binding:"max=20,dive,keys,min=1,max=40,endkeys,max=500"`
 }
 
 type MakeTransferResponse struct {
-	Transfer      model.Transfer `json:"transfer"`
-	Sender        model.Accounts `json:"sender"`
-	Receiver      model.Accounts `json:"receiver"`
-	SenderEntry   model.Entries  `json:"sender_entry"`
-	ReceiverEntry model.Entries  `json:"receiver_entry"`
+	Transfer      model.Transfer      `json:"transfer"`
+	Sender        model.Accounts      `json:"sender"`
+	Receiver      model.Accounts      `json:"receiver"`
+	SenderEntry   model.Entries       `json:"sender_entry"`
+	ReceiverEntry model.Entries       `json:"receiver_entry"`
+	Fees          []model.TransferFee `json:"fees"`
+	TotalFee      int64               `json:"total_fee"`
+}
+
+type TransferQuoteRequest struct {
+	SenderId string `json:"sender_id" binding:"required"`
+	Amount   int64  `json:"amount" binding:"required,gt=0"`
+	Currency string `json:"currency" binding:"required,currency"`
+}
+
+type FeeQuote struct {
+	RuleId string `json:"rule_id"`
+	Name   string `json:"name"`
+	Amount int64  `json:"amount"`
+}
+
+type TransferQuoteResponse struct {
+	Amount     int64      `json:"amount"`
+	Currency   string     `json:"currency"`
+	Fees       []FeeQuote `json:"fees"`
+	TotalFee   int64      `json:"total_fee"`
+	TotalDebit int64      `json:"total_debit"`
+}
+
+type TransferHistoryRequest struct {
+	AccountId string `form:"account_id" binding:"required"`
+	Reference string `form:"reference" binding:"max=35"`
+	Page      int    `form:"page"`
+	Size      int    `form:"size"`
+}
+
+// TransferHistoryItem is a transfer as its parties see it, without the risk
+// assessment and review of the bank.
+type TransferHistoryItem struct {
+	ID          string            `json:"id"`
+	SenderId    string            `json:"sender_id"`
+	ReceiverId  string            `json:"receiver_id"`
+	Amount      int64             `json:"amount"`
+	Status      string            `json:"status"`
+	Description string            `json:"description,omitempty"`
+	Reference   string            `json:"reference,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	CreatedAt   string            `json:"created_at"`
+}
+
+type ListTransfersRequest struct {
+	Page int `form:"page"`
+	Size int `form:"size"`
+}
+
+type ReviewTransferRequest struct {
+	Id string `uri:"id" binding:"required"`
+}
+
+type AuthorizeHoldRequest struct {
+	SenderId   string `json:"sender_id" binding:"required"`
+	ReceiverId string `json:"receiver_id" binding:"required"`
+	Amount     int64  `json:"amount" binding:"required,gt=0"`
+	Currency   string `json:"currency" binding:"required,currency"`
+	TtlSeconds int64  `json:"ttl_seconds" binding:"gte=0"`
+	TotpCode   string `json:"totp_code"`
+}
+
+type HoldRequest struct {
+	Id string `uri:"id" binding:"required"`
+}
+
+type CaptureHoldRequest struct {
+	Id     string `json:"-"`
+	Amount int64  `json:"amount" binding:"gte=0"`
+}
+
+type CaptureHoldResponse struct {
+	Hold     model.Hold           `json:"hold"`
+	Transfer MakeTransferResponse `json:"transfer"`
 }
@@ -8,15 +8,19 @@ import (
 
 type CreateUserRequest struct {
 	Username string `json:"username" binding:"required,alphanum"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required,password"`
 	FullName string `json:"full_name" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
+	// Role defaults to customer. It is never bound from a request, admins
+	// are created with the user create command.
+	Role string `json:"-"`
 }
 
 type UserReponse struct {
 	Username     string    `json:"username"`
 	FullName     string    `json:"full_name"`
 	Email        string    `json:"email"`
+	Role         string    `json:"role"`
 	PwdChangedAt time.Time `json:"pwd_changed_at"`
 	CreatedAt    time.Time `json:"created_at"`
 }
@@ -0,0 +1,116 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestParseLevel(t *testing.T) {
+	testCases := []struct {
+		in      string
+		want    slog.Level
+		wantErr bool
+	}{
+		{in: "", want: slog.LevelInfo},
+		{in: "debug", want: slog.LevelDebug},
+		{in: "WARN", want: slog.LevelWarn},
+		{in: "error", want: slog.LevelError},
+		{in: "verbose", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := ParseLevel(tc.in)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseLevel(%q) error = %v, wantErr %v", tc.in, err, tc.wantErr)
+			}
+			if err == nil && got != tc.want {
+				t.Errorf("ParseLevel(%q) = %v, want %v", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := WithContext(context.Background(), New(&buf, slog.LevelInfo))
+	ctx = With(ctx, "request_id", "abc")
+
+	FromContext(ctx).Debug("dropped")
+	FromContext(ctx).Info("kept")
+
+	line := decode(t, &buf)
+	if line["msg"] != "kept" || line["request_id"] != "abc" {
+		t.Errorf("record = %v", line)
+	}
+	if FromContext(context.Background()) != slog.Default() {
+		t.Error("FromContext() without a logger is not the default logger")
+	}
+}
+
+type credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Mfa      struct {
+		Code string `json:"code"`
+	} `json:"mfa"`
+	CreatedAt time.Time `json:"created_at"`
+	internal  string
+}
+
+func TestRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo)
+
+	req := credentials{Username: "fulan1234", Password: "hunter22", CreatedAt: time.Date(2023, 11, 8, 0, 0, 0, 0, time.UTC), internal: "x"}
+	req.Mfa.Code = "123456"
+	logger.Info("login",
+		"request", &req,
+		"refresh_token", "eyJ...",
+		slog.Group("headers", "Authorization", "Bearer eyJ..."),
+		"error_code", "invalid_token",
+		"error", errors.New("boom"),
+		"meta", map[string]any{"secret": "s3cr3t", "note": "ok"},
+	)
+
+	line := decode(t, &buf)
+	request := line["request"].(map[string]any)
+	if request["username"] != "fulan1234" || request["password"] != Redacted {
+		t.Errorf("request = %v", request)
+	}
+	if mfa := request["mfa"].(map[string]any); mfa["code"] != Redacted {
+		t.Errorf("mfa = %v", mfa)
+	}
+	if request["created_at"] != "2023-11-08T00:00:00Z" {
+		t.Errorf("created_at = %v", request["created_at"])
+	}
+	if _, ok := request["internal"]; ok {
+		t.Error("unexported field logged")
+	}
+	if line["refresh_token"] != Redacted {
+		t.Errorf("refresh_token = %v", line["refresh_token"])
+	}
+	if headers := line["headers"].(map[string]any); headers["Authorization"] != Redacted {
+		t.Errorf("headers = %v", headers)
+	}
+	if line["error_code"] != "invalid_token" || line["error"] != "boom" {
+		t.Errorf("record = %v", line)
+	}
+	if meta := line["meta"].(map[string]any); meta["secret"] != Redacted || meta["note"] != "ok" {
+		t.Errorf("meta = %v", meta)
+	}
+}
+
+func decode(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("cannot decode %q: %v", buf.String(), err)
+	}
+	return line
+}
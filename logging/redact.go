@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"encoding"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"strings"
+)
+
+// Redacted replaces the value of sensitive attributes.
+const Redacted = "[REDACTED]"
+
+// sensitiveParts redact any key containing them, e.g. refresh_token or
+// hashed_password.
+var sensitiveParts = []string{"password", "token", "secret", "authorization", "cookie", "totp"}
+
+// sensitiveKeys redact keys that are only sensitive as a whole; "code" is an
+// MFA code in a request but error_code is not.
+var sensitiveKeys = map[string]bool{"code": true, "recovery_codes": true}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	if sensitiveKeys[key] {
+		return true
+	}
+	for _, part := range sensitiveParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactAttr is the ReplaceAttr of the handler. Structs and maps logged with
+// slog.Any are turned into groups keyed by their JSON names, so that the
+// handler calls it again for each of their fields.
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	if isSensitive(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+	if a.Value.Kind() == slog.KindAny {
+		if group, ok := groupOf(a.Value.Any()); ok {
+			return slog.Attr{Key: a.Key, Value: group}
+		}
+	}
+	return a
+}
+
+var (
+	errorType         = reflect.TypeOf((*error)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// groupOf returns v as a group value when it is a struct, a pointer to one or
+// a map with string keys. Values that format themselves, such as errors and
+// time.Time, are left to the handler.
+func groupOf(v any) (slog.Value, bool) {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return slog.Value{}, false
+	}
+	if t := rv.Type(); t.Implements(errorType) || t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		return slog.Value{}, false
+	}
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return slog.Value{}, false
+		}
+		rv = rv.Elem()
+	}
+	if t := rv.Type(); reflect.PointerTo(t).Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return slog.Value{}, false
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		return slog.GroupValue(structAttrs(rv)...), true
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return slog.Value{}, false
+		}
+		attrs := make([]slog.Attr, 0, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			attrs = append(attrs, slog.Any(iter.Key().String(), iter.Value().Interface()))
+		}
+		return slog.GroupValue(attrs...), true
+	}
+	return slog.Value{}, false
+}
+
+func structAttrs(rv reflect.Value) []slog.Attr {
+	t := rv.Type()
+	attrs := make([]slog.Attr, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		value := rv.Field(i)
+		if field.Anonymous && name == "" && value.Kind() == reflect.Struct {
+			attrs = append(attrs, structAttrs(value)...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		attrs = append(attrs, slog.Any(name, value.Interface()))
+	}
+	return attrs
+}
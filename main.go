@@ -1,36 +1,13 @@
 package main
 
 import (
-	"log"
+	"os"
 
-	"github.com/terajari/bank-api/delivery"
-	"github.com/terajari/bank-api/manager"
-	"github.com/terajari/bank-api/utils"
+	"github.com/terajari/bank-api/cmd"
 )
 
 func main() {
-	cfg, err := utils.LoadConfig("./.env")
-	if err != nil {
-		log.Fatal("cannot load config")
+	if err := cmd.Execute(); err != nil {
+		os.Exit(1)
 	}
-
-	infra, err := manager.NewInfraManager(&cfg)
-	if err != nil {
-		log.Fatal(err)
-	}
-	repoManager, err := manager.NewRepositoryManager(infra)
-	if err != nil {
-		log.Fatal(err)
-	}
-	usecaseManager, err := manager.NewUsecaseManager(repoManager)
-	if err != nil {
-		log.Fatal(err)
-	}
-	server, err := delivery.NewServer(cfg, usecaseManager)
-	if err != nil {
-		log.Fatal(err)
-	}
-	server.SetupRouter()
-	server.Start(cfg.HTTPServer)
-
 }
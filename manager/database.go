@@ -0,0 +1,67 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/terajari/bank-api/logging"
+)
+
+// maxConnectBackoff caps the wait between two connection attempts.
+const maxConnectBackoff = 10 * time.Second
+
+// withStatementTimeout sets the statement_timeout run-time parameter, in
+// milliseconds, on every connection opened with source. Postgres then
+// cancels statements running longer. A zero timeout leaves source as is.
+func withStatementTimeout(source string, timeout time.Duration) (string, error) {
+	if timeout <= 0 {
+		return source, nil
+	}
+	ms := strconv.FormatInt(timeout.Milliseconds(), 10)
+	if !strings.Contains(source, "://") {
+		return source + " statement_timeout=" + ms, nil
+	}
+	u, err := url.Parse(source)
+	if err != nil {
+		return "", errors.New("DB_SOURCE is not a valid URL")
+	}
+	query := u.Query()
+	query.Set("statement_timeout", ms)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// pingWithRetry waits for the database to accept connections, retrying with
+// exponential backoff until timeout. A zero timeout pings once. It lets the
+// API start alongside a database that is still booting.
+func pingWithRetry(ctx context.Context, db *sqlx.DB, timeout, backoff time.Duration) error {
+	if timeout <= 0 {
+		return db.PingContext(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	wait := backoff
+	for attempt := 1; ; attempt++ {
+		err := db.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+		logging.FromContext(ctx).Warn("database not reachable, retrying", "attempt", attempt, "retry_in", wait.String(), "error", err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("database not reachable after %d attempts in %s: %w", attempt, timeout, err)
+		case <-timer.C:
+		}
+		wait = min(wait*2, maxConnectBackoff)
+	}
+}
@@ -1,14 +1,19 @@
 package manager
 
 import (
+	"context"
+
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	"github.com/terajari/bank-api/metrics"
+	"github.com/terajari/bank-api/tracing"
 	"github.com/terajari/bank-api/utils"
 )
 
 type InfrastuctureManager interface {
 	Conn() *sqlx.DB
 	Config() *utils.Config
+	Close() error
 }
 
 type infrastuctureManager struct {
@@ -17,14 +22,29 @@ type infrastuctureManager struct {
 }
 
 func (i *infrastuctureManager) initDb() error {
-	driver := i.config.DBDriver
-	source := i.config.DBSource
+	cfg := i.config
+	source, err := withStatementTimeout(cfg.DBSource, cfg.DBStatementTimeout)
+	if err != nil {
+		return err
+	}
 
-	db, err := sqlx.Open(driver, source)
+	// Every statement is traced. sqlx keeps the driver name to pick the bind
+	// variables.
+	sqlDB, err := tracing.OpenDB(cfg.DBDriver, source)
 	if err != nil {
 		return err
 	}
-	if err := db.Ping(); err != nil {
+	db := sqlx.NewDb(sqlDB, cfg.DBDriver)
+	db.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	db.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	db.SetConnMaxLifetime(cfg.DBConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.DBConnMaxIdleTime)
+
+	if err := pingWithRetry(context.Background(), db, cfg.DBConnectTimeout, cfg.DBConnectBackoff); err != nil {
+		db.Close()
+		return err
+	}
+	if err := metrics.RegisterDBStats(db.DB); err != nil {
 		return err
 	}
 	i.db = db
@@ -41,6 +61,12 @@ func (i *infrastuctureManager) Config() *utils.Config {
 	return i.config
 }
 
+// Close closes the connection pool. It must only be called once nothing
+// uses the connection anymore.
+func (i *infrastuctureManager) Close() error {
+	return i.db.Close()
+}
+
 func NewInfraManager(configParam *utils.Config) (InfrastuctureManager, error) {
 	infra := &infrastuctureManager{
 		config: configParam,
@@ -3,39 +3,78 @@ package manager
 import "github.com/terajari/bank-api/repository"
 
 type RepositoryManager interface {
-	AccountsRepo() repository.AccountsRepository
-	EntryRepo() repository.EntryRepository
-	TransferRepo() repository.TransferRepository
-	UsersRepo() repository.UsersRepository
-	SessionsRepo() repository.SessionsRepository
+	repository.Repositories
+	TxManager() TxManager
 }
 
 type repositoryManager struct {
-	infra InfrastuctureManager
+	db repository.DBTX
 }
 
 func (r *repositoryManager) AccountsRepo() repository.AccountsRepository {
-	return repository.NewAccountsRepository(r.infra.Conn())
+	return repository.NewAccountsRepository(r.db)
+}
+
+func (r *repositoryManager) AccountTypesRepo() repository.AccountTypesRepository {
+	return repository.NewAccountTypesRepository(r.db)
 }
 
 func (r *repositoryManager) EntryRepo() repository.EntryRepository {
-	return repository.NewEntryRepository(r.infra.Conn())
+	return repository.NewEntryRepository(r.db)
 }
 
 func (r *repositoryManager) TransferRepo() repository.TransferRepository {
-	return repository.NewTransferRepository(r.infra.Conn())
+	return repository.NewTransferRepository(r.db)
 }
 
 func (r *repositoryManager) UsersRepo() repository.UsersRepository {
-	return repository.NewUsersRepository(r.infra.Conn())
+	return repository.NewUsersRepository(r.db)
 }
 
 func (r *repositoryManager) SessionsRepo() repository.SessionsRepository {
-	return repository.NewSessionsRepository(r.infra.Conn())
+	return repository.NewSessionsRepository(r.db)
+}
+
+func (r *repositoryManager) MfaRepo() repository.MfaRepository {
+	return repository.NewMfaRepository(r.db)
+}
+
+func (r *repositoryManager) InterestRepo() repository.InterestRepository {
+	return repository.NewInterestRepository(r.db)
+}
+
+func (r *repositoryManager) FeeRulesRepo() repository.FeeRulesRepository {
+	return repository.NewFeeRulesRepository(r.db)
+}
+
+func (r *repositoryManager) HoldsRepo() repository.HoldsRepository {
+	return repository.NewHoldsRepository(r.db)
+}
+
+func (r *repositoryManager) BatchJobsRepo() repository.BatchJobsRepository {
+	return repository.NewBatchJobsRepository(r.db)
+}
+
+func (r *repositoryManager) PayeesRepo() repository.PayeesRepository {
+	return repository.NewPayeesRepository(r.db)
+}
+
+func (r *repositoryManager) SchemaRepo() repository.SchemaRepository {
+	return repository.NewSchemaRepository(r.db)
+}
+
+func (r *repositoryManager) LedgerRepo() repository.LedgerRepository {
+	return repository.NewLedgerRepository(r.db)
+}
+
+// TxManager runs units of work on the manager's connection. Called on the
+// repositories handed to a unit of work, it joins that unit's transaction.
+func (r *repositoryManager) TxManager() TxManager {
+	return &txManager{db: r.db}
 }
 
 func NewRepositoryManager(infra InfrastuctureManager) (RepositoryManager, error) {
 	return &repositoryManager{
-		infra: infra,
+		db: infra.Conn(),
 	}, nil
 }
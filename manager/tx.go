@@ -0,0 +1,46 @@
+package manager
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/terajari/bank-api/repository"
+)
+
+// TxManager runs a unit of work: fn gets repositories bound to one
+// transaction, which is committed when fn returns nil and rolled back
+// otherwise. Repository methods that open their own transaction, such as
+// TransferTx, join the unit's transaction instead.
+type TxManager interface {
+	WithTx(ctx context.Context, fn func(repos repository.Repositories) error) error
+}
+
+type txManager struct {
+	db repository.DBTX
+}
+
+func NewTxManager(db *sqlx.DB) TxManager {
+	return &txManager{db: db}
+}
+
+func (t *txManager) WithTx(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	switch db := t.db.(type) {
+	case *sqlx.Tx:
+		// Already inside a unit of work, its owner commits.
+		return fn(&repositoryManager{db: db})
+	case *sqlx.DB:
+		tx, err := db.BeginTxx(ctx, &sql.TxOptions{})
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := fn(&repositoryManager{db: tx}); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+	return fmt.Errorf("manager: cannot begin a transaction on %T", t.db)
+}
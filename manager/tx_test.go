@@ -0,0 +1,120 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/terajari/bank-api/model"
+	"github.com/terajari/bank-api/repository"
+)
+
+func TestWithTx(t *testing.T) {
+	holdRows := []string{"id", "account_id", "receiver_id", "currency", "amount", "reserved", "captured_amount", "status", "transfer_id", "expires_at", "created_at", "updated_at"}
+	errFailed := errors.New("failed")
+
+	test := []struct {
+		name    string
+		actual  func(sqlmock.Sqlmock)
+		fn      func(ctx context.Context, repos repository.Repositories) error
+		wantErr error
+	}{
+		{
+			name: "commits",
+			actual: func(s sqlmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta("DELETE FROM accounts WHERE id = $1")).
+					WithArgs("acc1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				s.ExpectCommit()
+			},
+			fn: func(ctx context.Context, repos repository.Repositories) error {
+				return repos.AccountsRepo().Delete(ctx, "acc1")
+			},
+		},
+		{
+			name: "rolls back on error",
+			actual: func(s sqlmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta("DELETE FROM accounts WHERE id = $1")).
+					WithArgs("acc1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				s.ExpectRollback()
+			},
+			fn: func(ctx context.Context, repos repository.Repositories) error {
+				if err := repos.AccountsRepo().Delete(ctx, "acc1"); err != nil {
+					return err
+				}
+				return errFailed
+			},
+			wantErr: errFailed,
+		},
+		{
+			name: "repository transaction joins the unit of work",
+			actual: func(s sqlmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectQuery(regexp.QuoteMeta("UPDATE holds SET status = $2")).
+					WithArgs("hold1", model.HoldStatusVoided).
+					WillReturnRows(s.NewRows(holdRows).
+						AddRow("hold1", "acc1", "acc2", "IDR", 1000, 1010, 0, model.HoldStatusVoided, nil, time.Time{}, time.Time{}, time.Time{}))
+				s.ExpectExec(regexp.QuoteMeta("UPDATE accounts SET held_balance = held_balance + $2 WHERE id = $1")).
+					WithArgs("acc1", -1010).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta("DELETE FROM accounts WHERE id = $1")).
+					WithArgs("acc2").
+					WillReturnError(errFailed)
+				s.ExpectRollback()
+			},
+			fn: func(ctx context.Context, repos repository.Repositories) error {
+				if _, err := repos.HoldsRepo().VoidTx(ctx, "hold1"); err != nil {
+					return err
+				}
+				return repos.AccountsRepo().Delete(ctx, "acc2")
+			},
+			wantErr: errFailed,
+		},
+		{
+			name: "nested unit of work",
+			actual: func(s sqlmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta("DELETE FROM accounts WHERE id = $1")).
+					WithArgs("acc1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				s.ExpectCommit()
+			},
+			fn: func(ctx context.Context, repos repository.Repositories) error {
+				return repos.(RepositoryManager).TxManager().WithTx(ctx, func(repos repository.Repositories) error {
+					return repos.AccountsRepo().Delete(ctx, "acc1")
+				})
+			},
+		},
+	}
+
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			tt.actual(mock)
+
+			ctx := context.TODO()
+			tm := NewTxManager(sqlx.NewDb(db, "sqlmock"))
+			err = tm.WithTx(ctx, func(repos repository.Repositories) error {
+				return tt.fn(ctx, repos)
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("WithTx() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
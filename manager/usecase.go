@@ -1,36 +1,106 @@
 package manager
 
-import "github.com/terajari/bank-api/usecase"
+import (
+	"github.com/terajari/bank-api/migration"
+	"github.com/terajari/bank-api/risk"
+	"github.com/terajari/bank-api/token"
+	"github.com/terajari/bank-api/usecase"
+	"github.com/terajari/bank-api/utils"
+)
 
 type UsecaseManager interface {
 	AccountsUsecase() usecase.AccountsUsecase
 	TransferUsecase() usecase.TransferUsecase
 	UsersUsecase() usecase.UsersUsecase
 	SessionsUsecase() usecase.SessionsUsecase
+	MfaUsecase() usecase.MfaUsecase
+	InterestUsecase() usecase.InterestUsecase
+	BatchUsecase() usecase.BatchUsecase
+	PayeesUsecase() usecase.PayeesUsecase
+	HealthUsecase() usecase.HealthUsecase
+	LedgerUsecase() usecase.LedgerUsecase
+	TokenMaker() token.Maker
 }
 
 type usecaseManager struct {
+	Infra      InfrastuctureManager
 	Repository RepositoryManager
+	Config     *utils.Config
+	Hasher     utils.PasswordHasher
+	Risk       usecase.RiskEvaluator
+	Maker      token.Maker
 }
 
 func (u *usecaseManager) AccountsUsecase() usecase.AccountsUsecase {
-	return usecase.NewAccountsUsecase(u.Repository.AccountsRepo())
+	return usecase.NewAccountsUsecase(u.Repository.AccountsRepo(), u.Repository.AccountTypesRepo(), u.Repository.EntryRepo(), u.Repository.TxManager())
 }
 
 func (u *usecaseManager) TransferUsecase() usecase.TransferUsecase {
-	return usecase.NewTransferUsecase(u.Repository.AccountsRepo(), u.Repository.EntryRepo(), u.Repository.TransferRepo())
+	return usecase.NewTransferUsecase(u.Repository.AccountsRepo(), u.Repository.EntryRepo(), u.Repository.TransferRepo(), u.Repository.FeeRulesRepo(), u.Repository.HoldsRepo(), u.Repository.PayeesRepo(), u.Risk, u.Config)
 }
 
 func (u *usecaseManager) UsersUsecase() usecase.UsersUsecase {
-	return usecase.NewUsersUsecase(u.Repository.UsersRepo())
+	return usecase.NewUsersUsecase(u.Repository.UsersRepo(), u.Hasher)
 }
 
 func (u *usecaseManager) SessionsUsecase() usecase.SessionsUsecase {
-	return usecase.NewSessionsUsecase(u.Repository.SessionsRepo())
+	return usecase.NewSessionsUsecase(u.Repository.SessionsRepo(), u.Maker, u.Config)
 }
 
-func NewUsecaseManager(repositoryManager RepositoryManager) (UsecaseManager, error) {
+func (u *usecaseManager) MfaUsecase() usecase.MfaUsecase {
+	return usecase.NewMfaUsecase(u.Repository.MfaRepo(), u.Config)
+}
+
+func (u *usecaseManager) InterestUsecase() usecase.InterestUsecase {
+	return usecase.NewInterestUsecase(u.Repository.InterestRepo(), u.Repository.TxManager())
+}
+
+func (u *usecaseManager) BatchUsecase() usecase.BatchUsecase {
+	return usecase.NewBatchUsecase(u.Repository.BatchJobsRepo(), u.TransferUsecase(), u.Config)
+}
+
+func (u *usecaseManager) PayeesUsecase() usecase.PayeesUsecase {
+	return usecase.NewPayeesUsecase(u.Repository.PayeesRepo(), u.Repository.AccountsRepo())
+}
+
+func (u *usecaseManager) HealthUsecase() usecase.HealthUsecase {
+	return usecase.NewHealthUsecase(u.Infra.Conn(), u.Repository.SchemaRepo(), migration.SchemaVersion)
+}
+
+func (u *usecaseManager) LedgerUsecase() usecase.LedgerUsecase {
+	return usecase.NewLedgerUsecase(u.Repository.LedgerRepo())
+}
+
+func (u *usecaseManager) TokenMaker() token.Maker {
+	return u.Maker
+}
+
+func NewUsecaseManager(infra InfrastuctureManager, repositoryManager RepositoryManager) (UsecaseManager, error) {
+	config := infra.Config()
+	hasher, err := utils.NewPasswordHasher(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var riskEvaluator usecase.RiskEvaluator = risk.AllowAll{}
+	if config.RiskRulesFile != "" {
+		riskEvaluator, err = risk.LoadRules(config.RiskRulesFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tokenMaker, err := token.NewJWTMaker(config.TokenSymmtricKey)
+	if err != nil {
+		return nil, err
+	}
+
 	return &usecaseManager{
+		Infra:      infra,
 		Repository: repositoryManager,
+		Config:     config,
+		Hasher:     hasher,
+		Risk:       riskEvaluator,
+		Maker:      tokenMaker,
 	}, nil
 }
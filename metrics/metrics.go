@@ -0,0 +1,186 @@
+// Package metrics defines the Prometheus metrics of the API. They are
+// registered on Registry, which /metrics serves.
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/terajari/bank-api/apperror"
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/model"
+)
+
+const namespace = "bank_api"
+
+// Outcomes that are not an error code.
+const (
+	OutcomeError = "error"
+
+	LoginSuccess = "success"
+	LoginFailure = "failure"
+
+	RenewalRenewed  = "renewed"
+	RenewalExtended = "extended"
+
+	TransferApproved = "approved"
+
+	LedgerConsistent   = "consistent"
+	LedgerInconsistent = "inconsistent"
+)
+
+// Checks of the ledger verification.
+const (
+	LedgerCheckBalance  = "balance"
+	LedgerCheckTransfer = "transfer"
+	LedgerCheckCurrency = "currency"
+)
+
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route template and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route template and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	Transfers = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transfers_total",
+		Help:      "Transfers by currency and outcome: the transfer status, approved or the error code.",
+	}, []string{"currency", "outcome"})
+
+	TransferAmount = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "transfer_amount",
+		Help:      "Amounts moved by transfers in minor units, by currency.",
+		Buckets:   prometheus.ExponentialBuckets(100, 10, 9),
+	}, []string{"currency"})
+
+	Logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Password logins by result: success, failure or error.",
+	}, []string{"result"})
+
+	SessionRenewals = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "session_renewals_total",
+		Help:      "Access token renewals by result: renewed, extended or the error code.",
+	}, []string{"result"})
+
+	LedgerVerifications = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ledger_verifications_total",
+		Help:      "Ledger verifications by result: consistent, inconsistent or error.",
+	}, []string{"result"})
+
+	LedgerProblems = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "ledger_problems",
+		Help:      "Problems found by the last ledger verification, by check: drifted balances, transfers with wrong entries and imbalanced currencies.",
+	}, []string{"check"})
+
+	LedgerImbalance = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "ledger_imbalance",
+		Help:      "Sum of the entries of each currency in minor units at the last ledger verification, zero when balanced.",
+	}, []string{"currency"})
+
+	LedgerLastVerified = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "ledger_last_verification_timestamp_seconds",
+		Help:      "Unix time of the last ledger verification that completed.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPDuration,
+		Transfers,
+		TransferAmount,
+		Logins,
+		SessionRenewals,
+		LedgerVerifications,
+		LedgerProblems,
+		LedgerImbalance,
+		LedgerLastVerified,
+	)
+}
+
+// Handler serves the metrics of Registry.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// RegisterDBStats exposes the connection pool statistics of db as the
+// go_sql_* gauges and counters with db_name="bank_api".
+func RegisterDBStats(db *sql.DB) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, namespace))
+}
+
+// Outcome names err by its domain error code, so the label values stay a
+// fixed set. Errors without a code are reported as OutcomeError.
+func Outcome(err error) string {
+	var limitErr *model.LimitExceededError
+	var coolingOffErr *model.PayeeCoolingOffError
+	switch {
+	case errors.As(err, &limitErr):
+		return apperror.ErrLimitExceeded.Code
+	case errors.As(err, &coolingOffErr):
+		return apperror.ErrPayeeCoolingOff.Code
+	}
+	if appErr, ok := apperror.As(err); ok {
+		return appErr.Code
+	}
+	return OutcomeError
+}
+
+// ObserveTransfer counts a transfer attempt. outcome is the transfer status
+// or TransferApproved; it is replaced by the error's outcome when err is set.
+// Amounts are observed when money moved.
+func ObserveTransfer(currency, outcome string, amount int64, err error) {
+	if err != nil {
+		outcome = Outcome(err)
+	}
+	Transfers.WithLabelValues(currency, outcome).Inc()
+	if err == nil && (outcome == model.TransferStatusCompleted || outcome == TransferApproved) {
+		TransferAmount.WithLabelValues(currency).Observe(float64(amount))
+	}
+}
+
+// ObserveLedgerReport counts a ledger verification and, when it completed,
+// sets the problem gauges from its report.
+func ObserveLedgerReport(report dto.LedgerReport, err error) {
+	if err != nil {
+		LedgerVerifications.WithLabelValues(OutcomeError).Inc()
+		return
+	}
+	result := LedgerConsistent
+	if !report.Consistent {
+		result = LedgerInconsistent
+	}
+	LedgerVerifications.WithLabelValues(result).Inc()
+	LedgerProblems.WithLabelValues(LedgerCheckBalance).Set(float64(len(report.BalanceDrifts)))
+	LedgerProblems.WithLabelValues(LedgerCheckTransfer).Set(float64(len(report.TransferIssues)))
+	LedgerProblems.WithLabelValues(LedgerCheckCurrency).Set(float64(len(report.Imbalanced())))
+	for _, total := range report.CurrencyTotals {
+		LedgerImbalance.WithLabelValues(total.Currency).Set(float64(total.Imbalance))
+	}
+	LedgerLastVerified.Set(float64(report.CheckedAt.Unix()))
+}
@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/terajari/bank-api/apperror"
+	"github.com/terajari/bank-api/model"
+)
+
+func TestOutcome(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "domain error", err: apperror.ErrInsufficientFunds.Withf("insufficient balance: 1 < 2"), want: "insufficient_funds"},
+		{name: "wrapped domain error", err: fmt.Errorf("item 1: %w", apperror.ErrCurrencyMismatch), want: "currency_mismatch"},
+		{name: "limit", err: &model.LimitExceededError{Scope: "user", Limit: "max_daily"}, want: "limit_exceeded"},
+		{name: "cooling-off", err: &model.PayeeCoolingOffError{}, want: "payee_cooling_off"},
+		{name: "unknown error", err: errors.New("pq: deadlock detected"), want: OutcomeError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Outcome(tc.err); got != tc.want {
+				t.Errorf("Outcome() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestObserveTransfer(t *testing.T) {
+	testCases := []struct {
+		name        string
+		outcome     string
+		err         error
+		wantOutcome string
+		wantAmount  bool
+	}{
+		{name: "completed", outcome: model.TransferStatusCompleted, wantOutcome: model.TransferStatusCompleted, wantAmount: true},
+		{name: "held for review", outcome: model.TransferStatusPendingReview, wantOutcome: model.TransferStatusPendingReview},
+		{name: "approved", outcome: TransferApproved, wantOutcome: TransferApproved, wantAmount: true},
+		{name: "failed", err: apperror.ErrInsufficientFunds, wantOutcome: "insufficient_funds"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			counter := Transfers.WithLabelValues("EUR", tc.wantOutcome)
+			before := testutil.ToFloat64(counter)
+			amountsBefore := amountCount(t, "EUR")
+
+			ObserveTransfer("EUR", tc.outcome, 1000, tc.err)
+
+			if got := testutil.ToFloat64(counter) - before; got != 1 {
+				t.Errorf("transfers{outcome=%q} grew by %v, want 1", tc.wantOutcome, got)
+			}
+			if observed := amountCount(t, "EUR") > amountsBefore; observed != tc.wantAmount {
+				t.Errorf("amount observed = %v, want %v", observed, tc.wantAmount)
+			}
+		})
+	}
+}
+
+func amountCount(t *testing.T, currency string) uint64 {
+	t.Helper()
+	var m dto.Metric
+	if err := TransferAmount.WithLabelValues(currency).(prometheus.Histogram).Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/terajari/bank-api/apperror"
+	"github.com/terajari/bank-api/model"
+	"github.com/terajari/bank-api/token"
+	"github.com/terajari/bank-api/usecase"
+)
+
+// AdminMiddleware must run after AuthMiddleware. The role is read from the
+// database on every request so that revoking it takes effect immediately.
+func AdminMiddleware(users usecase.UsersUsecase) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := ctx.MustGet(AuthorizationPayloadKey).(*token.Payload)
+		user, err := users.GetUser(ctx, payload.Username)
+		if err != nil {
+			ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+
+		if user.Role != model.RoleAdmin {
+			ctx.Error(apperror.ErrForbidden.Withf("admin role is required"))
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
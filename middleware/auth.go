@@ -1,10 +1,10 @@
 package middleware
 
 import (
-	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/terajari/bank-api/apperror"
 	"github.com/terajari/bank-api/token"
 )
 
@@ -18,33 +18,36 @@ func AuthMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authorizationHeader := ctx.GetHeader(AuthorizationHeaderKey)
 		if len(authorizationHeader) == 0 {
-			err := "authorization header is not provided"
-			ctx.AbortWithStatusJSON(
-				http.StatusUnauthorized, gin.H{"error": err})
+			ctx.Error(apperror.ErrUnauthenticated.Withf("authorization header is not provided"))
+			ctx.Abort()
 			return
 		}
 
 		fields := strings.Fields(authorizationHeader)
 		if len(fields) < 2 {
-			err := "invalid authorization header format"
-			ctx.AbortWithStatusJSON(
-				http.StatusUnauthorized, gin.H{"error": err})
+			ctx.Error(apperror.ErrUnauthenticated.Withf("invalid authorization header format"))
+			ctx.Abort()
 			return
 		}
 
 		authrizationType := strings.ToLower(fields[0])
 		if authrizationType != AuthorizationTypeBearer {
-			err := "incorect authorization type"
-			ctx.AbortWithStatusJSON(
-				http.StatusUnauthorized, gin.H{"error": err})
+			ctx.Error(apperror.ErrUnauthenticated.Withf("incorect authorization type"))
+			ctx.Abort()
 			return
 		}
 
 		accessToken := fields[1]
 		payload, err := tokenMaker.VerifyToken(accessToken)
 		if err != nil {
-			ctx.AbortWithStatusJSON(
-				http.StatusUnauthorized, gin.H{"error": err.Error()})
+			ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+
+		if payload.Purpose != "" {
+			ctx.Error(apperror.ErrUnauthenticated.Withf("token cannot be used for authorization"))
+			ctx.Abort()
 			return
 		}
 
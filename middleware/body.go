@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/terajari/bank-api/apperror"
+)
+
+// BodyLimit caps request bodies at limit bytes. Reading past the limit fails
+// with *http.MaxBytesError, which the error middleware answers with 413.
+func BodyLimit(limit int64) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.Request.ContentLength > limit {
+			ctx.Error(apperror.ErrPayloadTooLarge.Wrap(&http.MaxBytesError{Limit: limit}))
+			ctx.Abort()
+			return
+		}
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, limit)
+		ctx.Next()
+	}
+}
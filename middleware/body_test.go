@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/terajari/bank-api/apperror"
+)
+
+func TestBodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	testCases := []struct {
+		name       string
+		body       string
+		chunked    bool
+		wantStatus int
+		wantCode   string
+		wantCalled bool
+	}{
+		{
+			name:       "within limit",
+			body:       `{"amount":1}`,
+			wantStatus: http.StatusOK,
+			wantCalled: true,
+		},
+		{
+			name:       "declared length over limit",
+			body:       `{"amount":1000000000}`,
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantCode:   "payload_too_large",
+		},
+		{
+			name:       "chunked body over limit",
+			body:       `{"amount":1000000000}`,
+			chunked:    true,
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantCode:   "payload_too_large",
+			wantCalled: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			router := gin.New()
+			router.Use(ErrorMiddleware(), BodyLimit(16))
+			router.POST("/test", func(ctx *gin.Context) {
+				called = true
+				var req struct {
+					Amount int64 `json:"amount"`
+				}
+				if err := ctx.ShouldBindJSON(&req); err != nil {
+					ctx.Error(apperror.ErrInvalidRequest.Wrap(err))
+					return
+				}
+				ctx.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.chunked {
+				req.ContentLength = -1
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tc.wantStatus, rec.Body.String())
+			}
+			if called != tc.wantCalled {
+				t.Errorf("handler called = %v, want %v", called, tc.wantCalled)
+			}
+			if tc.wantCode == "" {
+				return
+			}
+			var problem Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+				t.Fatal(err)
+			}
+			if problem.Code != tc.wantCode {
+				t.Errorf("code = %q, want %q", problem.Code, tc.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lib/pq"
+	"github.com/terajari/bank-api/apperror"
+	"github.com/terajari/bank-api/logging"
+	"github.com/terajari/bank-api/model"
+)
+
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem detail. Code is stable and meant for
+// clients to switch on; Detail is for humans and may change.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+
+	Errors     []FieldError                `json:"errors,omitempty"`
+	Limit      *model.LimitExceededError   `json:"limit,omitempty"`
+	CoolingOff *model.PayeeCoolingOffError `json:"cooling_off,omitempty"`
+}
+
+// FieldError is a request field that failed validation.
+type FieldError struct {
+	Field string `json:"field"`
+	Rule  string `json:"rule"`
+}
+
+// ErrorMiddleware renders the last error added with ctx.Error as a problem
+// detail. Handlers that already wrote a response are left alone.
+func ErrorMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		if len(ctx.Errors) == 0 || ctx.Writer.Written() {
+			return
+		}
+		err := ctx.Errors.Last().Err
+		problem := NewProblem(err, ctx.Request.URL.Path)
+		if problem.Status == http.StatusInternalServerError {
+			logging.FromContext(ctx.Request.Context()).Error("request failed",
+				"method", ctx.Request.Method, "path", ctx.Request.URL.Path, "error", err)
+		}
+		if problem.Limit != nil && problem.Limit.ResetsAt != nil {
+			ctx.Header("Retry-After", strconv.Itoa(int(time.Until(*problem.Limit.ResetsAt).Seconds())+1))
+		}
+
+		ctx.Header("Content-Type", ProblemContentType)
+		ctx.JSON(problem.Status, problem)
+	}
+}
+
+// NewProblem maps err to its problem detail. Errors that are not domain
+// errors are reported as internal errors without their message, so database
+// and driver details never reach the client.
+func NewProblem(err error, instance string) Problem {
+	appErr, detail := classify(err)
+	status := appErr.Kind.Status()
+	problem := Problem{
+		Type:     "/errors/" + appErr.Code,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: instance,
+		Code:     appErr.Code,
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		for _, fe := range validationErrs {
+			problem.Errors = append(problem.Errors, FieldError{Field: fe.Namespace(), Rule: fe.Tag()})
+		}
+	}
+	errors.As(err, &problem.Limit)
+	errors.As(err, &problem.CoolingOff)
+	return problem
+}
+
+func classify(err error) (*apperror.Error, string) {
+	var limitErr *model.LimitExceededError
+	var coolingOffErr *model.PayeeCoolingOffError
+	var validationErrs validator.ValidationErrors
+	var pqErr *pq.Error
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		// Checked first: binding errors arrive wrapped as invalid requests.
+		return apperror.ErrPayloadTooLarge, fmt.Sprintf("request body must not exceed %d bytes", maxBytesErr.Limit)
+	case errors.As(err, &limitErr):
+		return apperror.ErrLimitExceeded, limitErr.Error()
+	case errors.As(err, &coolingOffErr):
+		return apperror.ErrPayeeCoolingOff, coolingOffErr.Error()
+	}
+
+	if appErr, ok := apperror.As(err); ok {
+		switch appErr.Kind {
+		case apperror.KindInternal:
+			return appErr, apperror.ErrInternal.Message
+		case apperror.KindInvalid:
+			// The cause of an invalid request is the client's own input.
+			return appErr, appErr.Error()
+		}
+		return appErr, appErr.Message
+	}
+
+	switch {
+	case errors.As(err, &validationErrs):
+		return apperror.ErrInvalidRequest, err.Error()
+	case errors.Is(err, sql.ErrNoRows):
+		return apperror.ErrNotFound, apperror.ErrNotFound.Message
+	case errors.Is(err, context.DeadlineExceeded):
+		return apperror.ErrUnavailable, apperror.ErrUnavailable.Message
+	case errors.As(err, &pqErr):
+		switch pqErr.Code.Name() {
+		case "unique_violation":
+			return apperror.ErrConflict, apperror.ErrConflict.Message
+		case "foreign_key_violation", "check_violation", "not_null_violation":
+			return apperror.ErrConstraint, apperror.ErrConstraint.Message
+		}
+	}
+	return apperror.ErrInternal, apperror.ErrInternal.Message
+}
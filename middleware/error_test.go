@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/terajari/bank-api/apperror"
+	"github.com/terajari/bank-api/model"
+)
+
+func TestErrorMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	testCases := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantDetail string
+	}{
+		{
+			name:       "domain error",
+			err:        apperror.ErrAccountNotFound,
+			wantStatus: http.StatusNotFound,
+			wantCode:   "account_not_found",
+			wantDetail: "account not found",
+		},
+		{
+			name:       "wrapped domain error",
+			err:        fmt.Errorf("transfer: %w", apperror.ErrInsufficientFunds.Withf("insufficient balance: 1 < 2")),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   "insufficient_funds",
+			wantDetail: "insufficient balance: 1 < 2",
+		},
+		{
+			name:       "invalid request keeps cause",
+			err:        apperror.ErrInvalidRequest.Wrap(errors.New("EOF")),
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_request",
+			wantDetail: "invalid request: EOF",
+		},
+		{
+			name:       "body too large",
+			err:        apperror.ErrInvalidRequest.Wrap(&http.MaxBytesError{Limit: 1024}),
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantCode:   "payload_too_large",
+			wantDetail: "request body must not exceed 1024 bytes",
+		},
+		{
+			name:       "no rows",
+			err:        sql.ErrNoRows,
+			wantStatus: http.StatusNotFound,
+			wantCode:   "not_found",
+			wantDetail: "resource not found",
+		},
+		{
+			name:       "unique violation hides driver message",
+			err:        &pq.Error{Code: "23505", Message: "duplicate key value violates unique constraint \"users_pkey\""},
+			wantStatus: http.StatusConflict,
+			wantCode:   "conflict",
+			wantDetail: "resource already exists",
+		},
+		{
+			name:       "limit exceeded",
+			err:        &model.LimitExceededError{Scope: "user", Limit: "max_single", Max: 10, Requested: 20},
+			wantStatus: http.StatusTooManyRequests,
+			wantCode:   "limit_exceeded",
+			wantDetail: "user limit max_single exceeded for : 0 used + 20 requested > 10",
+		},
+		{
+			name:       "unknown error",
+			err:        errors.New("dial tcp: connection refused"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   "internal_error",
+			wantDetail: "internal server error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(ErrorMiddleware())
+			router.GET("/test", func(ctx *gin.Context) {
+				ctx.Error(tc.err)
+			})
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tc.wantStatus)
+			}
+			if got := rec.Header().Get("Content-Type"); got != ProblemContentType {
+				t.Errorf("Content-Type = %q, want %q", got, ProblemContentType)
+			}
+			var problem Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("cannot decode body %q: %v", rec.Body.String(), err)
+			}
+			if problem.Code != tc.wantCode {
+				t.Errorf("code = %q, want %q", problem.Code, tc.wantCode)
+			}
+			if problem.Type != "/errors/"+tc.wantCode {
+				t.Errorf("type = %q, want %q", problem.Type, "/errors/"+tc.wantCode)
+			}
+			if problem.Status != tc.wantStatus || problem.Title != http.StatusText(tc.wantStatus) {
+				t.Errorf("status, title = %d, %q", problem.Status, problem.Title)
+			}
+			if problem.Detail != tc.wantDetail {
+				t.Errorf("detail = %q, want %q", problem.Detail, tc.wantDetail)
+			}
+			if problem.Instance != "/test" {
+				t.Errorf("instance = %q, want %q", problem.Instance, "/test")
+			}
+		})
+	}
+}
+
+func TestErrorMiddlewareWrittenResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorMiddleware())
+	router.GET("/test", func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, "partial")
+		ctx.Error(errors.New("stream aborted"))
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "partial" {
+		t.Errorf("got %d %q, want the handler's response untouched", rec.Code, rec.Body.String())
+	}
+}
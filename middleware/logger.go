@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/terajari/bank-api/logging"
+)
+
+// AccessLog logs every request once it is served. It must run after
+// RequestID for the record to carry the request ID.
+func AccessLog() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		logging.FromContext(ctx.Request.Context()).LogAttrs(ctx.Request.Context(), level, "request",
+			slog.String("method", ctx.Request.Method),
+			slog.String("route", ctx.FullPath()),
+			slog.String("path", ctx.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Int("bytes", ctx.Writer.Size()),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("client_ip", ctx.ClientIP()),
+		)
+	}
+}
+
+// Recovery turns a panic into a 500 problem and logs it with its stack.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(ctx *gin.Context, recovered any) {
+		err := fmt.Errorf("panic: %v", recovered)
+		logging.FromContext(ctx.Request.Context()).Error("panic recovered", "error", err, "stack", string(debug.Stack()))
+		ctx.Header("Content-Type", ProblemContentType)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, NewProblem(err, ctx.Request.URL.Path))
+	})
+}
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/terajari/bank-api/logging"
+)
+
+// newLoggedRouter returns a router whose requests log to buf through the
+// request ID and access log middleware.
+func newLoggedRouter(buf *bytes.Buffer) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger := logging.New(buf, slog.LevelInfo)
+	router := gin.New()
+	router.ContextWithFallback = true
+	router.Use(func(ctx *gin.Context) {
+		ctx.Request = ctx.Request.WithContext(logging.WithContext(ctx.Request.Context(), logger))
+	})
+	router.Use(RequestID(), AccessLog(), Recovery(), ErrorMiddleware())
+	return router
+}
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	for _, raw := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var line map[string]any
+		if err := json.Unmarshal([]byte(raw), &line); err != nil {
+			t.Fatalf("cannot decode %q: %v", raw, err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestRequestID(t *testing.T) {
+	testCases := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{name: "honored", header: "req-42.a:b", keep: true},
+		{name: "created when missing"},
+		{name: "replaced when malformed", header: "bad id\n{\"level\":\"ERROR\"}"},
+		{name: "replaced when too long", header: strings.Repeat("a", 129)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			router := newLoggedRouter(&buf)
+			router.GET("/ping", func(ctx *gin.Context) {
+				logging.FromContext(ctx).Info("handled", "password", "hunter22")
+				ctx.String(http.StatusOK, GetRequestID(ctx))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+			if tc.header != "" {
+				req.Header.Set(RequestIDHeader, tc.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			id := rec.Header().Get(RequestIDHeader)
+			if tc.keep && id != tc.header {
+				t.Errorf("request ID = %q, want %q", id, tc.header)
+			}
+			if !tc.keep && (id == "" || id == tc.header) {
+				t.Errorf("request ID = %q, want a new one", id)
+			}
+			if rec.Body.String() != id {
+				t.Errorf("GetRequestID() = %q, want %q", rec.Body.String(), id)
+			}
+
+			lines := decodeLines(t, &buf)
+			if len(lines) != 2 {
+				t.Fatalf("got %d records, want 2", len(lines))
+			}
+			for _, line := range lines {
+				if line["request_id"] != id {
+					t.Errorf("record %v has request_id %v, want %q", line["msg"], line["request_id"], id)
+				}
+			}
+			if lines[0]["password"] != logging.Redacted {
+				t.Errorf("password = %v, want it redacted", lines[0]["password"])
+			}
+		})
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	router := newLoggedRouter(&buf)
+	router.GET("/account/:id", func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/account/acc1", nil))
+
+	line := decodeLines(t, &buf)[0]
+	if line["msg"] != "request" || line["level"] != "INFO" {
+		t.Errorf("record = %v", line)
+	}
+	if line["route"] != "/account/:id" || line["path"] != "/account/acc1" || line["status"] != float64(http.StatusNoContent) {
+		t.Errorf("record = %v", line)
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	var buf bytes.Buffer
+	router := newLoggedRouter(&buf)
+	router.GET("/panic", func(ctx *gin.Context) {
+		panic("boom")
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	var problem Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatal(err)
+	}
+	if problem.Code != "internal_error" || strings.Contains(rec.Body.String(), "boom") {
+		t.Errorf("problem = %+v", problem)
+	}
+
+	lines := decodeLines(t, &buf)
+	if len(lines) != 2 || lines[0]["msg"] != "panic recovered" || lines[1]["level"] != "ERROR" {
+		t.Errorf("records = %v", lines)
+	}
+}
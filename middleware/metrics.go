@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/terajari/bank-api/metrics"
+)
+
+// unmatchedRoute labels requests that matched no route, so that scanners
+// probing random paths do not create new series.
+const unmatchedRoute = "unmatched"
+
+// MetricsMiddleware counts and times requests by route template, e.g.
+// /account/:id. It must run before ErrorMiddleware to see the final status.
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := strconv.Itoa(ctx.Writer.Status())
+		metrics.HTTPRequests.WithLabelValues(ctx.Request.Method, route, status).Inc()
+		metrics.HTTPDuration.WithLabelValues(ctx.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/terajari/bank-api/apperror"
+	"github.com/terajari/bank-api/metrics"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(MetricsMiddleware(), ErrorMiddleware())
+	router.GET("/account/:id", func(ctx *gin.Context) {
+		if ctx.Param("id") == "missing" {
+			ctx.Error(apperror.ErrAccountNotFound)
+			return
+		}
+		ctx.Status(http.StatusOK)
+	})
+
+	testCases := []struct {
+		name   string
+		path   string
+		route  string
+		status string
+	}{
+		{name: "route template", path: "/account/acc1", route: "/account/:id", status: "200"},
+		{name: "status from error middleware", path: "/account/missing", route: "/account/:id", status: "404"},
+		{name: "unmatched path", path: "/wp-login.php", route: "unmatched", status: "404"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			counter := metrics.HTTPRequests.WithLabelValues(http.MethodGet, tc.route, tc.status)
+			before := testutil.ToFloat64(counter)
+
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			if got := testutil.ToFloat64(counter) - before; got != 1 {
+				t.Errorf("requests{route=%q,status=%q} grew by %v, want 1", tc.route, tc.status, got)
+			}
+		})
+	}
+}
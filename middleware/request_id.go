@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/terajari/bank-api/logging"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the gin context key of the request ID.
+const requestIDKey = "request_id"
+
+// validRequestID keeps IDs set by a proxy or client short and free of
+// characters that could forge log lines.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID takes the request ID from the X-Request-ID header, or creates one
+// when it is missing or malformed, and echoes it in the response. The request
+// context carries a logger that adds the ID to every record, so usecases and
+// repositories log it by logging from their ctx.
+func RequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = uuid.NewString()
+		}
+		ctx.Set(requestIDKey, id)
+		ctx.Header(RequestIDHeader, id)
+		ctx.Request = ctx.Request.WithContext(logging.With(ctx.Request.Context(), "request_id", id))
+		ctx.Next()
+	}
+}
+
+// GetRequestID returns the ID RequestID gave the request.
+func GetRequestID(ctx *gin.Context) string {
+	return ctx.GetString(requestIDKey)
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/terajari/bank-api/apperror"
+	"github.com/terajari/bank-api/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing(t *testing.T) {
+	previous, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(previous)
+		otel.SetTextMapPropagator(previousPropagator)
+	}()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.ContextWithFallback = true
+	router.Use(RequestID(), Tracing(), ErrorMiddleware())
+	router.GET("/account/:id", func(ctx *gin.Context) {
+		_, span := tracing.Start(ctx, "AccountsUsecase.Get")
+		span.End()
+		if ctx.Param("id") == "broken" {
+			ctx.Error(apperror.ErrInternal)
+			return
+		}
+		ctx.Status(http.StatusOK)
+	})
+
+	testCases := []struct {
+		name       string
+		path       string
+		wantStatus codes.Code
+	}{
+		{name: "ok", path: "/account/acc1", wantStatus: codes.Unset},
+		{name: "server error", path: "/account/broken", wantStatus: codes.Error},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			spans := recorder.Ended()
+			if len(spans) != 2 {
+				t.Fatalf("got %d spans, want 2", len(spans))
+			}
+			child, server := spans[0], spans[1]
+			if server.Name() != "GET /account/:id" {
+				t.Errorf("server span name = %q", server.Name())
+			}
+			if got := server.SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+				t.Errorf("trace ID = %s, want the caller's", got)
+			}
+			if child.Parent().SpanID() != server.SpanContext().SpanID() {
+				t.Error("usecase span is not a child of the server span")
+			}
+			if server.Status().Code != tc.wantStatus {
+				t.Errorf("status = %v, want %v", server.Status().Code, tc.wantStatus)
+			}
+			var sawRoute bool
+			for _, kv := range server.Attributes() {
+				if kv.Key == attribute.Key("http.route") && kv.Value.AsString() == "/account/:id" {
+					sawRoute = true
+				}
+			}
+			if !sawRoute {
+				t.Errorf("attributes = %v, want http.route", server.Attributes())
+			}
+		})
+	}
+}
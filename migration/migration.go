@@ -0,0 +1,178 @@
+// Package migration holds the database migrations. The files in
+// migration/postgres are embedded in the binary and applied with
+// golang-migrate, either by the migrate command or on startup with
+// AUTO_MIGRATE.
+package migration
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// SchemaVersion is the version of the newest migration. The readiness check
+// fails while the database is behind it, so it must be bumped together with
+// every new migration.
+const SchemaVersion = 20231110124530
+
+const dir = "postgres"
+
+//go:embed postgres/*.sql
+var files embed.FS
+
+// Migration is an embedded migration.
+type Migration struct {
+	Version uint
+	Name    string
+}
+
+// Status is a migration and whether the database has it applied.
+type Status struct {
+	Migration
+	Applied bool
+	Dirty   bool
+}
+
+// List returns the embedded migrations, oldest first.
+func List() ([]Migration, error) {
+	names, err := fs.Glob(files, dir+"/*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+	migrations := make([]Migration, 0, len(names))
+	for _, name := range names {
+		base := strings.TrimSuffix(strings.TrimPrefix(name, dir+"/"), ".up.sql")
+		prefix, title, _ := strings.Cut(base, "_")
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration: %s: %w", name, err)
+		}
+		migrations = append(migrations, Migration{Version: uint(version), Name: title})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrator applies the embedded migrations to a database. Up and Down hold a
+// Postgres advisory lock while they run, so replicas migrating at the same
+// time wait for each other and the later ones find nothing left to do.
+type Migrator struct {
+	m *migrate.Migrate
+}
+
+// Open connects to the database on a connection of its own, closed by Close.
+// lockTimeout bounds how long Up and Down wait for another migrator.
+func Open(driverName, dsn string, lockTimeout time.Duration) (*Migrator, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	m, err := newMigrate(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if lockTimeout > 0 {
+		m.LockTimeout = lockTimeout
+	}
+	m.Log = logger{}
+	return &Migrator{m: m}, nil
+}
+
+func newMigrate(db *sql.DB) (*migrate.Migrate, error) {
+	src, err := iofs.New(files, dir)
+	if err != nil {
+		return nil, fmt.Errorf("migration: %w", err)
+	}
+	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("migration: %w", err)
+	}
+	return migrate.NewWithInstance("iofs", src, "postgres", driver)
+}
+
+// Up applies n pending migrations, or all of them when n is 0. It is not an
+// error when none is pending.
+func (mg *Migrator) Up(n int) error {
+	var err error
+	if n > 0 {
+		err = mg.m.Steps(n)
+	} else {
+		err = mg.m.Up()
+	}
+	return ignoreNoChange(err)
+}
+
+// Down reverts the n newest applied migrations.
+func (mg *Migrator) Down(n int) error {
+	if n <= 0 {
+		return errors.New("migration: the number of migrations to revert must be positive")
+	}
+	return ignoreNoChange(mg.m.Steps(-n))
+}
+
+// Version returns the version of the newest applied migration, 0 when none
+// is, and whether it failed halfway.
+func (mg *Migrator) Version() (uint, bool, error) {
+	version, dirty, err := mg.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// Status returns every embedded migration with whether it is applied.
+func (mg *Migrator) Status() ([]Status, error) {
+	version, dirty, err := mg.Version()
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := List()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, len(migrations))
+	for i, m := range migrations {
+		statuses[i] = Status{
+			Migration: m,
+			Applied:   m.Version <= version,
+			Dirty:     dirty && m.Version == version,
+		}
+	}
+	return statuses, nil
+}
+
+// Close closes the connection of the migrator.
+func (mg *Migrator) Close() error {
+	srcErr, dbErr := mg.m.Close()
+	return errors.Join(srcErr, dbErr)
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return err
+}
+
+// logger reports the progress of golang-migrate through the default logger.
+type logger struct{}
+
+func (logger) Printf(format string, v ...any) {
+	slog.Info("migration: " + strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+func (logger) Verbose() bool {
+	return true
+}
@@ -0,0 +1,94 @@
+package migration
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSchemaVersionIsNewestMigration(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("postgres", "*.up.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var newest int64
+	for _, file := range files {
+		prefix, _, _ := strings.Cut(filepath.Base(file), "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		if version > newest {
+			newest = version
+		}
+	}
+	if newest != SchemaVersion {
+		t.Errorf("SchemaVersion = %d, newest migration in %s is %d", SchemaVersion, "postgres"+string(os.PathSeparator), newest)
+	}
+}
+
+func TestList(t *testing.T) {
+	migrations, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no migration embedded")
+	}
+	for i, m := range migrations {
+		if i > 0 && m.Version <= migrations[i-1].Version {
+			t.Errorf("migration %d is listed after %d", m.Version, migrations[i-1].Version)
+		}
+		down := fmt.Sprintf("%s/%d_%s.down.sql", dir, m.Version, m.Name)
+		if _, err := fs.Stat(files, down); err != nil {
+			t.Errorf("migration %d_%s has no down migration: %v", m.Version, m.Name, err)
+		}
+	}
+	if newest := migrations[len(migrations)-1]; newest.Version != SchemaVersion {
+		t.Errorf("newest embedded migration is %d, SchemaVersion is %d", newest.Version, SchemaVersion)
+	}
+	if migrations[0].Name != "init-schema" {
+		t.Errorf("first migration = %q, want init-schema", migrations[0].Name)
+	}
+}
+
+// Entries booked before entries.transfer_id existed have it NULL, the ledger
+// check would report every such transfer unless they are backfilled.
+func TestBackfillsLegacyEntryTransferIds(t *testing.T) {
+	up, err := fs.ReadFile(files, dir+"/20231110091540_backfill_entry_transfer_ids.up.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := strings.Join(strings.Fields(string(up)), " ")
+	for _, want := range []string{
+		`SET "transfer_id" = l."transfer_id"`,
+		`WHERE e."transfer_id" IS NULL`,
+		`-"amount" AS "amount"`,
+		`l."account_id" = le."account_id" AND l."amount" = le."amount" AND l."created_at" = le."created_at" AND l."n" = le."n"`,
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("backfill does not contain %q", want)
+		}
+	}
+}
+
+func TestBooksInitialDepositsFromSettlement(t *testing.T) {
+	up, err := fs.ReadFile(files, dir+"/20231110120510_book_initial_deposits.up.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := strings.Join(strings.Fields(string(up)), " ")
+	for _, want := range []string{
+		`WHERE e."transfer_id" IS NULL AND e."amount" > 0`,
+		`SELECT "transfer_id", "settlement_id", "account_id", "amount"`,
+		`SELECT gen_random_uuid()::text, "settlement_id", -"amount", "transfer_id"`,
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("booking does not contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "mfa_recovery_codes";
+
+DROP TABLE IF EXISTS "user_mfa";
\ No newline at end of file
@@ -0,0 +1,22 @@
+CREATE TABLE "user_mfa" (
+  "username" varchar PRIMARY KEY,
+  "secret" varchar NOT NULL,
+  "enabled" boolean NOT NULL DEFAULT false,
+  "last_used_step" bigint NOT NULL DEFAULT 0,
+  "confirmed_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "mfa_recovery_codes" (
+  "id" varchar(100) PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "code_hash" varchar NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "mfa_recovery_codes" ("username", "code_hash");
+
+ALTER TABLE "user_mfa" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "mfa_recovery_codes" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
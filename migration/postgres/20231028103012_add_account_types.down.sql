@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS "owner_currency_type_key";
+
+DROP INDEX IF EXISTS "accounts_owner_currency_type_idx";
+
+ALTER TABLE IF EXISTS "accounts" ADD CONSTRAINT "owner_currency_key" UNIQUE ("owner", "currency");
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "unique_per_currency";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "type";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "nickname";
+
+DROP TABLE IF EXISTS "account_types";
@@ -0,0 +1,22 @@
+CREATE TABLE "account_types" (
+  "name" varchar PRIMARY KEY,
+  "unique_per_currency" boolean NOT NULL DEFAULT false,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+INSERT INTO "account_types" ("name", "unique_per_currency") VALUES ('checking', true), ('savings', false);
+
+ALTER TABLE "accounts" ADD COLUMN "nickname" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "accounts" ADD COLUMN "type" varchar NOT NULL DEFAULT 'checking';
+
+-- copied from account_types when the account is created so the partial index below can enforce it
+ALTER TABLE "accounts" ADD COLUMN "unique_per_currency" boolean NOT NULL DEFAULT true;
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("type") REFERENCES "account_types" ("name");
+
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "owner_currency_key";
+
+CREATE UNIQUE INDEX "owner_currency_type_key" ON "accounts" ("owner", "currency", "type") WHERE "unique_per_currency";
+
+CREATE INDEX ON "accounts" ("owner", "currency", "type");
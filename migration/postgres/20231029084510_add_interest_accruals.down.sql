@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS "interest_accruals";
+
+DELETE FROM "accounts" WHERE "id" IN ('sys-interest-expense-idr', 'sys-interest-expense-usd', 'sys-interest-expense-eur');
+
+DELETE FROM "users" WHERE "username" = 'bank';
+
+DELETE FROM "account_types" WHERE "name" = 'system';
+
+ALTER TABLE IF EXISTS "account_types" DROP COLUMN IF EXISTS "annual_interest_rate_bps";
@@ -0,0 +1,30 @@
+ALTER TABLE "account_types" ADD COLUMN "annual_interest_rate_bps" integer NOT NULL DEFAULT 0;
+
+UPDATE "account_types" SET "annual_interest_rate_bps" = 150 WHERE "name" = 'savings';
+
+INSERT INTO "account_types" ("name", "unique_per_currency") VALUES ('system', false);
+
+INSERT INTO "users" ("username", "hashed_password", "full_name", "email") VALUES ('bank', '!', 'Bank System', 'system@bank-api.local');
+
+INSERT INTO "accounts" ("id", "owner", "balance", "currency", "nickname", "type", "unique_per_currency") VALUES
+  ('sys-interest-expense-idr', 'bank', 0, 'IDR', 'Interest expense IDR', 'system', false),
+  ('sys-interest-expense-usd', 'bank', 0, 'USD', 'Interest expense USD', 'system', false),
+  ('sys-interest-expense-eur', 'bank', 0, 'EUR', 'Interest expense EUR', 'system', false);
+
+CREATE TABLE "interest_accruals" (
+  "id" varchar(100) PRIMARY KEY,
+  "account_id" varchar(100) NOT NULL,
+  "accrual_date" date NOT NULL,
+  "balance" bigint NOT NULL,
+  "rate_bps" integer NOT NULL,
+  "amount" bigint NOT NULL,
+  "transfer_id" varchar(100),
+  "posted_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "interest_accruals" ("account_id", "accrual_date");
+
+CREATE INDEX ON "interest_accruals" ("transfer_id");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
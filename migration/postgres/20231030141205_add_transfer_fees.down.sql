@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS "transfer_fees";
+
+ALTER TABLE IF EXISTS "entries" DROP COLUMN IF EXISTS "transfer_id";
+
+DELETE FROM "accounts" WHERE "id" IN ('sys-fee-revenue-idr', 'sys-fee-revenue-usd', 'sys-fee-revenue-eur');
+
+DROP TABLE IF EXISTS "fee_rules";
@@ -0,0 +1,51 @@
+CREATE TABLE "fee_rules" (
+  "id" varchar(100) PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "kind" varchar NOT NULL CHECK ("kind" IN ('flat', 'percentage')),
+  "flat_amount" bigint NOT NULL DEFAULT 0,
+  "rate_bps" integer NOT NULL DEFAULT 0,
+  "min_amount" bigint NOT NULL DEFAULT 0,
+  "max_amount" bigint,
+  "currency" varchar,
+  "account_type" varchar,
+  "active" boolean NOT NULL DEFAULT true,
+  "priority" integer NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "fee_rules" ADD FOREIGN KEY ("account_type") REFERENCES "account_types" ("name");
+
+INSERT INTO "accounts" ("id", "owner", "balance", "currency", "nickname", "type", "unique_per_currency") VALUES
+  ('sys-fee-revenue-idr', 'bank', 0, 'IDR', 'Fee revenue IDR', 'system', false),
+  ('sys-fee-revenue-usd', 'bank', 0, 'USD', 'Fee revenue USD', 'system', false),
+  ('sys-fee-revenue-eur', 'bank', 0, 'EUR', 'Fee revenue EUR', 'system', false);
+
+ALTER TABLE "entries" ADD COLUMN "transfer_id" varchar(100);
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "entries" ("transfer_id");
+
+CREATE TABLE "transfer_fees" (
+  "id" varchar(100) PRIMARY KEY,
+  "transfer_id" varchar(100) NOT NULL,
+  "rule_id" varchar(100) NOT NULL,
+  "name" varchar NOT NULL,
+  "amount" bigint NOT NULL,
+  "revenue_account_id" varchar(100) NOT NULL,
+  "sender_entry_id" varchar(100) NOT NULL,
+  "revenue_entry_id" varchar(100) NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "transfer_fees" ("transfer_id");
+
+ALTER TABLE "transfer_fees" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "transfer_fees" ADD FOREIGN KEY ("rule_id") REFERENCES "fee_rules" ("id");
+
+ALTER TABLE "transfer_fees" ADD FOREIGN KEY ("revenue_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_fees" ADD FOREIGN KEY ("sender_entry_id") REFERENCES "entries" ("id");
+
+ALTER TABLE "transfer_fees" ADD FOREIGN KEY ("revenue_entry_id") REFERENCES "entries" ("id");
@@ -0,0 +1,16 @@
+CREATE TABLE "transfer_limits" (
+  "id" varchar(100) PRIMARY KEY,
+  "scope" varchar NOT NULL CHECK ("scope" IN ('user', 'account')),
+  "subject" varchar NOT NULL,
+  "currency" varchar NOT NULL,
+  "max_single" bigint,
+  "max_daily" bigint,
+  "max_monthly" bigint,
+  "max_hourly_count" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX "transfer_limits_scope_subject_currency_key" ON "transfer_limits" ("scope", "subject", "currency");
+
+CREATE INDEX ON "transfers" ("sender_id", "created_at");
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS "transfers_pending_review_idx";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "reviewed_at";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "reviewed_by";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "risk_reasons";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "risk_score";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "status";
+
+ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "role";
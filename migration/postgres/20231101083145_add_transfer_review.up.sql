@@ -0,0 +1,15 @@
+ALTER TABLE "users" ADD COLUMN "role" varchar NOT NULL DEFAULT 'customer' CHECK ("role" IN ('customer', 'admin'));
+
+ALTER TABLE "transfers" ADD COLUMN "status" varchar NOT NULL DEFAULT 'completed' CHECK ("status" IN ('completed', 'pending_review', 'rejected'));
+
+ALTER TABLE "transfers" ADD COLUMN "risk_score" integer NOT NULL DEFAULT 0;
+
+ALTER TABLE "transfers" ADD COLUMN "risk_reasons" jsonb NOT NULL DEFAULT '[]';
+
+ALTER TABLE "transfers" ADD COLUMN "reviewed_by" varchar;
+
+ALTER TABLE "transfers" ADD COLUMN "reviewed_at" timestamptz;
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("reviewed_by") REFERENCES "users" ("username");
+
+CREATE INDEX "transfers_pending_review_idx" ON "transfers" ("created_at") WHERE "status" = 'pending_review';
@@ -0,0 +1,26 @@
+ALTER TABLE "accounts" ADD COLUMN "held_balance" bigint NOT NULL DEFAULT 0 CHECK ("held_balance" >= 0);
+
+CREATE TABLE "holds" (
+  "id" varchar(100) PRIMARY KEY,
+  "account_id" varchar(100) NOT NULL,
+  "receiver_id" varchar(100) NOT NULL,
+  "currency" varchar NOT NULL,
+  "amount" bigint NOT NULL CHECK ("amount" > 0),
+  "reserved" bigint NOT NULL,
+  "captured_amount" bigint NOT NULL DEFAULT 0,
+  "status" varchar NOT NULL DEFAULT 'authorized' CHECK ("status" IN ('authorized', 'captured', 'voided', 'expired')),
+  "transfer_id" varchar(100),
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "holds" ("account_id");
+
+CREATE INDEX "holds_authorized_expires_at_idx" ON "holds" ("expires_at") WHERE "status" = 'authorized';
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("receiver_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
@@ -0,0 +1,22 @@
+CREATE TABLE "batch_jobs" (
+  "id" varchar(100) PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "sender_id" varchar(100) NOT NULL,
+  "mode" varchar NOT NULL CHECK ("mode" IN ('all_or_nothing', 'best_effort')),
+  "item_count" integer NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'queued' CHECK ("status" IN ('queued', 'running', 'completed', 'failed')),
+  "request" jsonb NOT NULL,
+  "result" jsonb,
+  "error" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "started_at" timestamptz,
+  "finished_at" timestamptz
+);
+
+CREATE INDEX ON "batch_jobs" ("owner");
+
+CREATE INDEX "batch_jobs_queued_idx" ON "batch_jobs" ("created_at") WHERE "status" = 'queued';
+
+ALTER TABLE "batch_jobs" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "batch_jobs" ADD FOREIGN KEY ("sender_id") REFERENCES "accounts" ("id");
@@ -0,0 +1,17 @@
+CREATE TABLE "payees" (
+  "id" varchar(100) PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "nickname" varchar(64) NOT NULL,
+  "account_id" varchar(100) NOT NULL,
+  "currency" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX "payees_owner_account_id_key" ON "payees" ("owner", "account_id");
+
+CREATE UNIQUE INDEX "payees_owner_nickname_key" ON "payees" ("owner", "nickname");
+
+ALTER TABLE "payees" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "payees" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
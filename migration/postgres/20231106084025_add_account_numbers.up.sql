@@ -0,0 +1,12 @@
+ALTER TABLE "accounts" ADD COLUMN "number" varchar(34);
+
+-- Existing accounts get a random basic account number with ISO 13616 check
+-- digits: 98 - (bban || 'ID' || '00') mod 97, where I = 18 and D = 13.
+UPDATE "accounts" a
+SET "number" = 'ID' || lpad((98 - (n."bban" || '181300')::numeric % 97)::text, 2, '0') || n."bban"
+FROM (SELECT "id", lpad(floor(random() * 1e14)::bigint::text, 14, '0') AS "bban" FROM "accounts") n
+WHERE a."id" = n."id";
+
+ALTER TABLE "accounts" ALTER COLUMN "number" SET NOT NULL;
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_number_key" UNIQUE ("number");
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS "transfers_reference_idx";
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "metadata";
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "reference";
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "description";
@@ -0,0 +1,7 @@
+ALTER TABLE "transfers" ADD COLUMN "description" varchar(140) NOT NULL DEFAULT '';
+
+ALTER TABLE "transfers" ADD COLUMN "reference" varchar(35) NOT NULL DEFAULT '';
+
+ALTER TABLE "transfers" ADD COLUMN "metadata" jsonb NOT NULL DEFAULT '{}';
+
+CREATE INDEX "transfers_reference_idx" ON "transfers" ("reference") WHERE "reference" <> '';
@@ -0,0 +1,3 @@
+ALTER TABLE "sessions" DROP COLUMN IF EXISTS "last_renewed_at";
+
+ALTER TABLE "sessions" DROP COLUMN IF EXISTS "renewal_count";
@@ -0,0 +1,3 @@
+ALTER TABLE "sessions" ADD COLUMN "renewal_count" int NOT NULL DEFAULT 0;
+
+ALTER TABLE "sessions" ADD COLUMN "last_renewed_at" timestamptz;
@@ -0,0 +1 @@
+ALTER TABLE "accounts" ADD COLUMN "frozen_at" timestamptz;
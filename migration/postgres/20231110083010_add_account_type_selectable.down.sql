@@ -0,0 +1 @@
+ALTER TABLE "account_types" DROP COLUMN IF EXISTS "user_selectable";
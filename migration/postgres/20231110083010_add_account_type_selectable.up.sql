@@ -0,0 +1,3 @@
+ALTER TABLE "account_types" ADD COLUMN "user_selectable" boolean NOT NULL DEFAULT true;
+
+UPDATE "account_types" SET "user_selectable" = false WHERE "name" = 'system';
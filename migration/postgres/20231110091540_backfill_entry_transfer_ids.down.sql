@@ -0,0 +1,2 @@
+-- The backfilled links are valid before this migration too, so they are kept.
+SELECT 1;
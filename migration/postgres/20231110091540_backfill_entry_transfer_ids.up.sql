@@ -0,0 +1,23 @@
+-- Entries written before entries.transfer_id existed are linked to their
+-- transfer. The transfer and its two entries were inserted in one transaction,
+-- so they share created_at. Identical transfers in one transaction are paired
+-- with the entries in id order.
+WITH "legs" AS (
+  SELECT "id" AS "transfer_id", "sender_id" AS "account_id", -"amount" AS "amount", "created_at" FROM "transfers"
+  UNION ALL
+  SELECT "id", "receiver_id", "amount", "created_at" FROM "transfers"
+), "legacy_legs" AS (
+  SELECT l.*, row_number() OVER (PARTITION BY l."account_id", l."amount", l."created_at" ORDER BY l."transfer_id") AS "n"
+  FROM "legs" l
+  WHERE NOT EXISTS (SELECT 1 FROM "entries" e WHERE e."transfer_id" = l."transfer_id")
+), "legacy_entries" AS (
+  SELECT e."id", e."account_id", e."amount", e."created_at",
+    row_number() OVER (PARTITION BY e."account_id", e."amount", e."created_at" ORDER BY e."id") AS "n"
+  FROM "entries" e
+  WHERE e."transfer_id" IS NULL
+)
+UPDATE "entries" e
+SET "transfer_id" = l."transfer_id"
+FROM "legacy_entries" le
+JOIN "legacy_legs" l ON l."account_id" = le."account_id" AND l."amount" = le."amount" AND l."created_at" = le."created_at" AND l."n" = le."n"
+WHERE e."id" = le."id";
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "batch_jobs_running_idx";
+
+ALTER TABLE IF EXISTS "batch_jobs" DROP COLUMN IF EXISTS "attempts";
+
+ALTER TABLE IF EXISTS "batch_jobs" DROP COLUMN IF EXISTS "heartbeat_at";
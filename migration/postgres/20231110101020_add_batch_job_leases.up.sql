@@ -0,0 +1,7 @@
+ALTER TABLE "batch_jobs" ADD COLUMN "heartbeat_at" timestamptz;
+
+ALTER TABLE "batch_jobs" ADD COLUMN "attempts" integer NOT NULL DEFAULT 0;
+
+UPDATE "batch_jobs" SET "heartbeat_at" = "started_at", "attempts" = 1 WHERE "status" = 'running';
+
+CREATE INDEX "batch_jobs_running_idx" ON "batch_jobs" ("heartbeat_at") WHERE "status" = 'running';
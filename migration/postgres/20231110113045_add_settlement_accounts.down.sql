@@ -0,0 +1 @@
+DELETE FROM "accounts" WHERE "id" IN ('sys-settlement-idr', 'sys-settlement-usd', 'sys-settlement-eur');
@@ -0,0 +1,13 @@
+-- Money enters and leaves the bank through the settlement accounts, their
+-- numbers are generated like those of the accounts that existed when account
+-- numbers were introduced.
+INSERT INTO "accounts" ("id", "number", "owner", "balance", "currency", "nickname", "type", "unique_per_currency")
+SELECT s."id", 'ID' || lpad((98 - (s."bban" || '181300')::numeric % 97)::text, 2, '0') || s."bban", 'bank', 0, s."currency", s."nickname", 'system', false
+FROM (
+  SELECT v.*, lpad(floor(random() * 1e14)::bigint::text, 14, '0') AS "bban"
+  FROM (VALUES
+    ('sys-settlement-idr', 'IDR', 'Settlement IDR'),
+    ('sys-settlement-usd', 'USD', 'Settlement USD'),
+    ('sys-settlement-eur', 'EUR', 'Settlement EUR')
+  ) v ("id", "currency", "nickname")
+) s;
@@ -0,0 +1,21 @@
+-- Initial deposits were booked as a single entry without a transfer. Each
+-- becomes a transfer from the settlement account of its currency with the
+-- matching debit, so that the entries of every currency sum to zero.
+WITH "deposits" AS (
+  SELECT e."id" AS "entry_id", e."account_id", e."amount", e."created_at",
+    'sys-settlement-' || lower(a."currency") AS "settlement_id", gen_random_uuid()::text AS "transfer_id"
+  FROM "entries" e
+  JOIN "accounts" a ON a."id" = e."account_id"
+  WHERE e."transfer_id" IS NULL AND e."amount" > 0 AND a."owner" <> 'bank'
+), "booked" AS (
+  INSERT INTO "transfers" ("id", "sender_id", "receiver_id", "amount", "status", "description", "created_at")
+  SELECT "transfer_id", "settlement_id", "account_id", "amount", 'completed', 'Initial deposit', "created_at" FROM "deposits"
+), "linked" AS (
+  UPDATE "entries" e SET "transfer_id" = d."transfer_id" FROM "deposits" d WHERE e."id" = d."entry_id"
+)
+INSERT INTO "entries" ("id", "account_id", "amount", "transfer_id", "created_at")
+SELECT gen_random_uuid()::text, "settlement_id", -"amount", "transfer_id", "created_at" FROM "deposits";
+
+UPDATE "accounts" a
+SET "balance" = COALESCE((SELECT SUM(e."amount") FROM "entries" e WHERE e."account_id" = a."id"), 0)
+WHERE a."id" IN ('sys-settlement-idr', 'sys-settlement-usd', 'sys-settlement-eur');
@@ -0,0 +1,14 @@
+ALTER TABLE "user_mfa" ADD COLUMN "failed_attempts" integer NOT NULL DEFAULT 0;
+
+ALTER TABLE "user_mfa" ADD COLUMN "locked_until" timestamptz;
+
+CREATE TABLE "mfa_challenges" (
+  "id" varchar(100) PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "used_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "mfa_challenges" ("expires_at");
+
+ALTER TABLE "mfa_challenges" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
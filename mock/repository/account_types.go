@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/account_types.go
+
+// Package mockrepo is a generated GoMock package.
+package mockrepo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/terajari/bank-api/model"
+)
+
+// MockAccountTypesRepository is a mock of AccountTypesRepository interface.
+type MockAccountTypesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountTypesRepositoryMockRecorder
+}
+
+// MockAccountTypesRepositoryMockRecorder is the mock recorder for MockAccountTypesRepository.
+type MockAccountTypesRepositoryMockRecorder struct {
+	mock *MockAccountTypesRepository
+}
+
+// NewMockAccountTypesRepository creates a new mock instance.
+func NewMockAccountTypesRepository(ctrl *gomock.Controller) *MockAccountTypesRepository {
+	mock := &MockAccountTypesRepository{ctrl: ctrl}
+	mock.recorder = &MockAccountTypesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountTypesRepository) EXPECT() *MockAccountTypesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockAccountTypesRepository) Get(ctx context.Context, name string) (model.AccountType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, name)
+	ret0, _ := ret[0].(model.AccountType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAccountTypesRepositoryMockRecorder) Get(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAccountTypesRepository)(nil).Get), ctx, name)
+}
+
+// List mocks base method.
+func (m *MockAccountTypesRepository) List(ctx context.Context) ([]model.AccountType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]model.AccountType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAccountTypesRepositoryMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAccountTypesRepository)(nil).List), ctx)
+}
@@ -35,6 +35,21 @@ func (m *MockAccountsRepository) EXPECT() *MockAccountsRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddBalance mocks base method.
+func (m *MockAccountsRepository) AddBalance(ctx context.Context, id string, amount int64) (model.Accounts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBalance", ctx, id, amount)
+	ret0, _ := ret[0].(model.Accounts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddBalance indicates an expected call of AddBalance.
+func (mr *MockAccountsRepositoryMockRecorder) AddBalance(ctx, id, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBalance", reflect.TypeOf((*MockAccountsRepository)(nil).AddBalance), ctx, id, amount)
+}
+
 // Create mocks base method.
 func (m *MockAccountsRepository) Create(ctx context.Context, account model.Accounts) (model.Accounts, error) {
 	m.ctrl.T.Helper()
@@ -79,6 +94,21 @@ func (mr *MockAccountsRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAccountsRepository)(nil).Get), ctx, id)
 }
 
+// GetByNumber mocks base method.
+func (m *MockAccountsRepository) GetByNumber(ctx context.Context, number string) (model.Accounts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByNumber", ctx, number)
+	ret0, _ := ret[0].(model.Accounts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByNumber indicates an expected call of GetByNumber.
+func (mr *MockAccountsRepositoryMockRecorder) GetByNumber(ctx, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByNumber", reflect.TypeOf((*MockAccountsRepository)(nil).GetByNumber), ctx, number)
+}
+
 // GetForUpdate mocks base method.
 func (m *MockAccountsRepository) GetForUpdate(ctx context.Context, id string) (model.Accounts, error) {
 	m.ctrl.T.Helper()
@@ -95,18 +125,33 @@ func (mr *MockAccountsRepositoryMockRecorder) GetForUpdate(ctx, id interface{})
 }
 
 // List mocks base method.
-func (m *MockAccountsRepository) List(ctx context.Context, owner string, limit, offset int) ([]model.Accounts, error) {
+func (m *MockAccountsRepository) List(ctx context.Context, filter model.AccountsFilter, limit, offset int) ([]model.Accounts, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx, owner, limit, offset)
+	ret := m.ctrl.Call(m, "List", ctx, filter, limit, offset)
 	ret0, _ := ret[0].([]model.Accounts)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockAccountsRepositoryMockRecorder) List(ctx, owner, limit, offset interface{}) *gomock.Call {
+func (mr *MockAccountsRepositoryMockRecorder) List(ctx, filter, limit, offset interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAccountsRepository)(nil).List), ctx, owner, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAccountsRepository)(nil).List), ctx, filter, limit, offset)
+}
+
+// SetFrozen mocks base method.
+func (m *MockAccountsRepository) SetFrozen(ctx context.Context, id string, frozen bool) (model.Accounts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFrozen", ctx, id, frozen)
+	ret0, _ := ret[0].(model.Accounts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetFrozen indicates an expected call of SetFrozen.
+func (mr *MockAccountsRepositoryMockRecorder) SetFrozen(ctx, id, frozen interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFrozen", reflect.TypeOf((*MockAccountsRepository)(nil).SetFrozen), ctx, id, frozen)
 }
 
 // Update mocks base method.
@@ -123,3 +168,18 @@ func (mr *MockAccountsRepositoryMockRecorder) Update(ctx, account interface{}) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAccountsRepository)(nil).Update), ctx, account)
 }
+
+// UpdateNickname mocks base method.
+func (m *MockAccountsRepository) UpdateNickname(ctx context.Context, id, nickname string) (model.Accounts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNickname", ctx, id, nickname)
+	ret0, _ := ret[0].(model.Accounts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNickname indicates an expected call of UpdateNickname.
+func (mr *MockAccountsRepositoryMockRecorder) UpdateNickname(ctx, id, nickname interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNickname", reflect.TypeOf((*MockAccountsRepository)(nil).UpdateNickname), ctx, id, nickname)
+}
@@ -0,0 +1,141 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/batch.go
+
+// Package mockrepo is a generated GoMock package.
+package mockrepo
+
+import (
+	context "context"
+	json "encoding/json"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/terajari/bank-api/model"
+)
+
+// MockBatchJobsRepository is a mock of BatchJobsRepository interface.
+type MockBatchJobsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchJobsRepositoryMockRecorder
+}
+
+// MockBatchJobsRepositoryMockRecorder is the mock recorder for MockBatchJobsRepository.
+type MockBatchJobsRepositoryMockRecorder struct {
+	mock *MockBatchJobsRepository
+}
+
+// NewMockBatchJobsRepository creates a new mock instance.
+func NewMockBatchJobsRepository(ctrl *gomock.Controller) *MockBatchJobsRepository {
+	mock := &MockBatchJobsRepository{ctrl: ctrl}
+	mock.recorder = &MockBatchJobsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatchJobsRepository) EXPECT() *MockBatchJobsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimNext mocks base method.
+func (m *MockBatchJobsRepository) ClaimNext(ctx context.Context) (model.BatchJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimNext", ctx)
+	ret0, _ := ret[0].(model.BatchJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimNext indicates an expected call of ClaimNext.
+func (mr *MockBatchJobsRepositoryMockRecorder) ClaimNext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimNext", reflect.TypeOf((*MockBatchJobsRepository)(nil).ClaimNext), ctx)
+}
+
+// Create mocks base method.
+func (m *MockBatchJobsRepository) Create(ctx context.Context, job model.BatchJob) (model.BatchJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, job)
+	ret0, _ := ret[0].(model.BatchJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockBatchJobsRepositoryMockRecorder) Create(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBatchJobsRepository)(nil).Create), ctx, job)
+}
+
+// Finish mocks base method.
+func (m *MockBatchJobsRepository) Finish(ctx context.Context, id, status string, result json.RawMessage, errMsg string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Finish", ctx, id, status, result, errMsg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Finish indicates an expected call of Finish.
+func (mr *MockBatchJobsRepositoryMockRecorder) Finish(ctx, id, status, result, errMsg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Finish", reflect.TypeOf((*MockBatchJobsRepository)(nil).Finish), ctx, id, status, result, errMsg)
+}
+
+// Get mocks base method.
+func (m *MockBatchJobsRepository) Get(ctx context.Context, id string) (model.BatchJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(model.BatchJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockBatchJobsRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBatchJobsRepository)(nil).Get), ctx, id)
+}
+
+// Heartbeat mocks base method.
+func (m *MockBatchJobsRepository) Heartbeat(ctx context.Context, id string, progress json.RawMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Heartbeat", ctx, id, progress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Heartbeat indicates an expected call of Heartbeat.
+func (mr *MockBatchJobsRepositoryMockRecorder) Heartbeat(ctx, id, progress interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Heartbeat", reflect.TypeOf((*MockBatchJobsRepository)(nil).Heartbeat), ctx, id, progress)
+}
+
+// Requeue mocks base method.
+func (m *MockBatchJobsRepository) Requeue(ctx context.Context, id string, progress json.RawMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Requeue", ctx, id, progress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Requeue indicates an expected call of Requeue.
+func (mr *MockBatchJobsRepositoryMockRecorder) Requeue(ctx, id, progress interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Requeue", reflect.TypeOf((*MockBatchJobsRepository)(nil).Requeue), ctx, id, progress)
+}
+
+// RequeueStale mocks base method.
+func (m *MockBatchJobsRepository) RequeueStale(ctx context.Context, staleBefore time.Time, maxAttempts int) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueStale", ctx, staleBefore, maxAttempts)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RequeueStale indicates an expected call of RequeueStale.
+func (mr *MockBatchJobsRepositoryMockRecorder) RequeueStale(ctx, staleBefore, maxAttempts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueStale", reflect.TypeOf((*MockBatchJobsRepository)(nil).RequeueStale), ctx, staleBefore, maxAttempts)
+}
@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/entries.go
+
+// Package mockrepo is a generated GoMock package.
+package mockrepo
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/terajari/bank-api/model"
+)
+
+// MockEntryRepository is a mock of EntryRepository interface.
+type MockEntryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockEntryRepositoryMockRecorder
+}
+
+// MockEntryRepositoryMockRecorder is the mock recorder for MockEntryRepository.
+type MockEntryRepositoryMockRecorder struct {
+	mock *MockEntryRepository
+}
+
+// NewMockEntryRepository creates a new mock instance.
+func NewMockEntryRepository(ctrl *gomock.Controller) *MockEntryRepository {
+	mock := &MockEntryRepository{ctrl: ctrl}
+	mock.recorder = &MockEntryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEntryRepository) EXPECT() *MockEntryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockEntryRepository) Create(ctx context.Context, entry model.Entries) (model.Entries, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, entry)
+	ret0, _ := ret[0].(model.Entries)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockEntryRepositoryMockRecorder) Create(ctx, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEntryRepository)(nil).Create), ctx, entry)
+}
+
+// Get mocks base method.
+func (m *MockEntryRepository) Get(ctx context.Context, id string) (model.Entries, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(model.Entries)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockEntryRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockEntryRepository)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockEntryRepository) List(ctx context.Context, accountId string, limit, offset int) ([]model.Entries, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, accountId, limit, offset)
+	ret0, _ := ret[0].([]model.Entries)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockEntryRepositoryMockRecorder) List(ctx, accountId, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockEntryRepository)(nil).List), ctx, accountId, limit, offset)
+}
+
+// StreamStatement mocks base method.
+func (m *MockEntryRepository) StreamStatement(ctx context.Context, accountId string, from, to time.Time, begin func(int64, int64) error, each func(model.StatementEntry) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamStatement", ctx, accountId, from, to, begin, each)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamStatement indicates an expected call of StreamStatement.
+func (mr *MockEntryRepositoryMockRecorder) StreamStatement(ctx, accountId, from, to, begin, each interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamStatement", reflect.TypeOf((*MockEntryRepository)(nil).StreamStatement), ctx, accountId, from, to, begin, each)
+}
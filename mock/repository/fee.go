@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/fee.go
+
+// Package mockrepo is a generated GoMock package.
+package mockrepo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/terajari/bank-api/model"
+)
+
+// MockFeeRulesRepository is a mock of FeeRulesRepository interface.
+type MockFeeRulesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeeRulesRepositoryMockRecorder
+}
+
+// MockFeeRulesRepositoryMockRecorder is the mock recorder for MockFeeRulesRepository.
+type MockFeeRulesRepositoryMockRecorder struct {
+	mock *MockFeeRulesRepository
+}
+
+// NewMockFeeRulesRepository creates a new mock instance.
+func NewMockFeeRulesRepository(ctrl *gomock.Controller) *MockFeeRulesRepository {
+	mock := &MockFeeRulesRepository{ctrl: ctrl}
+	mock.recorder = &MockFeeRulesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeeRulesRepository) EXPECT() *MockFeeRulesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListActive mocks base method.
+func (m *MockFeeRulesRepository) ListActive(ctx context.Context, currency, accountType string) ([]model.FeeRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActive", ctx, currency, accountType)
+	ret0, _ := ret[0].([]model.FeeRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActive indicates an expected call of ListActive.
+func (mr *MockFeeRulesRepositoryMockRecorder) ListActive(ctx, currency, accountType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActive", reflect.TypeOf((*MockFeeRulesRepository)(nil).ListActive), ctx, currency, accountType)
+}
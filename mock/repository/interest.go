@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/interest.go
+
+// Package mockrepo is a generated GoMock package.
+package mockrepo
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/terajari/bank-api/model"
+)
+
+// MockInterestRepository is a mock of InterestRepository interface.
+type MockInterestRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockInterestRepositoryMockRecorder
+}
+
+// MockInterestRepositoryMockRecorder is the mock recorder for MockInterestRepository.
+type MockInterestRepositoryMockRecorder struct {
+	mock *MockInterestRepository
+}
+
+// NewMockInterestRepository creates a new mock instance.
+func NewMockInterestRepository(ctrl *gomock.Controller) *MockInterestRepository {
+	mock := &MockInterestRepository{ctrl: ctrl}
+	mock.recorder = &MockInterestRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInterestRepository) EXPECT() *MockInterestRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateAccrual mocks base method.
+func (m *MockInterestRepository) CreateAccrual(ctx context.Context, accrual model.InterestAccrual) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccrual", ctx, accrual)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccrual indicates an expected call of CreateAccrual.
+func (mr *MockInterestRepositoryMockRecorder) CreateAccrual(ctx, accrual interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccrual", reflect.TypeOf((*MockInterestRepository)(nil).CreateAccrual), ctx, accrual)
+}
+
+// LastAccrualDate mocks base method.
+func (m *MockInterestRepository) LastAccrualDate(ctx context.Context) (time.Time, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastAccrualDate", ctx)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// LastAccrualDate indicates an expected call of LastAccrualDate.
+func (mr *MockInterestRepositoryMockRecorder) LastAccrualDate(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastAccrualDate", reflect.TypeOf((*MockInterestRepository)(nil).LastAccrualDate), ctx)
+}
+
+// ListCandidates mocks base method.
+func (m *MockInterestRepository) ListCandidates(ctx context.Context, endOfDay time.Time) ([]model.InterestCandidate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCandidates", ctx, endOfDay)
+	ret0, _ := ret[0].([]model.InterestCandidate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCandidates indicates an expected call of ListCandidates.
+func (mr *MockInterestRepositoryMockRecorder) ListCandidates(ctx, endOfDay interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCandidates", reflect.TypeOf((*MockInterestRepository)(nil).ListCandidates), ctx, endOfDay)
+}
+
+// ListUnposted mocks base method.
+func (m *MockInterestRepository) ListUnposted(ctx context.Context, before time.Time) ([]model.InterestPosting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnposted", ctx, before)
+	ret0, _ := ret[0].([]model.InterestPosting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnposted indicates an expected call of ListUnposted.
+func (mr *MockInterestRepositoryMockRecorder) ListUnposted(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnposted", reflect.TypeOf((*MockInterestRepository)(nil).ListUnposted), ctx, before)
+}
+
+// MarkPosted mocks base method.
+func (m *MockInterestRepository) MarkPosted(ctx context.Context, accountId string, before time.Time, transferId string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPosted", ctx, accountId, before, transferId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkPosted indicates an expected call of MarkPosted.
+func (mr *MockInterestRepositoryMockRecorder) MarkPosted(ctx, accountId, before, transferId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPosted", reflect.TypeOf((*MockInterestRepository)(nil).MarkPosted), ctx, accountId, before, transferId)
+}
@@ -0,0 +1,155 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/mfa.go
+
+// Package mockrepo is a generated GoMock package.
+package mockrepo
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/terajari/bank-api/model"
+)
+
+// MockMfaRepository is a mock of MfaRepository interface.
+type MockMfaRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMfaRepositoryMockRecorder
+}
+
+// MockMfaRepositoryMockRecorder is the mock recorder for MockMfaRepository.
+type MockMfaRepositoryMockRecorder struct {
+	mock *MockMfaRepository
+}
+
+// NewMockMfaRepository creates a new mock instance.
+func NewMockMfaRepository(ctrl *gomock.Controller) *MockMfaRepository {
+	mock := &MockMfaRepository{ctrl: ctrl}
+	mock.recorder = &MockMfaRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMfaRepository) EXPECT() *MockMfaRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountAttempt mocks base method.
+func (m *MockMfaRepository) CountAttempt(ctx context.Context, username string, maxAttempts int, lockout time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAttempt", ctx, username, maxAttempts, lockout)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAttempt indicates an expected call of CountAttempt.
+func (mr *MockMfaRepositoryMockRecorder) CountAttempt(ctx, username, maxAttempts, lockout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAttempt", reflect.TypeOf((*MockMfaRepository)(nil).CountAttempt), ctx, username, maxAttempts, lockout)
+}
+
+// Enable mocks base method.
+func (m *MockMfaRepository) Enable(ctx context.Context, username string, step int64, codes []model.RecoveryCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enable", ctx, username, step, codes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enable indicates an expected call of Enable.
+func (mr *MockMfaRepositoryMockRecorder) Enable(ctx, username, step, codes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enable", reflect.TypeOf((*MockMfaRepository)(nil).Enable), ctx, username, step, codes)
+}
+
+// Get mocks base method.
+func (m *MockMfaRepository) Get(ctx context.Context, username string) (model.UserMfa, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, username)
+	ret0, _ := ret[0].(model.UserMfa)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockMfaRepositoryMockRecorder) Get(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockMfaRepository)(nil).Get), ctx, username)
+}
+
+// ResetAttempts mocks base method.
+func (m *MockMfaRepository) ResetAttempts(ctx context.Context, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetAttempts", ctx, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetAttempts indicates an expected call of ResetAttempts.
+func (mr *MockMfaRepositoryMockRecorder) ResetAttempts(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetAttempts", reflect.TypeOf((*MockMfaRepository)(nil).ResetAttempts), ctx, username)
+}
+
+// Upsert mocks base method.
+func (m *MockMfaRepository) Upsert(ctx context.Context, mfa model.UserMfa) (model.UserMfa, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, mfa)
+	ret0, _ := ret[0].(model.UserMfa)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockMfaRepositoryMockRecorder) Upsert(ctx, mfa interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockMfaRepository)(nil).Upsert), ctx, mfa)
+}
+
+// UseChallenge mocks base method.
+func (m *MockMfaRepository) UseChallenge(ctx context.Context, id, username string, expiresAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseChallenge", ctx, id, username, expiresAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseChallenge indicates an expected call of UseChallenge.
+func (mr *MockMfaRepositoryMockRecorder) UseChallenge(ctx, id, username, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseChallenge", reflect.TypeOf((*MockMfaRepository)(nil).UseChallenge), ctx, id, username, expiresAt)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockMfaRepository) UseRecoveryCode(ctx context.Context, username, codeHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", ctx, username, codeHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockMfaRepositoryMockRecorder) UseRecoveryCode(ctx, username, codeHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockMfaRepository)(nil).UseRecoveryCode), ctx, username, codeHash)
+}
+
+// UseStep mocks base method.
+func (m *MockMfaRepository) UseStep(ctx context.Context, username string, step int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseStep", ctx, username, step)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseStep indicates an expected call of UseStep.
+func (mr *MockMfaRepositoryMockRecorder) UseStep(ctx, username, step interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseStep", reflect.TypeOf((*MockMfaRepository)(nil).UseStep), ctx, username, step)
+}
@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/payees.go
+
+// Package mockrepo is a generated GoMock package.
+package mockrepo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/terajari/bank-api/model"
+)
+
+// MockPayeesRepository is a mock of PayeesRepository interface.
+type MockPayeesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPayeesRepositoryMockRecorder
+}
+
+// MockPayeesRepositoryMockRecorder is the mock recorder for MockPayeesRepository.
+type MockPayeesRepositoryMockRecorder struct {
+	mock *MockPayeesRepository
+}
+
+// NewMockPayeesRepository creates a new mock instance.
+func NewMockPayeesRepository(ctrl *gomock.Controller) *MockPayeesRepository {
+	mock := &MockPayeesRepository{ctrl: ctrl}
+	mock.recorder = &MockPayeesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPayeesRepository) EXPECT() *MockPayeesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPayeesRepository) Create(ctx context.Context, payee model.Payee) (model.Payee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, payee)
+	ret0, _ := ret[0].(model.Payee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPayeesRepositoryMockRecorder) Create(ctx, payee interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPayeesRepository)(nil).Create), ctx, payee)
+}
+
+// Delete mocks base method.
+func (m *MockPayeesRepository) Delete(ctx context.Context, id, owner string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id, owner)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPayeesRepositoryMockRecorder) Delete(ctx, id, owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPayeesRepository)(nil).Delete), ctx, id, owner)
+}
+
+// Get mocks base method.
+func (m *MockPayeesRepository) Get(ctx context.Context, id, owner string) (model.Payee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id, owner)
+	ret0, _ := ret[0].(model.Payee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPayeesRepositoryMockRecorder) Get(ctx, id, owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPayeesRepository)(nil).Get), ctx, id, owner)
+}
+
+// List mocks base method.
+func (m *MockPayeesRepository) List(ctx context.Context, owner string, limit, offset int) ([]model.Payee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, owner, limit, offset)
+	ret0, _ := ret[0].([]model.Payee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockPayeesRepositoryMockRecorder) List(ctx, owner, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPayeesRepository)(nil).List), ctx, owner, limit, offset)
+}
+
+// UpdateNickname mocks base method.
+func (m *MockPayeesRepository) UpdateNickname(ctx context.Context, id, owner, nickname string) (model.Payee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNickname", ctx, id, owner, nickname)
+	ret0, _ := ret[0].(model.Payee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNickname indicates an expected call of UpdateNickname.
+func (mr *MockPayeesRepositoryMockRecorder) UpdateNickname(ctx, id, owner, nickname interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNickname", reflect.TypeOf((*MockPayeesRepository)(nil).UpdateNickname), ctx, id, owner, nickname)
+}
@@ -0,0 +1,231 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/repositories.go
+
+// Package mockrepo is a generated GoMock package.
+package mockrepo
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	repository "github.com/terajari/bank-api/repository"
+)
+
+// MockRepositories is a mock of Repositories interface.
+type MockRepositories struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoriesMockRecorder
+}
+
+// MockRepositoriesMockRecorder is the mock recorder for MockRepositories.
+type MockRepositoriesMockRecorder struct {
+	mock *MockRepositories
+}
+
+// NewMockRepositories creates a new mock instance.
+func NewMockRepositories(ctrl *gomock.Controller) *MockRepositories {
+	mock := &MockRepositories{ctrl: ctrl}
+	mock.recorder = &MockRepositoriesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepositories) EXPECT() *MockRepositoriesMockRecorder {
+	return m.recorder
+}
+
+// AccountTypesRepo mocks base method.
+func (m *MockRepositories) AccountTypesRepo() repository.AccountTypesRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountTypesRepo")
+	ret0, _ := ret[0].(repository.AccountTypesRepository)
+	return ret0
+}
+
+// AccountTypesRepo indicates an expected call of AccountTypesRepo.
+func (mr *MockRepositoriesMockRecorder) AccountTypesRepo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountTypesRepo", reflect.TypeOf((*MockRepositories)(nil).AccountTypesRepo))
+}
+
+// AccountsRepo mocks base method.
+func (m *MockRepositories) AccountsRepo() repository.AccountsRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountsRepo")
+	ret0, _ := ret[0].(repository.AccountsRepository)
+	return ret0
+}
+
+// AccountsRepo indicates an expected call of AccountsRepo.
+func (mr *MockRepositoriesMockRecorder) AccountsRepo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountsRepo", reflect.TypeOf((*MockRepositories)(nil).AccountsRepo))
+}
+
+// BatchJobsRepo mocks base method.
+func (m *MockRepositories) BatchJobsRepo() repository.BatchJobsRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchJobsRepo")
+	ret0, _ := ret[0].(repository.BatchJobsRepository)
+	return ret0
+}
+
+// BatchJobsRepo indicates an expected call of BatchJobsRepo.
+func (mr *MockRepositoriesMockRecorder) BatchJobsRepo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchJobsRepo", reflect.TypeOf((*MockRepositories)(nil).BatchJobsRepo))
+}
+
+// EntryRepo mocks base method.
+func (m *MockRepositories) EntryRepo() repository.EntryRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EntryRepo")
+	ret0, _ := ret[0].(repository.EntryRepository)
+	return ret0
+}
+
+// EntryRepo indicates an expected call of EntryRepo.
+func (mr *MockRepositoriesMockRecorder) EntryRepo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EntryRepo", reflect.TypeOf((*MockRepositories)(nil).EntryRepo))
+}
+
+// FeeRulesRepo mocks base method.
+func (m *MockRepositories) FeeRulesRepo() repository.FeeRulesRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FeeRulesRepo")
+	ret0, _ := ret[0].(repository.FeeRulesRepository)
+	return ret0
+}
+
+// FeeRulesRepo indicates an expected call of FeeRulesRepo.
+func (mr *MockRepositoriesMockRecorder) FeeRulesRepo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FeeRulesRepo", reflect.TypeOf((*MockRepositories)(nil).FeeRulesRepo))
+}
+
+// HoldsRepo mocks base method.
+func (m *MockRepositories) HoldsRepo() repository.HoldsRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HoldsRepo")
+	ret0, _ := ret[0].(repository.HoldsRepository)
+	return ret0
+}
+
+// HoldsRepo indicates an expected call of HoldsRepo.
+func (mr *MockRepositoriesMockRecorder) HoldsRepo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HoldsRepo", reflect.TypeOf((*MockRepositories)(nil).HoldsRepo))
+}
+
+// InterestRepo mocks base method.
+func (m *MockRepositories) InterestRepo() repository.InterestRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InterestRepo")
+	ret0, _ := ret[0].(repository.InterestRepository)
+	return ret0
+}
+
+// InterestRepo indicates an expected call of InterestRepo.
+func (mr *MockRepositoriesMockRecorder) InterestRepo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InterestRepo", reflect.TypeOf((*MockRepositories)(nil).InterestRepo))
+}
+
+// LedgerRepo mocks base method.
+func (m *MockRepositories) LedgerRepo() repository.LedgerRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LedgerRepo")
+	ret0, _ := ret[0].(repository.LedgerRepository)
+	return ret0
+}
+
+// LedgerRepo indicates an expected call of LedgerRepo.
+func (mr *MockRepositoriesMockRecorder) LedgerRepo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LedgerRepo", reflect.TypeOf((*MockRepositories)(nil).LedgerRepo))
+}
+
+// MfaRepo mocks base method.
+func (m *MockRepositories) MfaRepo() repository.MfaRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MfaRepo")
+	ret0, _ := ret[0].(repository.MfaRepository)
+	return ret0
+}
+
+// MfaRepo indicates an expected call of MfaRepo.
+func (mr *MockRepositoriesMockRecorder) MfaRepo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MfaRepo", reflect.TypeOf((*MockRepositories)(nil).MfaRepo))
+}
+
+// PayeesRepo mocks base method.
+func (m *MockRepositories) PayeesRepo() repository.PayeesRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PayeesRepo")
+	ret0, _ := ret[0].(repository.PayeesRepository)
+	return ret0
+}
+
+// PayeesRepo indicates an expected call of PayeesRepo.
+func (mr *MockRepositoriesMockRecorder) PayeesRepo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PayeesRepo", reflect.TypeOf((*MockRepositories)(nil).PayeesRepo))
+}
+
+// SchemaRepo mocks base method.
+func (m *MockRepositories) SchemaRepo() repository.SchemaRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SchemaRepo")
+	ret0, _ := ret[0].(repository.SchemaRepository)
+	return ret0
+}
+
+// SchemaRepo indicates an expected call of SchemaRepo.
+func (mr *MockRepositoriesMockRecorder) SchemaRepo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchemaRepo", reflect.TypeOf((*MockRepositories)(nil).SchemaRepo))
+}
+
+// SessionsRepo mocks base method.
+func (m *MockRepositories) SessionsRepo() repository.SessionsRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SessionsRepo")
+	ret0, _ := ret[0].(repository.SessionsRepository)
+	return ret0
+}
+
+// SessionsRepo indicates an expected call of SessionsRepo.
+func (mr *MockRepositoriesMockRecorder) SessionsRepo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SessionsRepo", reflect.TypeOf((*MockRepositories)(nil).SessionsRepo))
+}
+
+// TransferRepo mocks base method.
+func (m *MockRepositories) TransferRepo() repository.TransferRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferRepo")
+	ret0, _ := ret[0].(repository.TransferRepository)
+	return ret0
+}
+
+// TransferRepo indicates an expected call of TransferRepo.
+func (mr *MockRepositoriesMockRecorder) TransferRepo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferRepo", reflect.TypeOf((*MockRepositories)(nil).TransferRepo))
+}
+
+// UsersRepo mocks base method.
+func (m *MockRepositories) UsersRepo() repository.UsersRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UsersRepo")
+	ret0, _ := ret[0].(repository.UsersRepository)
+	return ret0
+}
+
+// UsersRepo indicates an expected call of UsersRepo.
+func (mr *MockRepositoriesMockRecorder) UsersRepo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UsersRepo", reflect.TypeOf((*MockRepositories)(nil).UsersRepo))
+}
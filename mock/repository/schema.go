@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/schema.go
+
+// Package mockrepo is a generated GoMock package.
+package mockrepo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSchemaRepository is a mock of SchemaRepository interface.
+type MockSchemaRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSchemaRepositoryMockRecorder
+}
+
+// MockSchemaRepositoryMockRecorder is the mock recorder for MockSchemaRepository.
+type MockSchemaRepositoryMockRecorder struct {
+	mock *MockSchemaRepository
+}
+
+// NewMockSchemaRepository creates a new mock instance.
+func NewMockSchemaRepository(ctrl *gomock.Controller) *MockSchemaRepository {
+	mock := &MockSchemaRepository{ctrl: ctrl}
+	mock.recorder = &MockSchemaRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSchemaRepository) EXPECT() *MockSchemaRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Version mocks base method.
+func (m *MockSchemaRepository) Version(ctx context.Context) (int64, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Version", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Version indicates an expected call of Version.
+func (mr *MockSchemaRepositoryMockRecorder) Version(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Version", reflect.TypeOf((*MockSchemaRepository)(nil).Version), ctx)
+}
@@ -0,0 +1,112 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/sessions.go
+
+// Package mockrepo is a generated GoMock package.
+package mockrepo
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	model "github.com/terajari/bank-api/model"
+)
+
+// MockSessionsRepository is a mock of SessionsRepository interface.
+type MockSessionsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionsRepositoryMockRecorder
+}
+
+// MockSessionsRepositoryMockRecorder is the mock recorder for MockSessionsRepository.
+type MockSessionsRepositoryMockRecorder struct {
+	mock *MockSessionsRepository
+}
+
+// NewMockSessionsRepository creates a new mock instance.
+func NewMockSessionsRepository(ctrl *gomock.Controller) *MockSessionsRepository {
+	mock := &MockSessionsRepository{ctrl: ctrl}
+	mock.recorder = &MockSessionsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionsRepository) EXPECT() *MockSessionsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSessionsRepository) Create(ctx context.Context, sessions model.Sessions) (model.Sessions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, sessions)
+	ret0, _ := ret[0].(model.Sessions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSessionsRepositoryMockRecorder) Create(ctx, sessions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSessionsRepository)(nil).Create), ctx, sessions)
+}
+
+// Get mocks base method.
+func (m *MockSessionsRepository) Get(ctx context.Context, id uuid.UUID) (model.Sessions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(model.Sessions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockSessionsRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSessionsRepository)(nil).Get), ctx, id)
+}
+
+// Last mocks base method.
+func (m *MockSessionsRepository) Last(ctx context.Context) (model.Sessions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Last", ctx)
+	ret0, _ := ret[0].(model.Sessions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Last indicates an expected call of Last.
+func (mr *MockSessionsRepositoryMockRecorder) Last(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Last", reflect.TypeOf((*MockSessionsRepository)(nil).Last), ctx)
+}
+
+// Renew mocks base method.
+func (m *MockSessionsRepository) Renew(ctx context.Context, id uuid.UUID, oldRefreshToken, refreshToken string, expiresAt time.Time) (model.Sessions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Renew", ctx, id, oldRefreshToken, refreshToken, expiresAt)
+	ret0, _ := ret[0].(model.Sessions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Renew indicates an expected call of Renew.
+func (mr *MockSessionsRepositoryMockRecorder) Renew(ctx, id, oldRefreshToken, refreshToken, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Renew", reflect.TypeOf((*MockSessionsRepository)(nil).Renew), ctx, id, oldRefreshToken, refreshToken, expiresAt)
+}
+
+// Update mocks base method.
+func (m *MockSessionsRepository) Update(ctx context.Context, id uuid.UUID, isBlocked bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, isBlocked)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockSessionsRepositoryMockRecorder) Update(ctx, id, isBlocked interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSessionsRepository)(nil).Update), ctx, id, isBlocked)
+}
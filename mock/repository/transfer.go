@@ -0,0 +1,231 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/transfer.go
+
+// Package mockrepo is a generated GoMock package.
+package mockrepo
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "github.com/terajari/bank-api/dto"
+	model "github.com/terajari/bank-api/model"
+	repository "github.com/terajari/bank-api/repository"
+)
+
+// MockTransferRepository is a mock of TransferRepository interface.
+type MockTransferRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransferRepositoryMockRecorder
+}
+
+// MockTransferRepositoryMockRecorder is the mock recorder for MockTransferRepository.
+type MockTransferRepositoryMockRecorder struct {
+	mock *MockTransferRepository
+}
+
+// NewMockTransferRepository creates a new mock instance.
+func NewMockTransferRepository(ctrl *gomock.Controller) *MockTransferRepository {
+	mock := &MockTransferRepository{ctrl: ctrl}
+	mock.recorder = &MockTransferRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransferRepository) EXPECT() *MockTransferRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ApproveTx mocks base method.
+func (m *MockTransferRepository) ApproveTx(ctx context.Context, id, reviewer string, fees []model.TransferFee) (dto.MakeTransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveTx", ctx, id, reviewer, fees)
+	ret0, _ := ret[0].(dto.MakeTransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveTx indicates an expected call of ApproveTx.
+func (mr *MockTransferRepositoryMockRecorder) ApproveTx(ctx, id, reviewer, fees interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveTx", reflect.TypeOf((*MockTransferRepository)(nil).ApproveTx), ctx, id, reviewer, fees)
+}
+
+// Create mocks base method.
+func (m *MockTransferRepository) Create(ctx context.Context, transfer model.Transfer) (model.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, transfer)
+	ret0, _ := ret[0].(model.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTransferRepositoryMockRecorder) Create(ctx, transfer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransferRepository)(nil).Create), ctx, transfer)
+}
+
+// Get mocks base method.
+func (m *MockTransferRepository) Get(ctx context.Context, id string) (model.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(model.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTransferRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTransferRepository)(nil).Get), ctx, id)
+}
+
+// HasPaid mocks base method.
+func (m *MockTransferRepository) HasPaid(ctx context.Context, owner, receiverId string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasPaid", ctx, owner, receiverId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasPaid indicates an expected call of HasPaid.
+func (mr *MockTransferRepositoryMockRecorder) HasPaid(ctx, owner, receiverId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasPaid", reflect.TypeOf((*MockTransferRepository)(nil).HasPaid), ctx, owner, receiverId)
+}
+
+// List mocks base method.
+func (m *MockTransferRepository) List(ctx context.Context, filter model.TransfersFilter, limit, offset int) ([]model.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter, limit, offset)
+	ret0, _ := ret[0].([]model.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTransferRepositoryMockRecorder) List(ctx, filter, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTransferRepository)(nil).List), ctx, filter, limit, offset)
+}
+
+// ListByStatus mocks base method.
+func (m *MockTransferRepository) ListByStatus(ctx context.Context, status string, limit, offset int) ([]model.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByStatus", ctx, status, limit, offset)
+	ret0, _ := ret[0].([]model.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByStatus indicates an expected call of ListByStatus.
+func (mr *MockTransferRepositoryMockRecorder) ListByStatus(ctx, status, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByStatus", reflect.TypeOf((*MockTransferRepository)(nil).ListByStatus), ctx, status, limit, offset)
+}
+
+// RecentActivity mocks base method.
+func (m *MockTransferRepository) RecentActivity(ctx context.Context, senderId string, since time.Time) (int64, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecentActivity", ctx, senderId, since)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RecentActivity indicates an expected call of RecentActivity.
+func (mr *MockTransferRepositoryMockRecorder) RecentActivity(ctx, senderId, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecentActivity", reflect.TypeOf((*MockTransferRepository)(nil).RecentActivity), ctx, senderId, since)
+}
+
+// Reject mocks base method.
+func (m *MockTransferRepository) Reject(ctx context.Context, id, reviewer string) (model.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reject", ctx, id, reviewer)
+	ret0, _ := ret[0].(model.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reject indicates an expected call of Reject.
+func (mr *MockTransferRepositoryMockRecorder) Reject(ctx, id, reviewer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reject", reflect.TypeOf((*MockTransferRepository)(nil).Reject), ctx, id, reviewer)
+}
+
+// TransferBatchTx mocks base method.
+func (m *MockTransferRepository) TransferBatchTx(ctx context.Context, args []repository.TransferTxParams) ([]dto.MakeTransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferBatchTx", ctx, args)
+	ret0, _ := ret[0].([]dto.MakeTransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferBatchTx indicates an expected call of TransferBatchTx.
+func (mr *MockTransferRepositoryMockRecorder) TransferBatchTx(ctx, args interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferBatchTx", reflect.TypeOf((*MockTransferRepository)(nil).TransferBatchTx), ctx, args)
+}
+
+// TransferTx mocks base method.
+func (m *MockTransferRepository) TransferTx(ctx context.Context, arg repository.TransferTxParams) (dto.MakeTransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferTx", ctx, arg)
+	ret0, _ := ret[0].(dto.MakeTransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferTx indicates an expected call of TransferTx.
+func (mr *MockTransferRepositoryMockRecorder) TransferTx(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferTx", reflect.TypeOf((*MockTransferRepository)(nil).TransferTx), ctx, arg)
+}
+
+// MockrowScanner is a mock of rowScanner interface.
+type MockrowScanner struct {
+	ctrl     *gomock.Controller
+	recorder *MockrowScannerMockRecorder
+}
+
+// MockrowScannerMockRecorder is the mock recorder for MockrowScanner.
+type MockrowScannerMockRecorder struct {
+	mock *MockrowScanner
+}
+
+// NewMockrowScanner creates a new mock instance.
+func NewMockrowScanner(ctrl *gomock.Controller) *MockrowScanner {
+	mock := &MockrowScanner{ctrl: ctrl}
+	mock.recorder = &MockrowScannerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockrowScanner) EXPECT() *MockrowScannerMockRecorder {
+	return m.recorder
+}
+
+// Scan mocks base method.
+func (m *MockrowScanner) Scan(dest ...interface{}) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range dest {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Scan", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Scan indicates an expected call of Scan.
+func (mr *MockrowScannerMockRecorder) Scan(dest ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockrowScanner)(nil).Scan), dest...)
+}
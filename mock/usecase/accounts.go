@@ -6,6 +6,7 @@ package mockusecase
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -49,6 +50,20 @@ func (mr *MockAccountsUsecaseMockRecorder) DeleteAccount(ctx, id interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockAccountsUsecase)(nil).DeleteAccount), ctx, id)
 }
 
+// ExportStatement mocks base method.
+func (m *MockAccountsUsecase) ExportStatement(ctx context.Context, req dto.StatementRequest, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportStatement", ctx, req, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportStatement indicates an expected call of ExportStatement.
+func (mr *MockAccountsUsecaseMockRecorder) ExportStatement(ctx, req, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportStatement", reflect.TypeOf((*MockAccountsUsecase)(nil).ExportStatement), ctx, req, w)
+}
+
 // GetAccount mocks base method.
 func (m *MockAccountsUsecase) GetAccount(ctx context.Context, id string) (dto.GetAccountResponse, error) {
 	m.ctrl.T.Helper()
@@ -79,6 +94,21 @@ func (mr *MockAccountsUsecaseMockRecorder) ListAccounts(ctx, req interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockAccountsUsecase)(nil).ListAccounts), ctx, req)
 }
 
+// OpenFundedAccount mocks base method.
+func (m *MockAccountsUsecase) OpenFundedAccount(ctx context.Context, req dto.OpenFundedAccountRequest) (dto.RegisterNewAccountsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenFundedAccount", ctx, req)
+	ret0, _ := ret[0].(dto.RegisterNewAccountsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenFundedAccount indicates an expected call of OpenFundedAccount.
+func (mr *MockAccountsUsecaseMockRecorder) OpenFundedAccount(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenFundedAccount", reflect.TypeOf((*MockAccountsUsecase)(nil).OpenFundedAccount), ctx, req)
+}
+
 // RegisterNewAccounts mocks base method.
 func (m *MockAccountsUsecase) RegisterNewAccounts(ctx context.Context, req dto.RegisterNewAccountsRequest) (dto.RegisterNewAccountsResponse, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +124,21 @@ func (mr *MockAccountsUsecaseMockRecorder) RegisterNewAccounts(ctx, req interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterNewAccounts", reflect.TypeOf((*MockAccountsUsecase)(nil).RegisterNewAccounts), ctx, req)
 }
 
+// SetFrozen mocks base method.
+func (m *MockAccountsUsecase) SetFrozen(ctx context.Context, id string, frozen bool) (dto.GetAccountResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFrozen", ctx, id, frozen)
+	ret0, _ := ret[0].(dto.GetAccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetFrozen indicates an expected call of SetFrozen.
+func (mr *MockAccountsUsecaseMockRecorder) SetFrozen(ctx, id, frozen interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFrozen", reflect.TypeOf((*MockAccountsUsecase)(nil).SetFrozen), ctx, id, frozen)
+}
+
 // UpdateAccount mocks base method.
 func (m *MockAccountsUsecase) UpdateAccount(ctx context.Context, req dto.UpdateAccountRequest) (dto.UpdateAccountResponse, error) {
 	m.ctrl.T.Helper()
@@ -108,3 +153,18 @@ func (mr *MockAccountsUsecaseMockRecorder) UpdateAccount(ctx, req interface{}) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccount", reflect.TypeOf((*MockAccountsUsecase)(nil).UpdateAccount), ctx, req)
 }
+
+// UpdateNickname mocks base method.
+func (m *MockAccountsUsecase) UpdateNickname(ctx context.Context, req dto.UpdateAccountNicknameRequest) (dto.UpdateAccountResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNickname", ctx, req)
+	ret0, _ := ret[0].(dto.UpdateAccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNickname indicates an expected call of UpdateNickname.
+func (mr *MockAccountsUsecaseMockRecorder) UpdateNickname(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNickname", reflect.TypeOf((*MockAccountsUsecase)(nil).UpdateNickname), ctx, req)
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecase/health.go
+
+// Package mockusecase is a generated GoMock package.
+package mockusecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "github.com/terajari/bank-api/dto"
+)
+
+// MockPinger is a mock of Pinger interface.
+type MockPinger struct {
+	ctrl     *gomock.Controller
+	recorder *MockPingerMockRecorder
+}
+
+// MockPingerMockRecorder is the mock recorder for MockPinger.
+type MockPingerMockRecorder struct {
+	mock *MockPinger
+}
+
+// NewMockPinger creates a new mock instance.
+func NewMockPinger(ctrl *gomock.Controller) *MockPinger {
+	mock := &MockPinger{ctrl: ctrl}
+	mock.recorder = &MockPingerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPinger) EXPECT() *MockPingerMockRecorder {
+	return m.recorder
+}
+
+// PingContext mocks base method.
+func (m *MockPinger) PingContext(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PingContext", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PingContext indicates an expected call of PingContext.
+func (mr *MockPingerMockRecorder) PingContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PingContext", reflect.TypeOf((*MockPinger)(nil).PingContext), ctx)
+}
+
+// MockHealthUsecase is a mock of HealthUsecase interface.
+type MockHealthUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthUsecaseMockRecorder
+}
+
+// MockHealthUsecaseMockRecorder is the mock recorder for MockHealthUsecase.
+type MockHealthUsecaseMockRecorder struct {
+	mock *MockHealthUsecase
+}
+
+// NewMockHealthUsecase creates a new mock instance.
+func NewMockHealthUsecase(ctrl *gomock.Controller) *MockHealthUsecase {
+	mock := &MockHealthUsecase{ctrl: ctrl}
+	mock.recorder = &MockHealthUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealthUsecase) EXPECT() *MockHealthUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Readiness mocks base method.
+func (m *MockHealthUsecase) Readiness(ctx context.Context) dto.ReadinessResponse {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Readiness", ctx)
+	ret0, _ := ret[0].(dto.ReadinessResponse)
+	return ret0
+}
+
+// Readiness indicates an expected call of Readiness.
+func (mr *MockHealthUsecaseMockRecorder) Readiness(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Readiness", reflect.TypeOf((*MockHealthUsecase)(nil).Readiness), ctx)
+}
@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecase/mfa.go
+
+// Package mockusecase is a generated GoMock package.
+package mockusecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "github.com/terajari/bank-api/dto"
+	token "github.com/terajari/bank-api/token"
+)
+
+// MockMfaUsecase is a mock of MfaUsecase interface.
+type MockMfaUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockMfaUsecaseMockRecorder
+}
+
+// MockMfaUsecaseMockRecorder is the mock recorder for MockMfaUsecase.
+type MockMfaUsecaseMockRecorder struct {
+	mock *MockMfaUsecase
+}
+
+// NewMockMfaUsecase creates a new mock instance.
+func NewMockMfaUsecase(ctrl *gomock.Controller) *MockMfaUsecase {
+	mock := &MockMfaUsecase{ctrl: ctrl}
+	mock.recorder = &MockMfaUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMfaUsecase) EXPECT() *MockMfaUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Confirm mocks base method.
+func (m *MockMfaUsecase) Confirm(ctx context.Context, req dto.ConfirmMfaRequest) (dto.ConfirmMfaResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Confirm", ctx, req)
+	ret0, _ := ret[0].(dto.ConfirmMfaResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Confirm indicates an expected call of Confirm.
+func (mr *MockMfaUsecaseMockRecorder) Confirm(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockMfaUsecase)(nil).Confirm), ctx, req)
+}
+
+// Enroll mocks base method.
+func (m *MockMfaUsecase) Enroll(ctx context.Context, username string) (dto.EnrollMfaResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enroll", ctx, username)
+	ret0, _ := ret[0].(dto.EnrollMfaResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Enroll indicates an expected call of Enroll.
+func (mr *MockMfaUsecaseMockRecorder) Enroll(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enroll", reflect.TypeOf((*MockMfaUsecase)(nil).Enroll), ctx, username)
+}
+
+// IsEnabled mocks base method.
+func (m *MockMfaUsecase) IsEnabled(ctx context.Context, username string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsEnabled", ctx, username)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsEnabled indicates an expected call of IsEnabled.
+func (mr *MockMfaUsecaseMockRecorder) IsEnabled(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEnabled", reflect.TypeOf((*MockMfaUsecase)(nil).IsEnabled), ctx, username)
+}
+
+// VerifyChallenge mocks base method.
+func (m *MockMfaUsecase) VerifyChallenge(ctx context.Context, challenge *token.Payload, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyChallenge", ctx, challenge, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyChallenge indicates an expected call of VerifyChallenge.
+func (mr *MockMfaUsecaseMockRecorder) VerifyChallenge(ctx, challenge, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyChallenge", reflect.TypeOf((*MockMfaUsecase)(nil).VerifyChallenge), ctx, challenge, code)
+}
+
+// VerifyStepUp mocks base method.
+func (m *MockMfaUsecase) VerifyStepUp(ctx context.Context, username, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyStepUp", ctx, username, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyStepUp indicates an expected call of VerifyStepUp.
+func (mr *MockMfaUsecaseMockRecorder) VerifyStepUp(ctx, username, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyStepUp", reflect.TypeOf((*MockMfaUsecase)(nil).VerifyStepUp), ctx, username, code)
+}
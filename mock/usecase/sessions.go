@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecase/sessions.go
+
+// Package mockusecase is a generated GoMock package.
+package mockusecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	dto "github.com/terajari/bank-api/dto"
+)
+
+// MockSessionsUsecase is a mock of SessionsUsecase interface.
+type MockSessionsUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionsUsecaseMockRecorder
+}
+
+// MockSessionsUsecaseMockRecorder is the mock recorder for MockSessionsUsecase.
+type MockSessionsUsecaseMockRecorder struct {
+	mock *MockSessionsUsecase
+}
+
+// NewMockSessionsUsecase creates a new mock instance.
+func NewMockSessionsUsecase(ctrl *gomock.Controller) *MockSessionsUsecase {
+	mock := &MockSessionsUsecase{ctrl: ctrl}
+	mock.recorder = &MockSessionsUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionsUsecase) EXPECT() *MockSessionsUsecaseMockRecorder {
+	return m.recorder
+}
+
+// AddSessions mocks base method.
+func (m *MockSessionsUsecase) AddSessions(ctx context.Context, req dto.AddSessionsRequest) (dto.SessionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddSessions", ctx, req)
+	ret0, _ := ret[0].(dto.SessionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddSessions indicates an expected call of AddSessions.
+func (mr *MockSessionsUsecaseMockRecorder) AddSessions(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSessions", reflect.TypeOf((*MockSessionsUsecase)(nil).AddSessions), ctx, req)
+}
+
+// GetSessions mocks base method.
+func (m *MockSessionsUsecase) GetSessions(ctx context.Context, id uuid.UUID) (dto.SessionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessions", ctx, id)
+	ret0, _ := ret[0].(dto.SessionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessions indicates an expected call of GetSessions.
+func (mr *MockSessionsUsecaseMockRecorder) GetSessions(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessions", reflect.TypeOf((*MockSessionsUsecase)(nil).GetSessions), ctx, id)
+}
+
+// LastSession mocks base method.
+func (m *MockSessionsUsecase) LastSession(ctx context.Context) (dto.SessionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastSession", ctx)
+	ret0, _ := ret[0].(dto.SessionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastSession indicates an expected call of LastSession.
+func (mr *MockSessionsUsecaseMockRecorder) LastSession(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastSession", reflect.TypeOf((*MockSessionsUsecase)(nil).LastSession), ctx)
+}
+
+// RenewAccessToken mocks base method.
+func (m *MockSessionsUsecase) RenewAccessToken(ctx context.Context, req dto.RenewAccessTokenRequest) (dto.RenewAccessTokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewAccessToken", ctx, req)
+	ret0, _ := ret[0].(dto.RenewAccessTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenewAccessToken indicates an expected call of RenewAccessToken.
+func (mr *MockSessionsUsecaseMockRecorder) RenewAccessToken(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewAccessToken", reflect.TypeOf((*MockSessionsUsecase)(nil).RenewAccessToken), ctx, req)
+}
+
+// UpdateBlockStatus mocks base method.
+func (m *MockSessionsUsecase) UpdateBlockStatus(ctx context.Context, req dto.UpdateSessionBlockRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBlockStatus", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBlockStatus indicates an expected call of UpdateBlockStatus.
+func (mr *MockSessionsUsecaseMockRecorder) UpdateBlockStatus(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBlockStatus", reflect.TypeOf((*MockSessionsUsecase)(nil).UpdateBlockStatus), ctx, req)
+}
@@ -0,0 +1,270 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecase/transfer.go
+
+// Package mockusecase is a generated GoMock package.
+package mockusecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "github.com/terajari/bank-api/dto"
+	model "github.com/terajari/bank-api/model"
+)
+
+// MockTransferUsecase is a mock of TransferUsecase interface.
+type MockTransferUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransferUsecaseMockRecorder
+}
+
+// MockTransferUsecaseMockRecorder is the mock recorder for MockTransferUsecase.
+type MockTransferUsecaseMockRecorder struct {
+	mock *MockTransferUsecase
+}
+
+// NewMockTransferUsecase creates a new mock instance.
+func NewMockTransferUsecase(ctrl *gomock.Controller) *MockTransferUsecase {
+	mock := &MockTransferUsecase{ctrl: ctrl}
+	mock.recorder = &MockTransferUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransferUsecase) EXPECT() *MockTransferUsecaseMockRecorder {
+	return m.recorder
+}
+
+// ApproveTransfer mocks base method.
+func (m *MockTransferUsecase) ApproveTransfer(ctx context.Context, id, reviewer string) (dto.MakeTransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveTransfer", ctx, id, reviewer)
+	ret0, _ := ret[0].(dto.MakeTransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveTransfer indicates an expected call of ApproveTransfer.
+func (mr *MockTransferUsecaseMockRecorder) ApproveTransfer(ctx, id, reviewer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveTransfer", reflect.TypeOf((*MockTransferUsecase)(nil).ApproveTransfer), ctx, id, reviewer)
+}
+
+// AuthorizeHold mocks base method.
+func (m *MockTransferUsecase) AuthorizeHold(ctx context.Context, req dto.AuthorizeHoldRequest) (model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizeHold", ctx, req)
+	ret0, _ := ret[0].(model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthorizeHold indicates an expected call of AuthorizeHold.
+func (mr *MockTransferUsecaseMockRecorder) AuthorizeHold(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizeHold", reflect.TypeOf((*MockTransferUsecase)(nil).AuthorizeHold), ctx, req)
+}
+
+// CaptureHold mocks base method.
+func (m *MockTransferUsecase) CaptureHold(ctx context.Context, req dto.CaptureHoldRequest) (dto.CaptureHoldResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureHold", ctx, req)
+	ret0, _ := ret[0].(dto.CaptureHoldResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockTransferUsecaseMockRecorder) CaptureHold(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockTransferUsecase)(nil).CaptureHold), ctx, req)
+}
+
+// ExpireHolds mocks base method.
+func (m *MockTransferUsecase) ExpireHolds(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireHolds", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireHolds indicates an expected call of ExpireHolds.
+func (mr *MockTransferUsecaseMockRecorder) ExpireHolds(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireHolds", reflect.TypeOf((*MockTransferUsecase)(nil).ExpireHolds), ctx)
+}
+
+// GetHold mocks base method.
+func (m *MockTransferUsecase) GetHold(ctx context.Context, id string) (model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHold", ctx, id)
+	ret0, _ := ret[0].(model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHold indicates an expected call of GetHold.
+func (mr *MockTransferUsecaseMockRecorder) GetHold(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHold", reflect.TypeOf((*MockTransferUsecase)(nil).GetHold), ctx, id)
+}
+
+// ListPendingTransfers mocks base method.
+func (m *MockTransferUsecase) ListPendingTransfers(ctx context.Context, req dto.ListTransfersRequest) ([]model.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingTransfers", ctx, req)
+	ret0, _ := ret[0].([]model.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingTransfers indicates an expected call of ListPendingTransfers.
+func (mr *MockTransferUsecaseMockRecorder) ListPendingTransfers(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingTransfers", reflect.TypeOf((*MockTransferUsecase)(nil).ListPendingTransfers), ctx, req)
+}
+
+// ListTransfers mocks base method.
+func (m *MockTransferUsecase) ListTransfers(ctx context.Context, req dto.TransferHistoryRequest) ([]dto.TransferHistoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransfers", ctx, req)
+	ret0, _ := ret[0].([]dto.TransferHistoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransfers indicates an expected call of ListTransfers.
+func (mr *MockTransferUsecaseMockRecorder) ListTransfers(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockTransferUsecase)(nil).ListTransfers), ctx, req)
+}
+
+// MakeTransfer mocks base method.
+func (m *MockTransferUsecase) MakeTransfer(ctx context.Context, request dto.MakeTransferRequest) (dto.MakeTransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MakeTransfer", ctx, request)
+	ret0, _ := ret[0].(dto.MakeTransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MakeTransfer indicates an expected call of MakeTransfer.
+func (mr *MockTransferUsecaseMockRecorder) MakeTransfer(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeTransfer", reflect.TypeOf((*MockTransferUsecase)(nil).MakeTransfer), ctx, request)
+}
+
+// MakeTransferBatch mocks base method.
+func (m *MockTransferUsecase) MakeTransferBatch(ctx context.Context, req dto.BatchTransferRequest) (dto.BatchTransferResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MakeTransferBatch", ctx, req)
+	ret0, _ := ret[0].(dto.BatchTransferResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MakeTransferBatch indicates an expected call of MakeTransferBatch.
+func (mr *MockTransferUsecaseMockRecorder) MakeTransferBatch(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeTransferBatch", reflect.TypeOf((*MockTransferUsecase)(nil).MakeTransferBatch), ctx, req)
+}
+
+// QuoteTransfer mocks base method.
+func (m *MockTransferUsecase) QuoteTransfer(ctx context.Context, request dto.TransferQuoteRequest) (dto.TransferQuoteResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuoteTransfer", ctx, request)
+	ret0, _ := ret[0].(dto.TransferQuoteResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuoteTransfer indicates an expected call of QuoteTransfer.
+func (mr *MockTransferUsecaseMockRecorder) QuoteTransfer(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuoteTransfer", reflect.TypeOf((*MockTransferUsecase)(nil).QuoteTransfer), ctx, request)
+}
+
+// RejectTransfer mocks base method.
+func (m *MockTransferUsecase) RejectTransfer(ctx context.Context, id, reviewer string) (model.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectTransfer", ctx, id, reviewer)
+	ret0, _ := ret[0].(model.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RejectTransfer indicates an expected call of RejectTransfer.
+func (mr *MockTransferUsecaseMockRecorder) RejectTransfer(ctx, id, reviewer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectTransfer", reflect.TypeOf((*MockTransferUsecase)(nil).RejectTransfer), ctx, id, reviewer)
+}
+
+// ResumeTransferBatch mocks base method.
+func (m *MockTransferUsecase) ResumeTransferBatch(ctx context.Context, req dto.BatchTransferRequest, done []dto.BatchItemResult, progress func(dto.BatchTransferResult)) (dto.BatchTransferResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeTransferBatch", ctx, req, done, progress)
+	ret0, _ := ret[0].(dto.BatchTransferResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResumeTransferBatch indicates an expected call of ResumeTransferBatch.
+func (mr *MockTransferUsecaseMockRecorder) ResumeTransferBatch(ctx, req, done, progress interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeTransferBatch", reflect.TypeOf((*MockTransferUsecase)(nil).ResumeTransferBatch), ctx, req, done, progress)
+}
+
+// VoidHold mocks base method.
+func (m *MockTransferUsecase) VoidHold(ctx context.Context, id string) (model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoidHold", ctx, id)
+	ret0, _ := ret[0].(model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoidHold indicates an expected call of VoidHold.
+func (mr *MockTransferUsecaseMockRecorder) VoidHold(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoidHold", reflect.TypeOf((*MockTransferUsecase)(nil).VoidHold), ctx, id)
+}
+
+// MockRiskEvaluator is a mock of RiskEvaluator interface.
+type MockRiskEvaluator struct {
+	ctrl     *gomock.Controller
+	recorder *MockRiskEvaluatorMockRecorder
+}
+
+// MockRiskEvaluatorMockRecorder is the mock recorder for MockRiskEvaluator.
+type MockRiskEvaluatorMockRecorder struct {
+	mock *MockRiskEvaluator
+}
+
+// NewMockRiskEvaluator creates a new mock instance.
+func NewMockRiskEvaluator(ctrl *gomock.Controller) *MockRiskEvaluator {
+	mock := &MockRiskEvaluator{ctrl: ctrl}
+	mock.recorder = &MockRiskEvaluatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRiskEvaluator) EXPECT() *MockRiskEvaluatorMockRecorder {
+	return m.recorder
+}
+
+// Evaluate mocks base method.
+func (m *MockRiskEvaluator) Evaluate(ctx context.Context, input model.RiskInput) (model.RiskResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Evaluate", ctx, input)
+	ret0, _ := ret[0].(model.RiskResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Evaluate indicates an expected call of Evaluate.
+func (mr *MockRiskEvaluatorMockRecorder) Evaluate(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Evaluate", reflect.TypeOf((*MockRiskEvaluator)(nil).Evaluate), ctx, input)
+}
@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecase/users.go
+
+// Package mockusecase is a generated GoMock package.
+package mockusecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "github.com/terajari/bank-api/dto"
+	model "github.com/terajari/bank-api/model"
+)
+
+// MockUsersUsecase is a mock of UsersUsecase interface.
+type MockUsersUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsersUsecaseMockRecorder
+}
+
+// MockUsersUsecaseMockRecorder is the mock recorder for MockUsersUsecase.
+type MockUsersUsecaseMockRecorder struct {
+	mock *MockUsersUsecase
+}
+
+// NewMockUsersUsecase creates a new mock instance.
+func NewMockUsersUsecase(ctrl *gomock.Controller) *MockUsersUsecase {
+	mock := &MockUsersUsecase{ctrl: ctrl}
+	mock.recorder = &MockUsersUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsersUsecase) EXPECT() *MockUsersUsecaseMockRecorder {
+	return m.recorder
+}
+
+// CreateUser mocks base method.
+func (m *MockUsersUsecase) CreateUser(ctx context.Context, req dto.CreateUserRequest) (dto.UserReponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, req)
+	ret0, _ := ret[0].(dto.UserReponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockUsersUsecaseMockRecorder) CreateUser(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUsersUsecase)(nil).CreateUser), ctx, req)
+}
+
+// GetUser mocks base method.
+func (m *MockUsersUsecase) GetUser(ctx context.Context, username string) (model.Users, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", ctx, username)
+	ret0, _ := ret[0].(model.Users)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockUsersUsecaseMockRecorder) GetUser(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockUsersUsecase)(nil).GetUser), ctx, username)
+}
+
+// Login mocks base method.
+func (m *MockUsersUsecase) Login(ctx context.Context, req dto.LoginUserRequest) (model.Users, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", ctx, req)
+	ret0, _ := ret[0].(model.Users)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Login indicates an expected call of Login.
+func (mr *MockUsersUsecaseMockRecorder) Login(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUsersUsecase)(nil).Login), ctx, req)
+}
@@ -2,10 +2,49 @@ package model
 
 import "time"
 
+const (
+	AccountTypeChecking = "checking"
+	AccountTypeSavings  = "savings"
+	AccountTypeSystem   = "system"
+)
+
 type Accounts struct {
-	ID        string    `json:"id"`
-	Owner     string    `json:"owner"`
-	Balance   int64     `json:"balance"`
-	Currency  string    `json:"currency"`
-	CreatedAt time.Time `json:"created_at"`
+	ID                string     `json:"id"`
+	Number            string     `json:"number"`
+	Owner             string     `json:"owner"`
+	Balance           int64      `json:"balance"`
+	Currency          string     `json:"currency"`
+	Nickname          string     `json:"nickname"`
+	Type              string     `json:"type"`
+	HeldBalance       int64      `json:"held_balance"`
+	UniquePerCurrency bool       `json:"-"`
+	FrozenAt          *time.Time `json:"frozen_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// AvailableBalance is the balance that is not reserved by holds.
+func (a Accounts) AvailableBalance() int64 {
+	return a.Balance - a.HeldBalance
+}
+
+// Frozen reports whether an operator has frozen the account. A frozen
+// account neither sends nor receives transfers.
+func (a Accounts) Frozen() bool {
+	return a.FrozenAt != nil
+}
+
+type AccountsFilter struct {
+	Owner    string
+	Currency string
+	Type     string
+}
+
+// AccountType describes a kind of account. Customers can only open types
+// that are UserSelectable, the bank's own ledger accounts are not.
+type AccountType struct {
+	Name                  string    `json:"name"`
+	UniquePerCurrency     bool      `json:"unique_per_currency"`
+	AnnualInterestRateBps int64     `json:"annual_interest_rate_bps"`
+	UserSelectable        bool      `json:"user_selectable"`
+	CreatedAt             time.Time `json:"created_at"`
 }
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	BatchModeAllOrNothing = "all_or_nothing"
+	BatchModeBestEffort   = "best_effort"
+)
+
+// Outcomes of a batch item besides the transfer statuses.
+const (
+	BatchItemFailed  = "failed"
+	BatchItemSkipped = "skipped"
+)
+
+const (
+	BatchJobQueued    = "queued"
+	BatchJobRunning   = "running"
+	BatchJobCompleted = "completed"
+	BatchJobFailed    = "failed"
+)
+
+// BatchJob is a batch transfer processed in the background. Request and
+// Result hold the JSON encoded request and per item results; while the job
+// runs, Result is its progress. A running job whose HeartbeatAt gets stale
+// was abandoned by its worker and is queued again.
+type BatchJob struct {
+	ID          string          `json:"id"`
+	Owner       string          `json:"owner"`
+	SenderId    string          `json:"sender_id"`
+	Mode        string          `json:"mode"`
+	ItemCount   int             `json:"item_count"`
+	Status      string          `json:"status"`
+	Request     json.RawMessage `json:"-"`
+	Result      json.RawMessage `json:"result,omitempty"`
+	Error       string          `json:"error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	StartedAt   *time.Time      `json:"started_at,omitempty"`
+	HeartbeatAt *time.Time      `json:"heartbeat_at,omitempty"`
+	Attempts    int             `json:"attempts"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+}
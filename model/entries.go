@@ -1,8 +1,9 @@
 package model
 
 type Entries struct {
-	ID        string `json:"id"`
-	AccountId string `json:"account_id"`
-	Amount    int64  `json:"amount"`
-	CreatedAt string `json:"created_at"`
+	ID         string `json:"id"`
+	AccountId  string `json:"account_id"`
+	Amount     int64  `json:"amount"`
+	TransferId string `json:"transfer_id,omitempty"`
+	CreatedAt  string `json:"created_at"`
 }
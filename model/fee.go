@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+const (
+	FeeKindFlat       = "flat"
+	FeeKindPercentage = "percentage"
+)
+
+// FeeRule applies to transfers in Currency sent from an account of AccountType,
+// a nil Currency or AccountType matches any.
+type FeeRule struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Kind        string    `json:"kind"`
+	FlatAmount  int64     `json:"flat_amount"`
+	RateBps     int64     `json:"rate_bps"`
+	MinAmount   int64     `json:"min_amount"`
+	MaxAmount   *int64    `json:"max_amount"`
+	Currency    *string   `json:"currency"`
+	AccountType *string   `json:"account_type"`
+	Active      bool      `json:"active"`
+	Priority    int       `json:"priority"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type TransferFee struct {
+	ID               string    `json:"id"`
+	TransferID       string    `json:"transfer_id"`
+	RuleID           string    `json:"rule_id"`
+	Name             string    `json:"name"`
+	Amount           int64     `json:"amount"`
+	RevenueAccountID string    `json:"revenue_account_id"`
+	SenderEntryID    string    `json:"sender_entry_id"`
+	RevenueEntryID   string    `json:"revenue_entry_id"`
+	CreatedAt        time.Time `json:"created_at"`
+}
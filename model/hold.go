@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+const (
+	HoldStatusAuthorized = "authorized"
+	HoldStatusCaptured   = "captured"
+	HoldStatusVoided     = "voided"
+	HoldStatusExpired    = "expired"
+)
+
+// Hold reserves funds on AccountId for a later transfer to ReceiverId.
+// Reserved is the amount plus the fees quoted at authorization and is what
+// counts against the account's held balance.
+type Hold struct {
+	ID             string    `json:"id"`
+	AccountId      string    `json:"account_id"`
+	ReceiverId     string    `json:"receiver_id"`
+	Currency       string    `json:"currency"`
+	Amount         int64     `json:"amount"`
+	Reserved       int64     `json:"reserved"`
+	CapturedAmount int64     `json:"captured_amount"`
+	Status         string    `json:"status"`
+	TransferId     *string   `json:"transfer_id"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+type InterestCandidate struct {
+	AccountID string `json:"account_id"`
+	Currency  string `json:"currency"`
+	Balance   int64  `json:"balance"`
+	RateBps   int64  `json:"rate_bps"`
+}
+
+type InterestAccrual struct {
+	ID          string     `json:"id"`
+	AccountID   string     `json:"account_id"`
+	AccrualDate time.Time  `json:"accrual_date"`
+	Balance     int64      `json:"balance"`
+	RateBps     int64      `json:"rate_bps"`
+	Amount      int64      `json:"amount"`
+	TransferID  *string    `json:"transfer_id"`
+	PostedAt    *time.Time `json:"posted_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type InterestPosting struct {
+	AccountID string `json:"account_id"`
+	Currency  string `json:"currency"`
+	Amount    int64  `json:"amount"`
+	Accruals  int    `json:"accruals"`
+}
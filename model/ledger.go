@@ -0,0 +1,50 @@
+package model
+
+// BalanceDrift is an account whose balance differs from the sum of its
+// entries.
+type BalanceDrift struct {
+	AccountID    string `json:"account_id"`
+	Currency     string `json:"currency"`
+	Balance      int64  `json:"balance"`
+	EntriesTotal int64  `json:"entries_total"`
+}
+
+// Difference is how far the balance is off its entries.
+func (d BalanceDrift) Difference() int64 {
+	return d.Balance - d.EntriesTotal
+}
+
+// TransferIssue is a transfer whose entries do not match it. A completed
+// transfer must have exactly two entries besides those of its fees: one
+// debiting the sender and one crediting the receiver with its amount. A
+// transfer that is not completed must have none.
+type TransferIssue struct {
+	TransferID      string `json:"transfer_id"`
+	Status          string `json:"status"`
+	Amount          int64  `json:"amount"`
+	Entries         int    `json:"entries"`
+	SenderEntries   int    `json:"sender_entries"`
+	ReceiverEntries int    `json:"receiver_entries"`
+}
+
+// CurrencyTotal sums the ledger of one currency. Money only moves between
+// accounts, the bank's settlement accounts included, so the entries and the
+// balances of a currency must sum to zero.
+type CurrencyTotal struct {
+	Currency string `json:"currency"`
+	Balances int64  `json:"balances"`
+	Entries  int64  `json:"entries"`
+}
+
+// Imbalance is the sum of all entries, zero in a balanced ledger.
+func (c CurrencyTotal) Imbalance() int64 {
+	return c.Entries
+}
+
+// LedgerCheck is what the ledger checks found, read from one snapshot of the
+// database.
+type LedgerCheck struct {
+	BalanceDrifts  []BalanceDrift
+	TransferIssues []TransferIssue
+	CurrencyTotals []CurrencyTotal
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	LimitScopeUser    = "user"
+	LimitScopeAccount = "account"
+
+	// LimitSubjectAny applies a limit to every user or account of its scope.
+	LimitSubjectAny = "*"
+)
+
+const (
+	LimitMaxSingle      = "max_single"
+	LimitMaxDaily       = "max_daily"
+	LimitMaxMonthly     = "max_monthly"
+	LimitMaxHourlyCount = "max_hourly_count"
+)
+
+// TransferLimit caps outgoing transfers of a user (all accounts in Currency)
+// or of a single account. Nil fields are not enforced. Daily, monthly and
+// hourly windows are calendar periods in UTC.
+type TransferLimit struct {
+	ID             string    `json:"id"`
+	Scope          string    `json:"scope"`
+	Subject        string    `json:"subject"`
+	Currency       string    `json:"currency"`
+	MaxSingle      *int64    `json:"max_single"`
+	MaxDaily       *int64    `json:"max_daily"`
+	MaxMonthly     *int64    `json:"max_monthly"`
+	MaxHourlyCount *int64    `json:"max_hourly_count"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type LimitExceededError struct {
+	Scope     string     `json:"scope"`
+	Subject   string     `json:"subject"`
+	Limit     string     `json:"limit"`
+	Max       int64      `json:"max"`
+	Used      int64      `json:"used"`
+	Requested int64      `json:"requested"`
+	ResetsAt  *time.Time `json:"resets_at,omitempty"`
+}
+
+func (e *LimitExceededError) Error() string {
+	msg := fmt.Sprintf("%s limit %s exceeded for %s: %d used + %d requested > %d", e.Scope, e.Limit, e.Subject, e.Used, e.Requested, e.Max)
+	if e.ResetsAt != nil {
+		msg += fmt.Sprintf(", resets at %s", e.ResetsAt.Format(time.RFC3339))
+	}
+	return msg
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+type UserMfa struct {
+	Username       string     `json:"username"`
+	Secret         string     `json:"-"`
+	Enabled        bool       `json:"enabled"`
+	LastUsedStep   int64      `json:"-"`
+	FailedAttempts int        `json:"-"`
+	LockedUntil    *time.Time `json:"-"`
+	ConfirmedAt    *time.Time `json:"confirmed_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type RecoveryCode struct {
+	ID       string     `json:"id"`
+	Username string     `json:"username"`
+	CodeHash string     `json:"-"`
+	UsedAt   *time.Time `json:"used_at"`
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Payee is an account saved to a user's address book.
+type Payee struct {
+	ID        string    `json:"id"`
+	Owner     string    `json:"owner"`
+	Nickname  string    `json:"nickname"`
+	AccountId string    `json:"account_id"`
+	Currency  string    `json:"currency"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PayeeCoolingOff caps what a user can send to an account during Period
+// after adding it as a payee. A zero Period disables the check.
+type PayeeCoolingOff struct {
+	Period    time.Duration
+	MaxAmount int64
+}
+
+// PayeeCoolingOffError rejects a transfer to a payee that was added recently
+// when it would take the amount sent to it during the cooling-off period
+// above what is allowed.
+type PayeeCoolingOffError struct {
+	PayeeId     string    `json:"payee_id"`
+	Max         int64     `json:"max"`
+	Used        int64     `json:"used"`
+	Requested   int64     `json:"requested"`
+	AvailableAt time.Time `json:"available_at"`
+}
+
+func (e *PayeeCoolingOffError) Error() string {
+	return fmt.Sprintf("payee %s is in its cooling-off period: %d requested + %d sent > %d allowed until %s", e.PayeeId, e.Requested, e.Used, e.Max, e.AvailableAt.Format(time.RFC3339))
+}
@@ -0,0 +1,28 @@
+package model
+
+const (
+	RiskAllow  = "allow"
+	RiskDeny   = "deny"
+	RiskReview = "review"
+)
+
+// RiskInput describes a transfer before it is committed. Hour is the UTC hour
+// of day; RecentCount and RecentAmount cover the sender's transfers within
+// the configured velocity window.
+type RiskInput struct {
+	Username     string
+	SenderId     string
+	ReceiverId   string
+	Currency     string
+	Amount       int64
+	NewPayee     bool
+	Hour         int
+	RecentCount  int64
+	RecentAmount int64
+}
+
+type RiskResult struct {
+	Decision string   `json:"decision"`
+	Score    int      `json:"score"`
+	Reasons  []string `json:"reasons"`
+}
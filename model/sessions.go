@@ -15,4 +15,7 @@ type Sessions struct {
 	IsBlocked    bool      `json:"is_blocked"`
 	ExpiresAt    time.Time `json:"expires_at"`
 	CreatedAt    time.Time `json:"created_at"`
+	// RenewalCount and LastRenewedAt record access token renewals.
+	RenewalCount  int        `json:"renewal_count"`
+	LastRenewedAt *time.Time `json:"last_renewed_at,omitempty"`
 }
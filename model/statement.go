@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+const (
+	StatementEntryTransfer = "transfer"
+	StatementEntryFee      = "fee"
+)
+
+// StatementHeader describes a statement for [From, To).
+type StatementHeader struct {
+	AccountId      string
+	Owner          string
+	Currency       string
+	From           time.Time
+	To             time.Time
+	OpeningBalance int64
+	ClosingBalance int64
+	GeneratedAt    time.Time
+}
+
+// StatementEntry is one ledger entry of a statement. Balance is the running
+// balance after the entry.
+type StatementEntry struct {
+	EntryId      string
+	TransferId   string
+	Kind         string
+	Counterparty string
+	Description  string
+	Reference    string
+	Amount       int64
+	Balance      int64
+	CreatedAt    time.Time
+}
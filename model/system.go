@@ -0,0 +1,20 @@
+package model
+
+import "strings"
+
+// SystemOwner owns the bank's internal ledger accounts. The user cannot log in.
+const SystemOwner = "bank"
+
+func InterestExpenseAccountID(currency string) string {
+	return "sys-interest-expense-" + strings.ToLower(currency)
+}
+
+func FeeRevenueAccountID(currency string) string {
+	return "sys-fee-revenue-" + strings.ToLower(currency)
+}
+
+// SettlementAccountID is the account money enters and leaves the bank
+// through, for example the cash of a deposit at a branch.
+func SettlementAccountID(currency string) string {
+	return "sys-settlement-" + strings.ToLower(currency)
+}
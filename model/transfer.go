@@ -1,9 +1,32 @@
 package model
 
+import "time"
+
+const (
+	TransferStatusCompleted     = "completed"
+	TransferStatusPendingReview = "pending_review"
+	TransferStatusRejected      = "rejected"
+)
+
 type Transfer struct {
-	ID         string `json:"id"`
-	SenderId   string `json:"sender_id"`
-	ReceiverId string `json:"receiver_id"`
-	Amount     int64  `json:"amount"`
-	CreatedAt  string `json:"created_at"`
+	ID          string            `json:"id"`
+	SenderId    string            `json:"sender_id"`
+	ReceiverId  string            `json:"receiver_id"`
+	Amount      int64             `json:"amount"`
+	Status      string            `json:"status"`
+	Description string            `json:"description,omitempty"`
+	Reference   string            `json:"reference,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	RiskScore   int               `json:"risk_score,omitempty"`
+	RiskReasons []string          `json:"risk_reasons,omitempty"`
+	ReviewedBy  *string           `json:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time        `json:"reviewed_at,omitempty"`
+	CreatedAt   string            `json:"created_at"`
+}
+
+// TransfersFilter selects the transfers sent or received by AccountId. An
+// empty Reference matches every transfer.
+type TransfersFilter struct {
+	AccountId string
+	Reference string
 }
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/terajari/bank-api/model"
+)
+
+type AccountTypesRepository interface {
+	Get(ctx context.Context, name string) (model.AccountType, error)
+	List(ctx context.Context) ([]model.AccountType, error)
+}
+
+type accountTypesRepository struct {
+	db DBTX
+}
+
+func NewAccountTypesRepository(db DBTX) AccountTypesRepository {
+	return &accountTypesRepository{db: db}
+}
+
+func (r *accountTypesRepository) Get(ctx context.Context, name string) (model.AccountType, error) {
+	query := "SELECT name, unique_per_currency, annual_interest_rate_bps, user_selectable, created_at FROM account_types WHERE name = $1 LIMIT 1"
+
+	row := r.db.QueryRowContext(ctx, query, name)
+	var at model.AccountType
+	if err := row.Scan(&at.Name, &at.UniquePerCurrency, &at.AnnualInterestRateBps, &at.UserSelectable, &at.CreatedAt); err != nil {
+		return model.AccountType{}, err
+	}
+
+	return at, nil
+}
+
+func (r *accountTypesRepository) List(ctx context.Context) ([]model.AccountType, error) {
+	query := "SELECT name, unique_per_currency, annual_interest_rate_bps, user_selectable, created_at FROM account_types ORDER BY name"
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return []model.AccountType{}, err
+	}
+	defer rows.Close()
+	var types []model.AccountType
+	for rows.Next() {
+		var at model.AccountType
+		if err := rows.Scan(&at.Name, &at.UniquePerCurrency, &at.AnnualInterestRateBps, &at.UserSelectable, &at.CreatedAt); err != nil {
+			return []model.AccountType{}, err
+		}
+		types = append(types, at)
+	}
+
+	return types, nil
+}
@@ -3,33 +3,36 @@ package repository
 import (
 	"context"
 
-	"github.com/jmoiron/sqlx"
 	"github.com/terajari/bank-api/model"
 )
 
 type AccountsRepository interface {
 	Create(ctx context.Context, account model.Accounts) (model.Accounts, error)
 	Get(ctx context.Context, id string) (model.Accounts, error)
-	List(ctx context.Context, owner string, limit, offset int) ([]model.Accounts, error)
+	GetByNumber(ctx context.Context, number string) (model.Accounts, error)
+	List(ctx context.Context, filter model.AccountsFilter, limit, offset int) ([]model.Accounts, error)
 	Update(ctx context.Context, account model.Accounts) (model.Accounts, error)
+	UpdateNickname(ctx context.Context, id, nickname string) (model.Accounts, error)
 	Delete(ctx context.Context, id string) error
 	GetForUpdate(ctx context.Context, id string) (model.Accounts, error)
+	AddBalance(ctx context.Context, id string, amount int64) (model.Accounts, error)
+	SetFrozen(ctx context.Context, id string, frozen bool) (model.Accounts, error)
 }
 
 type accountsRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
-func NewAccountsRepository(db *sqlx.DB) AccountsRepository {
+func NewAccountsRepository(db DBTX) AccountsRepository {
 	return &accountsRepository{db: db}
 }
 
 func (r *accountsRepository) Create(ctx context.Context, account model.Accounts) (model.Accounts, error) {
-	query := "INSERT INTO accounts (id, owner, balance, currency) VALUES ($1, $2, $3, $4) RETURNING id, owner, balance, currency, created_at"
+	query := "INSERT INTO accounts (id, number, owner, balance, currency, nickname, type, unique_per_currency) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, number, owner, balance, currency, nickname, type, held_balance, created_at"
 
-	row := r.db.QueryRowContext(ctx, query, account.ID, account.Owner, account.Balance, account.Currency)
+	row := r.db.QueryRowContext(ctx, query, account.ID, account.Number, account.Owner, account.Balance, account.Currency, account.Nickname, account.Type, account.UniquePerCurrency)
 	var a model.Accounts
-	if err := row.Scan(&a.ID, &a.Owner, &a.Balance, &a.Currency, &a.CreatedAt); err != nil {
+	if err := row.Scan(&a.ID, &a.Number, &a.Owner, &a.Balance, &a.Currency, &a.Nickname, &a.Type, &a.HeldBalance, &a.CreatedAt); err != nil {
 		return model.Accounts{}, err
 	}
 
@@ -37,21 +40,34 @@ func (r *accountsRepository) Create(ctx context.Context, account model.Accounts)
 }
 
 func (r *accountsRepository) Get(ctx context.Context, id string) (model.Accounts, error) {
-	query := "SELECT id, owner, balance, currency FROM accounts WHERE id = $1 LIMIT 1"
+	query := "SELECT id, number, owner, balance, currency, nickname, type, held_balance, frozen_at FROM accounts WHERE id = $1 LIMIT 1"
 
 	row := r.db.QueryRowContext(ctx, query, id)
 	var a model.Accounts
-	if err := row.Scan(&a.ID, &a.Owner, &a.Balance, &a.Currency); err != nil {
+	if err := row.Scan(&a.ID, &a.Number, &a.Owner, &a.Balance, &a.Currency, &a.Nickname, &a.Type, &a.HeldBalance, &a.FrozenAt); err != nil {
 		return model.Accounts{}, err
 	}
 
 	return a, nil
 }
 
-func (r *accountsRepository) List(ctx context.Context, id string, limit, offset int) ([]model.Accounts, error) {
-	query := "SELECT id, owner, balance, currency FROM accounts WHERE owner = $1 ORDER BY id LIMIT $2 OFFSET $3"
+func (r *accountsRepository) GetByNumber(ctx context.Context, number string) (model.Accounts, error) {
+	query := "SELECT id, number, owner, balance, currency, nickname, type, held_balance, frozen_at FROM accounts WHERE number = $1 LIMIT 1"
 
-	rows, err := r.db.QueryContext(ctx, query, id, limit, offset)
+	row := r.db.QueryRowContext(ctx, query, number)
+	var a model.Accounts
+	if err := row.Scan(&a.ID, &a.Number, &a.Owner, &a.Balance, &a.Currency, &a.Nickname, &a.Type, &a.HeldBalance, &a.FrozenAt); err != nil {
+		return model.Accounts{}, err
+	}
+
+	return a, nil
+}
+
+// List returns the owner's accounts, an empty currency or type in the filter matches every account.
+func (r *accountsRepository) List(ctx context.Context, filter model.AccountsFilter, limit, offset int) ([]model.Accounts, error) {
+	query := "SELECT id, number, owner, balance, currency, nickname, type, held_balance FROM accounts WHERE owner = $1 AND ($2 = '' OR currency = $2) AND ($3 = '' OR type = $3) ORDER BY id LIMIT $4 OFFSET $5"
+
+	rows, err := r.db.QueryContext(ctx, query, filter.Owner, filter.Currency, filter.Type, limit, offset)
 	if err != nil {
 		return []model.Accounts{}, err
 	}
@@ -59,7 +75,7 @@ func (r *accountsRepository) List(ctx context.Context, id string, limit, offset
 	var accounts []model.Accounts
 	for rows.Next() {
 		var a model.Accounts
-		if err := rows.Scan(&a.ID, &a.Owner, &a.Balance, &a.Currency); err != nil {
+		if err := rows.Scan(&a.ID, &a.Number, &a.Owner, &a.Balance, &a.Currency, &a.Nickname, &a.Type, &a.HeldBalance); err != nil {
 			return []model.Accounts{}, err
 		}
 		accounts = append(accounts, a)
@@ -69,10 +85,43 @@ func (r *accountsRepository) List(ctx context.Context, id string, limit, offset
 }
 
 func (r *accountsRepository) Update(ctx context.Context, account model.Accounts) (model.Accounts, error) {
-	query := "UPDATE accounts SET balance = $2 WHERE id = $1 RETURNING id, owner, balance, currency, created_at"
+	query := "UPDATE accounts SET balance = $2 WHERE id = $1 RETURNING id, number, owner, balance, currency, nickname, type, held_balance, created_at"
 	row := r.db.QueryRowContext(ctx, query, account.ID, account.Balance)
 	var a model.Accounts
-	if err := row.Scan(&a.ID, &a.Owner, &a.Balance, &a.Currency, &a.CreatedAt); err != nil {
+	if err := row.Scan(&a.ID, &a.Number, &a.Owner, &a.Balance, &a.Currency, &a.Nickname, &a.Type, &a.HeldBalance, &a.CreatedAt); err != nil {
+		return model.Accounts{}, err
+	}
+	return a, nil
+}
+
+// AddBalance adds amount, which may be negative, to the account's balance.
+func (r *accountsRepository) AddBalance(ctx context.Context, id string, amount int64) (model.Accounts, error) {
+	query := "UPDATE accounts SET balance = balance + $2 WHERE id = $1 RETURNING id, number, owner, balance, currency, nickname, type, held_balance, created_at"
+	row := r.db.QueryRowContext(ctx, query, id, amount)
+	var a model.Accounts
+	if err := row.Scan(&a.ID, &a.Number, &a.Owner, &a.Balance, &a.Currency, &a.Nickname, &a.Type, &a.HeldBalance, &a.CreatedAt); err != nil {
+		return model.Accounts{}, err
+	}
+	return a, nil
+}
+
+// SetFrozen freezes or unfreezes the account. Freezing a frozen account keeps
+// the time it was first frozen at.
+func (r *accountsRepository) SetFrozen(ctx context.Context, id string, frozen bool) (model.Accounts, error) {
+	query := "UPDATE accounts SET frozen_at = CASE WHEN $2 THEN COALESCE(frozen_at, now()) END WHERE id = $1 RETURNING id, number, owner, balance, currency, nickname, type, held_balance, frozen_at, created_at"
+	row := r.db.QueryRowContext(ctx, query, id, frozen)
+	var a model.Accounts
+	if err := row.Scan(&a.ID, &a.Number, &a.Owner, &a.Balance, &a.Currency, &a.Nickname, &a.Type, &a.HeldBalance, &a.FrozenAt, &a.CreatedAt); err != nil {
+		return model.Accounts{}, err
+	}
+	return a, nil
+}
+
+func (r *accountsRepository) UpdateNickname(ctx context.Context, id, nickname string) (model.Accounts, error) {
+	query := "UPDATE accounts SET nickname = $2 WHERE id = $1 RETURNING id, number, owner, balance, currency, nickname, type, held_balance, created_at"
+	row := r.db.QueryRowContext(ctx, query, id, nickname)
+	var a model.Accounts
+	if err := row.Scan(&a.ID, &a.Number, &a.Owner, &a.Balance, &a.Currency, &a.Nickname, &a.Type, &a.HeldBalance, &a.CreatedAt); err != nil {
 		return model.Accounts{}, err
 	}
 	return a, nil
@@ -89,11 +138,11 @@ func (r *accountsRepository) Delete(ctx context.Context, id string) error {
 }
 
 func (r *accountsRepository) GetForUpdate(ctx context.Context, id string) (model.Accounts, error) {
-	query := "SELECT id, owner, balance, currency FROM accounts WHERE id = $1 LIMIT 1 FOR NO KEY UPDATE"
+	query := "SELECT id, number, owner, balance, currency, nickname, type, held_balance, frozen_at FROM accounts WHERE id = $1 LIMIT 1 FOR NO KEY UPDATE"
 
 	row := r.db.QueryRowContext(ctx, query, id)
 	var a model.Accounts
-	if err := row.Scan(&a.ID, &a.Owner, &a.Balance, &a.Currency); err != nil {
+	if err := row.Scan(&a.ID, &a.Number, &a.Owner, &a.Balance, &a.Currency, &a.Nickname, &a.Type, &a.HeldBalance, &a.FrozenAt); err != nil {
 		return model.Accounts{}, err
 	}
 
@@ -30,26 +30,26 @@ func TestCreateAccount(t *testing.T) {
 			name: "success create account",
 			args: args{
 				ctx:     context.TODO(),
-				account: model.Accounts{ID: "testID", Owner: "testOwner", Balance: 20000, Currency: "IDR"},
+				account: model.Accounts{ID: "testID", Number: "ID6400000000000001", Owner: "testOwner", Balance: 20000, Currency: "IDR", Nickname: "Travel IDR", Type: "savings"},
 			},
 			actual: func(s sqlmock.Sqlmock) {
-				s.ExpectQuery(regexp.QuoteMeta("INSERT INTO accounts (id, owner, balance, currency) VALUES ($1, $2, $3, $4) RETURNING id, owner, balance, currency, created_at")).
-					WithArgs("testID", "testOwner", 20000, "IDR").
-					WillReturnRows(s.NewRows([]string{"id", "owner", "balance", "currency", "created_at"}).
-						AddRow("testID", "testOwner", 20000, "IDR", time.Time{}))
+				s.ExpectQuery(regexp.QuoteMeta("INSERT INTO accounts (id, number, owner, balance, currency, nickname, type, unique_per_currency) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, number, owner, balance, currency, nickname, type, held_balance, created_at")).
+					WithArgs("testID", "ID6400000000000001", "testOwner", 20000, "IDR", "Travel IDR", "savings", false).
+					WillReturnRows(s.NewRows([]string{"id", "number", "owner", "balance", "currency", "nickname", "type", "held_balance", "created_at"}).
+						AddRow("testID", "ID6400000000000001", "testOwner", 20000, "IDR", "Travel IDR", "savings", 0, time.Time{}))
 			},
-			want:    model.Accounts{ID: "testID", Owner: "testOwner", Balance: 20000, Currency: "IDR"},
+			want:    model.Accounts{ID: "testID", Number: "ID6400000000000001", Owner: "testOwner", Balance: 20000, Currency: "IDR", Nickname: "Travel IDR", Type: "savings"},
 			wantErr: false,
 		},
 		{
 			name: "failed create account",
 			args: args{
 				ctx:     context.TODO(),
-				account: model.Accounts{ID: "testID", Owner: "testOwner", Balance: 20000, Currency: "IDR"},
+				account: model.Accounts{ID: "testID", Number: "ID6400000000000001", Owner: "testOwner", Balance: 20000, Currency: "IDR", Nickname: "Travel IDR", Type: "savings"},
 			},
 			actual: func(s sqlmock.Sqlmock) {
-				s.ExpectQuery(regexp.QuoteMeta("INSERT INTO accounts (id, owner, balance, currency) VALUES ($1, $2, $3, $4) RETURNING id, owner, balance, currency, created_at")).
-					WithArgs("testID", "testOwner", 20000, "IDR").
+				s.ExpectQuery(regexp.QuoteMeta("INSERT INTO accounts (id, number, owner, balance, currency, nickname, type, unique_per_currency) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, number, owner, balance, currency, nickname, type, held_balance, created_at")).
+					WithArgs("testID", "ID6400000000000001", "testOwner", 20000, "IDR", "Travel IDR", "savings", false).
 					WillReturnError(errors.New("failed"))
 			},
 			want:    model.Accounts{},
@@ -100,12 +100,12 @@ func TestGetAccount(t *testing.T) {
 				id:  "testID",
 			},
 			actual: func(s sqlmock.Sqlmock) {
-				s.ExpectQuery(regexp.QuoteMeta("SELECT id, owner, balance, currency FROM accounts WHERE id = $1 LIMIT 1")).
+				s.ExpectQuery(regexp.QuoteMeta("SELECT id, number, owner, balance, currency, nickname, type, held_balance, frozen_at FROM accounts WHERE id = $1 LIMIT 1")).
 					WithArgs("testID").
-					WillReturnRows(s.NewRows([]string{"id", "owner", "balance", "currency"}).
-						AddRow("testID", "testOwner", 20000, "IDR"))
+					WillReturnRows(s.NewRows([]string{"id", "number", "owner", "balance", "currency", "nickname", "type", "held_balance", "frozen_at"}).
+						AddRow("testID", "ID6400000000000001", "testOwner", 20000, "IDR", "", "checking", 0, nil))
 			},
-			want:    model.Accounts{ID: "testID", Owner: "testOwner", Balance: 20000, Currency: "IDR"},
+			want:    model.Accounts{ID: "testID", Number: "ID6400000000000001", Owner: "testOwner", Balance: 20000, Currency: "IDR", Type: "checking"},
 			wantErr: false,
 		},
 		{
@@ -115,7 +115,7 @@ func TestGetAccount(t *testing.T) {
 				id:  "testID",
 			},
 			actual: func(s sqlmock.Sqlmock) {
-				s.ExpectQuery(regexp.QuoteMeta("SELECT id, owner, balance, currency FROM accounts WHERE id = $1")).
+				s.ExpectQuery(regexp.QuoteMeta("SELECT id, number, owner, balance, currency, nickname, type, held_balance FROM accounts WHERE id = $1")).
 					WithArgs("testID").
 					WillReturnError(errors.New("failed"))
 			},
@@ -164,15 +164,15 @@ func TestUpdateAccount(t *testing.T) {
 			name: "success to update account",
 			args: args{
 				ctx:     context.TODO(),
-				account: model.Accounts{ID: "testID", Owner: "testOwner", Balance: 50000, Currency: "IDR"},
+				account: model.Accounts{ID: "testID", Number: "ID6400000000000001", Owner: "testOwner", Balance: 50000, Currency: "IDR"},
 			},
 			actual: func(s sqlmock.Sqlmock) {
-				s.ExpectQuery(regexp.QuoteMeta("UPDATE accounts SET balance = $2 WHERE id = $1 RETURNING id, owner, balance, currency, created_at")).
+				s.ExpectQuery(regexp.QuoteMeta("UPDATE accounts SET balance = $2 WHERE id = $1 RETURNING id, number, owner, balance, currency, nickname, type, held_balance, created_at")).
 					WithArgs("testID", 50000).
-					WillReturnRows(s.NewRows([]string{"id", "owner", "balance", "currency", "created_at"}).
-						AddRow("testID", "testOwner", 50000, "IDR", time.Time{}))
+					WillReturnRows(s.NewRows([]string{"id", "number", "owner", "balance", "currency", "nickname", "type", "held_balance", "created_at"}).
+						AddRow("testID", "ID6400000000000001", "testOwner", 50000, "IDR", "", "checking", 0, time.Time{}))
 			},
-			want:    model.Accounts{ID: "testID", Owner: "testOwner", Balance: 50000, Currency: "IDR"},
+			want:    model.Accounts{ID: "testID", Number: "ID6400000000000001", Owner: "testOwner", Balance: 50000, Currency: "IDR", Type: "checking"},
 			wantErr: false,
 		},
 
@@ -180,10 +180,10 @@ func TestUpdateAccount(t *testing.T) {
 			name: "failed to update account",
 			args: args{
 				ctx:     context.TODO(),
-				account: model.Accounts{ID: "testID", Owner: "testOwner", Balance: 50000, Currency: "IDR"},
+				account: model.Accounts{ID: "testID", Number: "ID6400000000000001", Owner: "testOwner", Balance: 50000, Currency: "IDR"},
 			},
 			actual: func(s sqlmock.Sqlmock) {
-				s.ExpectQuery(regexp.QuoteMeta("UPDATE accounts SET balance = $2 WHERE id = $1 RETURNING id, owner, balance, currency, created_at")).
+				s.ExpectQuery(regexp.QuoteMeta("UPDATE accounts SET balance = $2 WHERE id = $1 RETURNING id, number, owner, balance, currency, nickname, type, held_balance, created_at")).
 					WithArgs("testID", 50000).
 					WillReturnError(errors.New("failed"))
 			},
@@ -215,6 +215,76 @@ func TestUpdateAccount(t *testing.T) {
 	}
 }
 
+func TestSetFrozenAccount(t *testing.T) {
+	frozenAt := time.Date(2023, 11, 9, 9, 45, 0, 0, time.UTC)
+	const query = "UPDATE accounts SET frozen_at = CASE WHEN $2 THEN COALESCE(frozen_at, now()) END WHERE id = $1 RETURNING id, number, owner, balance, currency, nickname, type, held_balance, frozen_at, created_at"
+	columns := []string{"id", "number", "owner", "balance", "currency", "nickname", "type", "held_balance", "frozen_at", "created_at"}
+
+	test := []struct {
+		name    string
+		frozen  bool
+		actual  func(sqlmock.Sqlmock)
+		want    model.Accounts
+		wantErr bool
+	}{
+		{
+			name:   "freeze",
+			frozen: true,
+			actual: func(s sqlmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("testID", true).
+					WillReturnRows(s.NewRows(columns).
+						AddRow("testID", "ID6400000000000001", "testOwner", 50000, "IDR", "", "checking", 0, frozenAt, time.Time{}))
+			},
+			want: model.Accounts{ID: "testID", Number: "ID6400000000000001", Owner: "testOwner", Balance: 50000, Currency: "IDR", Type: "checking", FrozenAt: &frozenAt},
+		},
+		{
+			name:   "unfreeze",
+			frozen: false,
+			actual: func(s sqlmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("testID", false).
+					WillReturnRows(s.NewRows(columns).
+						AddRow("testID", "ID6400000000000001", "testOwner", 50000, "IDR", "", "checking", 0, nil, time.Time{}))
+			},
+			want: model.Accounts{ID: "testID", Number: "ID6400000000000001", Owner: "testOwner", Balance: 50000, Currency: "IDR", Type: "checking"},
+		},
+		{
+			name:   "failed to freeze account",
+			frozen: true,
+			actual: func(s sqlmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("testID", true).
+					WillReturnError(errors.New("failed"))
+			},
+			want:    model.Accounts{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			tt.actual(mock)
+
+			r := NewAccountsRepository(sqlx.NewDb(db, "sqlmock"))
+			got, err := r.SetFrozen(context.TODO(), "testID", tt.frozen)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetFrozen() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SetFrozen() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestDeleteAccount(t *testing.T) {
 	type args struct {
 		ctx context.Context
@@ -280,7 +350,7 @@ func TestDeleteAccount(t *testing.T) {
 func TestListAccounts(t *testing.T) {
 	type args struct {
 		ctx    context.Context
-		owner  string
+		filter model.AccountsFilter
 		limit  int
 		offset int
 	}
@@ -296,43 +366,76 @@ func TestListAccounts(t *testing.T) {
 			name: "success to list accounts",
 			args: args{
 				ctx:    context.TODO(),
-				owner:  "testOwner",
+				filter: model.AccountsFilter{Owner: "testOwner"},
 				limit:  10,
 				offset: 0,
 			},
 			actual: func(s sqlmock.Sqlmock) {
-				rows := s.NewRows([]string{"id", "owner", "balance", "currency"}).
-					AddRow("testId", "testOwner", 50000, "IDR").
-					AddRow("testId", "testOwner", 4, "USD")
+				rows := s.NewRows([]string{"id", "number", "owner", "balance", "currency", "nickname", "type", "held_balance"}).
+					AddRow("testId", "ID6400000000000001", "testOwner", 50000, "IDR", "", "checking", 0).
+					AddRow("testId", "ID6400000000000001", "testOwner", 4, "USD", "Travel USD", "savings", 0)
 
-				s.ExpectQuery(regexp.QuoteMeta("SELECT id, owner, balance, currency FROM accounts WHERE owner = $1 LIMIT $2 OFFSET $3 ORDER BY id")).
+				s.ExpectQuery(regexp.QuoteMeta("SELECT id, number, owner, balance, currency, nickname, type, held_balance FROM accounts WHERE owner = $1 AND ($2 = '' OR currency = $2) AND ($3 = '' OR type = $3) ORDER BY id LIMIT $4 OFFSET $5")).
+					WithArgs("testOwner", "", "", 10, 0).
 					WillReturnRows(rows)
 			},
 			want: []model.Accounts{{
 				ID:       "testId",
+				Number:   "ID6400000000000001",
 				Owner:    "testOwner",
 				Balance:  50000,
 				Currency: "IDR",
+				Type:     "checking",
 			},
 				{
 					ID:       "testId",
+					Number:   "ID6400000000000001",
 					Owner:    "testOwner",
 					Balance:  4,
 					Currency: "USD",
+					Nickname: "Travel USD",
+					Type:     "savings",
 				},
 			},
 			wantErr: false,
 		},
+		{
+			name: "success to list accounts filtered by currency and type",
+			args: args{
+				ctx:    context.TODO(),
+				filter: model.AccountsFilter{Owner: "testOwner", Currency: "USD", Type: "savings"},
+				limit:  10,
+				offset: 0,
+			},
+			actual: func(s sqlmock.Sqlmock) {
+				rows := s.NewRows([]string{"id", "number", "owner", "balance", "currency", "nickname", "type", "held_balance"}).
+					AddRow("testId", "ID6400000000000001", "testOwner", 4, "USD", "Travel USD", "savings", 0)
+
+				s.ExpectQuery(regexp.QuoteMeta("SELECT id, number, owner, balance, currency, nickname, type, held_balance FROM accounts WHERE owner = $1 AND ($2 = '' OR currency = $2) AND ($3 = '' OR type = $3) ORDER BY id LIMIT $4 OFFSET $5")).
+					WithArgs("testOwner", "USD", "savings", 10, 0).
+					WillReturnRows(rows)
+			},
+			want: []model.Accounts{{
+				ID:       "testId",
+				Number:   "ID6400000000000001",
+				Owner:    "testOwner",
+				Balance:  4,
+				Currency: "USD",
+				Nickname: "Travel USD",
+				Type:     "savings",
+			}},
+			wantErr: false,
+		},
 		{
 			name: "failed to list accounts",
 			args: args{
 				ctx:    context.TODO(),
-				owner:  "testOwner",
+				filter: model.AccountsFilter{Owner: "testOwner"},
 				limit:  10,
 				offset: 0,
 			},
 			actual: func(s sqlmock.Sqlmock) {
-				s.ExpectQuery(regexp.QuoteMeta("SELECT id, owner, balance, currency FROM accounts WHERE owner = $1 LIMIT $2 OFFSET $3 ORDER BY id")).
+				s.ExpectQuery(regexp.QuoteMeta("SELECT id, number, owner, balance, currency, nickname, type, held_balance FROM accounts WHERE owner = $1 AND ($2 = '' OR currency = $2) AND ($3 = '' OR type = $3) ORDER BY id LIMIT $4 OFFSET $5")).
 					WillReturnError(errors.New("failed"))
 			},
 			want:    []model.Accounts{},
@@ -352,7 +455,7 @@ func TestListAccounts(t *testing.T) {
 
 			r := NewAccountsRepository(sqlx.NewDb(db, "sqlmock"))
 
-			accounts, err := r.List(tt.args.ctx, tt.args.owner, tt.args.limit, tt.args.offset)
+			accounts, err := r.List(tt.args.ctx, tt.args.filter, tt.args.limit, tt.args.offset)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("List() error = %v, wantErr %v", err, tt.wantErr)
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/terajari/bank-api/model"
+)
+
+type BatchJobsRepository interface {
+	Create(ctx context.Context, job model.BatchJob) (model.BatchJob, error)
+	Get(ctx context.Context, id string) (model.BatchJob, error)
+	ClaimNext(ctx context.Context) (model.BatchJob, error)
+	Heartbeat(ctx context.Context, id string, progress json.RawMessage) error
+	Requeue(ctx context.Context, id string, progress json.RawMessage) error
+	RequeueStale(ctx context.Context, staleBefore time.Time, maxAttempts int) (int, int, error)
+	Finish(ctx context.Context, id, status string, result json.RawMessage, errMsg string) error
+}
+
+type batchJobsRepository struct {
+	db DBTX
+}
+
+func NewBatchJobsRepository(db DBTX) BatchJobsRepository {
+	return &batchJobsRepository{db: db}
+}
+
+const batchJobColumns = "id, owner, sender_id, mode, item_count, status, request, result, error, created_at, started_at, heartbeat_at, attempts, finished_at"
+
+func scanBatchJob(row rowScanner) (model.BatchJob, error) {
+	var j model.BatchJob
+	var request, result []byte
+	if err := row.Scan(&j.ID, &j.Owner, &j.SenderId, &j.Mode, &j.ItemCount, &j.Status, &request, &result, &j.Error, &j.CreatedAt, &j.StartedAt, &j.HeartbeatAt, &j.Attempts, &j.FinishedAt); err != nil {
+		return model.BatchJob{}, err
+	}
+	j.Request = request
+	j.Result = result
+	return j, nil
+}
+
+func (b *batchJobsRepository) Create(ctx context.Context, job model.BatchJob) (model.BatchJob, error) {
+	query := "INSERT INTO batch_jobs (id, owner, sender_id, mode, item_count, request) VALUES ($1, $2, $3, $4, $5, $6) RETURNING " + batchJobColumns
+	return scanBatchJob(b.db.QueryRowContext(ctx, query, job.ID, job.Owner, job.SenderId, job.Mode, job.ItemCount, []byte(job.Request)))
+}
+
+func (b *batchJobsRepository) Get(ctx context.Context, id string) (model.BatchJob, error) {
+	query := "SELECT " + batchJobColumns + " FROM batch_jobs WHERE id = $1 LIMIT 1"
+	return scanBatchJob(b.db.QueryRowContext(ctx, query, id))
+}
+
+// ClaimNext marks the oldest queued job as running and returns it, or
+// sql.ErrNoRows when the queue is empty. Concurrent workers skip each other's rows.
+func (b *batchJobsRepository) ClaimNext(ctx context.Context) (model.BatchJob, error) {
+	query := `UPDATE batch_jobs SET status = 'running', started_at = COALESCE(started_at, now()), heartbeat_at = now(), attempts = attempts + 1
+	WHERE id = (
+		SELECT id FROM batch_jobs WHERE status = 'queued' ORDER BY created_at LIMIT 1 FOR UPDATE SKIP LOCKED
+	)
+	RETURNING ` + batchJobColumns
+	return scanBatchJob(b.db.QueryRowContext(ctx, query))
+}
+
+// Heartbeat extends the lease of a running job and, unless progress is nil,
+// stores it as the job's result so far. It returns sql.ErrNoRows when the job
+// is no longer running, e.g. after it was requeued as stale.
+func (b *batchJobsRepository) Heartbeat(ctx context.Context, id string, progress json.RawMessage) error {
+	query := "UPDATE batch_jobs SET heartbeat_at = now(), result = COALESCE($2, result) WHERE id = $1 AND status = 'running'"
+	return b.updateRunning(ctx, query, id, progress)
+}
+
+// Requeue puts a running job back in the queue with its progress, so the next
+// worker resumes it.
+func (b *batchJobsRepository) Requeue(ctx context.Context, id string, progress json.RawMessage) error {
+	query := "UPDATE batch_jobs SET status = 'queued', heartbeat_at = NULL, result = COALESCE($2, result) WHERE id = $1 AND status = 'running'"
+	return b.updateRunning(ctx, query, id, progress)
+}
+
+func (b *batchJobsRepository) updateRunning(ctx context.Context, query, id string, progress json.RawMessage) error {
+	var res interface{}
+	if progress != nil {
+		res = []byte(progress)
+	}
+	result, err := b.db.ExecContext(ctx, query, id, res)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// RequeueStale queues the running jobs whose last heartbeat is older than
+// staleBefore again, or fails them once they were claimed maxAttempts times.
+// It returns the number of requeued and failed jobs.
+func (b *batchJobsRepository) RequeueStale(ctx context.Context, staleBefore time.Time, maxAttempts int) (int, int, error) {
+	query := `WITH stale AS (
+	  UPDATE batch_jobs SET
+	    status = CASE WHEN attempts >= $2 THEN 'failed' ELSE 'queued' END,
+	    error = CASE WHEN attempts >= $2 THEN 'abandoned by its worker ' || attempts || ' times' ELSE error END,
+	    finished_at = CASE WHEN attempts >= $2 THEN now() END,
+	    heartbeat_at = NULL
+	  WHERE status = 'running' AND heartbeat_at < $1
+	  RETURNING status
+	) SELECT COUNT(*) FILTER (WHERE status = 'queued'), COUNT(*) FILTER (WHERE status = 'failed') FROM stale`
+
+	var requeued, failed int
+	if err := b.db.QueryRowContext(ctx, query, staleBefore, maxAttempts).Scan(&requeued, &failed); err != nil {
+		return 0, 0, err
+	}
+	return requeued, failed, nil
+}
+
+func (b *batchJobsRepository) Finish(ctx context.Context, id, status string, result json.RawMessage, errMsg string) error {
+	query := "UPDATE batch_jobs SET status = $2, result = $3, error = $4, heartbeat_at = NULL, finished_at = now() WHERE id = $1"
+	var res interface{}
+	if result != nil {
+		res = []byte(result)
+	}
+	_, err := b.db.ExecContext(ctx, query, id, status, res, errMsg)
+	return err
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestBatchJobLease(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := NewBatchJobsRepository(sqlx.NewDb(db, "sqlmock"))
+
+	progress := json.RawMessage(`{"succeeded":1}`)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE batch_jobs SET heartbeat_at = now(), result = COALESCE($2, result) WHERE id = $1 AND status = 'running'")).
+		WithArgs("job1", []byte(progress)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := r.Heartbeat(context.TODO(), "job1", progress); err != nil {
+		t.Errorf("Heartbeat() error = %v", err)
+	}
+
+	// The job was requeued as stale meanwhile.
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE batch_jobs SET heartbeat_at = now()")).
+		WithArgs("job1", nil).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	if err := r.Heartbeat(context.TODO(), "job1", nil); err != sql.ErrNoRows {
+		t.Errorf("Heartbeat() error = %v, want sql.ErrNoRows", err)
+	}
+
+	staleBefore := time.Date(2023, 11, 10, 10, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE status = 'running' AND heartbeat_at < $1")).
+		WithArgs(staleBefore, 3).
+		WillReturnRows(mock.NewRows([]string{"requeued", "failed"}).AddRow(2, 1))
+	requeued, failed, err := r.RequeueStale(context.TODO(), staleBefore, 3)
+	if err != nil || requeued != 2 || failed != 1 {
+		t.Errorf("RequeueStale() = %d, %d, %v, want 2, 1, nil", requeued, failed, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DBTX is what the repositories run their queries on. Both *sqlx.DB and
+// *sqlx.Tx implement it, so a repository built on a transaction takes part
+// in it.
+type DBTX interface {
+	sqlx.ExtContext
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// txScope is the transaction a repository method runs in. When the
+// repository is built on a *sqlx.Tx the method joins that transaction and
+// leaves committing or rolling it back to whoever began it.
+type txScope struct {
+	*sqlx.Tx
+	owned bool
+}
+
+func beginTx(ctx context.Context, db DBTX, opts *sql.TxOptions) (*txScope, error) {
+	switch db := db.(type) {
+	case *sqlx.DB:
+		tx, err := db.BeginTxx(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &txScope{Tx: tx, owned: true}, nil
+	case *sqlx.Tx:
+		return &txScope{Tx: db}, nil
+	case *txScope:
+		return &txScope{Tx: db.Tx}, nil
+	}
+	return nil, fmt.Errorf("repository: cannot begin a transaction on %T", db)
+}
+
+func (t *txScope) Commit() error {
+	if !t.owned {
+		return nil
+	}
+	return t.Tx.Commit()
+}
+
+func (t *txScope) Rollback() error {
+	if !t.owned {
+		return nil
+	}
+	return t.Tx.Rollback()
+}
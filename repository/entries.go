@@ -2,8 +2,9 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
-	"github.com/jmoiron/sqlx"
 	"github.com/terajari/bank-api/model"
 )
 
@@ -11,22 +12,25 @@ type EntryRepository interface {
 	Create(ctx context.Context, entry model.Entries) (model.Entries, error)
 	Get(ctx context.Context, id string) (model.Entries, error)
 	List(ctx context.Context, accountId string, limit, offset int) ([]model.Entries, error)
+	StreamStatement(ctx context.Context, accountId string, from, to time.Time, begin func(opening, closing int64) error, each func(model.StatementEntry) error) error
 }
 
 type entryRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
-func NewEntryRepository(db *sqlx.DB) EntryRepository {
+func NewEntryRepository(db DBTX) EntryRepository {
 	return &entryRepository{db: db}
 }
 
+// Create records an entry. An entry without a transfer ID, such as an
+// initial deposit, is stored with a NULL transfer_id.
 func (r *entryRepository) Create(ctx context.Context, entry model.Entries) (model.Entries, error) {
-	query := "INSERT INTO entries (id, account_id, amount, created_at) VALUES ($1, $2, $3, $4) RETURNING id, account_id, amount, created_at"
+	query := "INSERT INTO entries (id, account_id, amount, transfer_id) VALUES ($1, $2, $3, NULLIF($4, '')) RETURNING id, account_id, amount, COALESCE(transfer_id, ''), created_at"
 
-	row := r.db.QueryRowContext(ctx, query, entry.ID, entry.AccountId, entry.Amount, entry.CreatedAt)
+	row := r.db.QueryRowContext(ctx, query, entry.ID, entry.AccountId, entry.Amount, entry.TransferId)
 	var e model.Entries
-	if err := row.Scan(&e.ID, &e.AccountId, &e.Amount, &e.CreatedAt); err != nil {
+	if err := row.Scan(&e.ID, &e.AccountId, &e.Amount, &e.TransferId, &e.CreatedAt); err != nil {
 		return model.Entries{}, err
 	}
 
@@ -62,3 +66,54 @@ func (r *entryRepository) List(ctx context.Context, accountId string, limit, off
 	}
 	return entries, nil
 }
+
+const statementEntriesQuery = `SELECT e.id, COALESCE(e.transfer_id, ''),
+	CASE WHEN fs.id IS NOT NULL OR fr.id IS NOT NULL THEN 'fee' ELSE 'transfer' END,
+	COALESCE(CASE WHEN fs.id IS NOT NULL THEN fs.revenue_account_id WHEN t.sender_id = e.account_id THEN t.receiver_id ELSE t.sender_id END, ''),
+	COALESCE(t.description, ''), COALESCE(t.reference, ''), e.amount, e.created_at
+FROM entries e
+LEFT JOIN transfers t ON t.id = e.transfer_id
+LEFT JOIN transfer_fees fs ON fs.sender_entry_id = e.id
+LEFT JOIN transfer_fees fr ON fr.revenue_entry_id = e.id
+WHERE e.account_id = $1 AND e.created_at >= $2 AND e.created_at < $3
+ORDER BY e.created_at, e.id`
+
+// StreamStatement reads the balances around [from, to) and then every entry
+// in that range, oldest first, from one repeatable-read snapshot so the
+// entries always add up to the reported balances. Rows are passed to each as
+// they are read and never collected.
+func (r *entryRepository) StreamStatement(ctx context.Context, accountId string, from, to time.Time, begin func(opening, closing int64) error, each func(model.StatementEntry) error) error {
+	tx, err := beginTx(ctx, r.db, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	queryBalances := "SELECT a.balance - COALESCE(SUM(e.amount), 0), a.balance - COALESCE(SUM(e.amount) FILTER (WHERE e.created_at >= $3), 0) FROM accounts a LEFT JOIN entries e ON e.account_id = a.id AND e.created_at >= $2 WHERE a.id = $1 GROUP BY a.balance"
+	var opening, closing int64
+	if err := tx.QueryRowContext(ctx, queryBalances, accountId, from, to).Scan(&opening, &closing); err != nil {
+		return err
+	}
+	if err := begin(opening, closing); err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, statementEntriesQuery, accountId, from, to)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var e model.StatementEntry
+		if err := rows.Scan(&e.EntryId, &e.TransferId, &e.Kind, &e.Counterparty, &e.Description, &e.Reference, &e.Amount, &e.CreatedAt); err != nil {
+			return err
+		}
+		if err := each(e); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
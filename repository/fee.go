@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/terajari/bank-api/model"
+)
+
+type FeeRulesRepository interface {
+	ListActive(ctx context.Context, currency, accountType string) ([]model.FeeRule, error)
+}
+
+type feeRulesRepository struct {
+	db DBTX
+}
+
+func NewFeeRulesRepository(db DBTX) FeeRulesRepository {
+	return &feeRulesRepository{db: db}
+}
+
+func (r *feeRulesRepository) ListActive(ctx context.Context, currency, accountType string) ([]model.FeeRule, error) {
+	query := `SELECT id, name, kind, flat_amount, rate_bps, min_amount, max_amount, currency, account_type, active, priority, created_at
+	FROM fee_rules
+	WHERE active AND (currency IS NULL OR currency = $1) AND (account_type IS NULL OR account_type = $2)
+	ORDER BY priority, id`
+
+	rows, err := r.db.QueryContext(ctx, query, currency, accountType)
+	if err != nil {
+		return []model.FeeRule{}, err
+	}
+	defer rows.Close()
+	var rules []model.FeeRule
+	for rows.Next() {
+		var f model.FeeRule
+		if err := rows.Scan(&f.ID, &f.Name, &f.Kind, &f.FlatAmount, &f.RateBps, &f.MinAmount, &f.MaxAmount, &f.Currency, &f.AccountType, &f.Active, &f.Priority, &f.CreatedAt); err != nil {
+			return []model.FeeRule{}, err
+		}
+		rules = append(rules, f)
+	}
+	return rules, rows.Err()
+}
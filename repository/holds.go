@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/terajari/bank-api/apperror"
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/model"
+)
+
+var (
+	ErrInsufficientFunds  = apperror.ErrInsufficientFunds
+	ErrAccountFrozen      = apperror.ErrAccountFrozen
+	ErrCaptureExceedsHold = apperror.New(apperror.KindUnprocessable, "capture_exceeds_hold", "capture amount exceeds the authorized amount")
+)
+
+type HoldsRepository interface {
+	Get(ctx context.Context, id string) (model.Hold, error)
+	AuthorizeTx(ctx context.Context, hold model.Hold, coolingOff model.PayeeCoolingOff) (model.Hold, error)
+	CaptureTx(ctx context.Context, id string, transfer model.Transfer, fees []model.TransferFee) (model.Hold, dto.MakeTransferResponse, error)
+	VoidTx(ctx context.Context, id string) (model.Hold, error)
+	ExpireDue(ctx context.Context, limit int) (int, error)
+}
+
+type holdsRepository struct {
+	db DBTX
+}
+
+func NewHoldsRepository(db DBTX) HoldsRepository {
+	return &holdsRepository{db: db}
+}
+
+const holdColumns = "id, account_id, receiver_id, currency, amount, reserved, captured_amount, status, transfer_id, expires_at, created_at, updated_at"
+
+func scanHold(row rowScanner) (model.Hold, error) {
+	var h model.Hold
+	if err := row.Scan(&h.ID, &h.AccountId, &h.ReceiverId, &h.Currency, &h.Amount, &h.Reserved, &h.CapturedAmount, &h.Status, &h.TransferId, &h.ExpiresAt, &h.CreatedAt, &h.UpdatedAt); err != nil {
+		return model.Hold{}, err
+	}
+	return h, nil
+}
+
+func (h *holdsRepository) Get(ctx context.Context, id string) (model.Hold, error) {
+	query := "SELECT " + holdColumns + " FROM holds WHERE id = $1 LIMIT 1"
+	return scanHold(h.db.QueryRowContext(ctx, query, id))
+}
+
+// AuthorizeTx reserves hold.Reserved on the account. The transfer limits are
+// checked against the authorized amount, and until it is captured the hold
+// counts towards the limits of later transfers and holds. The same goes for
+// the cooling-off of a payee the receiver was recently added as.
+func (h *holdsRepository) AuthorizeTx(ctx context.Context, hold model.Hold, coolingOff model.PayeeCoolingOff) (model.Hold, error) {
+	tx, err := beginTx(ctx, h.db, &sql.TxOptions{})
+	if err != nil {
+		return model.Hold{}, err
+	}
+	defer tx.Rollback()
+
+	sender, err := lockSender(ctx, tx, hold.AccountId)
+	if err != nil {
+		return model.Hold{}, err
+	}
+
+	transfer := model.Transfer{
+		SenderId:   hold.AccountId,
+		ReceiverId: hold.ReceiverId,
+		Amount:     hold.Amount,
+	}
+	if err := checkTransferLimits(ctx, tx, sender.Owner, sender.Currency, transfer); err != nil {
+		return model.Hold{}, err
+	}
+	if err := checkPayeeCoolingOff(ctx, tx, sender.Owner, transfer, coolingOff); err != nil {
+		return model.Hold{}, err
+	}
+
+	if sender.AvailableBalance() < hold.Reserved {
+		return model.Hold{}, ErrInsufficientFunds
+	}
+
+	query := "INSERT INTO holds (id, account_id, receiver_id, currency, amount, reserved, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING " + holdColumns
+	created, err := scanHold(tx.QueryRowContext(ctx, query, hold.ID, hold.AccountId, hold.ReceiverId, sender.Currency, hold.Amount, hold.Reserved, hold.ExpiresAt))
+	if err != nil {
+		return model.Hold{}, err
+	}
+
+	if err := addHeldBalance(ctx, tx, hold.AccountId, hold.Reserved); err != nil {
+		return model.Hold{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Hold{}, err
+	}
+	return created, nil
+}
+
+// CaptureTx releases the reservation and posts transfer with its fees. It
+// returns sql.ErrNoRows when the hold is not authorized or has expired and
+// ErrAccountFrozen when the sender or the receiver is frozen.
+func (h *holdsRepository) CaptureTx(ctx context.Context, id string, transfer model.Transfer, fees []model.TransferFee) (model.Hold, dto.MakeTransferResponse, error) {
+	var response dto.MakeTransferResponse
+	tx, err := beginTx(ctx, h.db, &sql.TxOptions{})
+	if err != nil {
+		return model.Hold{}, dto.MakeTransferResponse{}, err
+	}
+	defer tx.Rollback()
+
+	hold, err := lockAuthorizedHold(ctx, tx, id)
+	if err != nil {
+		return model.Hold{}, dto.MakeTransferResponse{}, err
+	}
+	if transfer.Amount > hold.Amount {
+		return model.Hold{}, dto.MakeTransferResponse{}, ErrCaptureExceedsHold
+	}
+
+	sender, err := lockSender(ctx, tx, hold.AccountId)
+	if err != nil {
+		return model.Hold{}, dto.MakeTransferResponse{}, err
+	}
+	if err := lockReceiver(ctx, tx, hold.ReceiverId); err != nil {
+		return model.Hold{}, dto.MakeTransferResponse{}, err
+	}
+
+	var totalFee int64
+	for _, fee := range fees {
+		totalFee += fee.Amount
+	}
+	if sender.AvailableBalance()+hold.Reserved < transfer.Amount+totalFee {
+		return model.Hold{}, dto.MakeTransferResponse{}, ErrInsufficientFunds
+	}
+	if err := addHeldBalance(ctx, tx, hold.AccountId, -hold.Reserved); err != nil {
+		return model.Hold{}, dto.MakeTransferResponse{}, err
+	}
+
+	transfer.SenderId = hold.AccountId
+	transfer.ReceiverId = hold.ReceiverId
+	transfer.Status = model.TransferStatusCompleted
+	tr, err := insertTransfer(ctx, tx, transfer)
+	if err != nil {
+		return model.Hold{}, dto.MakeTransferResponse{}, err
+	}
+	response.Transfer = tr
+
+	if err := postTransfer(ctx, tx, tr, fees, &response); err != nil {
+		return model.Hold{}, dto.MakeTransferResponse{}, err
+	}
+
+	query := "UPDATE holds SET status = 'captured', captured_amount = $2, transfer_id = $3, updated_at = now() WHERE id = $1 RETURNING " + holdColumns
+	captured, err := scanHold(tx.QueryRowContext(ctx, query, id, tr.Amount, tr.ID))
+	if err != nil {
+		return model.Hold{}, dto.MakeTransferResponse{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Hold{}, dto.MakeTransferResponse{}, err
+	}
+	return captured, response, nil
+}
+
+// VoidTx releases an authorized hold. It returns sql.ErrNoRows when the hold
+// is not authorized.
+func (h *holdsRepository) VoidTx(ctx context.Context, id string) (model.Hold, error) {
+	tx, err := beginTx(ctx, h.db, &sql.TxOptions{})
+	if err != nil {
+		return model.Hold{}, err
+	}
+	defer tx.Rollback()
+
+	voided, err := releaseHold(ctx, tx, id, model.HoldStatusVoided)
+	if err != nil {
+		return model.Hold{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Hold{}, err
+	}
+	return voided, nil
+}
+
+// ExpireDue releases up to limit authorized holds whose TTL has passed and
+// reports how many were expired. Holds locked by a capture are skipped.
+func (h *holdsRepository) ExpireDue(ctx context.Context, limit int) (int, error) {
+	tx, err := beginTx(ctx, h.db, &sql.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := "SELECT id FROM holds WHERE status = 'authorized' AND expires_at <= now() ORDER BY expires_at LIMIT $1 FOR UPDATE SKIP LOCKED"
+	var ids []string
+	if err := tx.SelectContext(ctx, &ids, query, limit); err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		if _, err := releaseHold(ctx, tx, id, model.HoldStatusExpired); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+func lockAuthorizedHold(ctx context.Context, tx DBTX, id string) (model.Hold, error) {
+	query := "SELECT " + holdColumns + " FROM holds WHERE id = $1 AND status = 'authorized' AND expires_at > now() FOR UPDATE"
+	return scanHold(tx.QueryRowContext(ctx, query, id))
+}
+
+func releaseHold(ctx context.Context, tx DBTX, id, status string) (model.Hold, error) {
+	query := "UPDATE holds SET status = $2, updated_at = now() WHERE id = $1 AND status = 'authorized' RETURNING " + holdColumns
+	hold, err := scanHold(tx.QueryRowContext(ctx, query, id, status))
+	if err != nil {
+		return model.Hold{}, err
+	}
+	if err := addHeldBalance(ctx, tx, hold.AccountId, -hold.Reserved); err != nil {
+		return model.Hold{}, err
+	}
+	return hold, nil
+}
+
+func addHeldBalance(ctx context.Context, tx DBTX, accountId string, amount int64) error {
+	_, err := tx.ExecContext(ctx, "UPDATE accounts SET held_balance = held_balance + $2 WHERE id = $1", accountId, amount)
+	return err
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/terajari/bank-api/model"
+)
+
+func TestVoidHold(t *testing.T) {
+	holdRows := []string{"id", "account_id", "receiver_id", "currency", "amount", "reserved", "captured_amount", "status", "transfer_id", "expires_at", "created_at", "updated_at"}
+
+	test := []struct {
+		name    string
+		actual  func(sqlmock.Sqlmock)
+		want    model.Hold
+		wantErr error
+	}{
+		{
+			name: "success void hold",
+			actual: func(s sqlmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectQuery(regexp.QuoteMeta("UPDATE holds SET status = $2, updated_at = now() WHERE id = $1 AND status = 'authorized' RETURNING "+holdColumns)).
+					WithArgs("hold1", model.HoldStatusVoided).
+					WillReturnRows(s.NewRows(holdRows).
+						AddRow("hold1", "acc1", "acc2", "IDR", 1000, 1010, 0, model.HoldStatusVoided, nil, time.Time{}, time.Time{}, time.Time{}))
+				s.ExpectExec(regexp.QuoteMeta("UPDATE accounts SET held_balance = held_balance + $2 WHERE id = $1")).
+					WithArgs("acc1", -1010).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				s.ExpectCommit()
+			},
+			want: model.Hold{ID: "hold1", AccountId: "acc1", ReceiverId: "acc2", Currency: "IDR", Amount: 1000, Reserved: 1010, Status: model.HoldStatusVoided},
+		},
+		{
+			name: "hold not authorized",
+			actual: func(s sqlmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectQuery(regexp.QuoteMeta("UPDATE holds SET status = $2")).
+					WithArgs("hold1", model.HoldStatusVoided).
+					WillReturnRows(s.NewRows(holdRows))
+				s.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			tt.actual(mock)
+
+			r := NewHoldsRepository(sqlx.NewDb(db, "sqlmock"))
+			got, err := r.VoidTx(context.TODO(), "hold1")
+			if err != tt.wantErr {
+				t.Errorf("VoidTx() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("VoidTx() got = %v, want %v", got, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestAuthorizeHoldCountsAuthorizedHolds(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	now := time.Date(2023, 11, 2, 10, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT owner FROM accounts WHERE id = $1")).
+		WithArgs("acc1").
+		WillReturnRows(mock.NewRows([]string{"owner"}).AddRow("fulan"))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT username FROM users WHERE username = $1 FOR NO KEY UPDATE")).
+		WithArgs("fulan").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, owner, balance, currency, held_balance, frozen_at FROM accounts")).
+		WithArgs("acc1").
+		WillReturnRows(mock.NewRows([]string{"id", "owner", "balance", "currency", "held_balance", "frozen_at"}).
+			AddRow("acc1", "fulan", 5000, "IDR", 800, nil))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM transfer_limits")).
+		WithArgs("fulan", "acc1", "IDR").
+		WillReturnRows(mock.NewRows([]string{"id", "scope", "subject", "currency", "max_single", "max_daily", "max_monthly", "max_hourly_count", "created_at", "updated_at"}).
+			AddRow("l1", "account", "acc1", "IDR", nil, 1000, nil, nil, now, now))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT now()")).
+		WillReturnRows(mock.NewRows([]string{"now"}).AddRow(now))
+	// The first hold of 800 is authorized and not captured yet, no transfer
+	// was made so far.
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT amount, created_at FROM holds WHERE account_id = $1 AND created_at >= $2 AND status = 'authorized'")).
+		WithArgs("acc1", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(mock.NewRows([]string{"daily", "monthly", "hourly"}).AddRow(800, 800, 1))
+	mock.ExpectRollback()
+
+	_, err = NewHoldsRepository(sqlx.NewDb(db, "sqlmock")).AuthorizeTx(context.TODO(), model.Hold{
+		ID: "hold2", AccountId: "acc1", ReceiverId: "acc2", Amount: 500, Reserved: 500, ExpiresAt: now.Add(time.Hour),
+	}, model.PayeeCoolingOff{Period: 24 * time.Hour, MaxAmount: 100000})
+	var limitErr *model.LimitExceededError
+	if !errors.As(err, &limitErr) || limitErr.Limit != model.LimitMaxDaily || limitErr.Used != 800 {
+		t.Fatalf("AuthorizeTx() error = %v, want the daily limit with 800 used", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCaptureHoldRefusesFrozenReceiver(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM holds WHERE id = $1 AND status = 'authorized' AND expires_at > now() FOR UPDATE")).
+		WithArgs("hold1").
+		WillReturnRows(mock.NewRows([]string{"id", "account_id", "receiver_id", "currency", "amount", "reserved", "captured_amount", "status", "transfer_id", "expires_at", "created_at", "updated_at"}).
+			AddRow("hold1", "acc1", "acc2", "IDR", 500, 500, 0, model.HoldStatusAuthorized, nil, now.Add(time.Hour), now, now))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT owner FROM accounts WHERE id = $1")).
+		WithArgs("acc1").
+		WillReturnRows(mock.NewRows([]string{"owner"}).AddRow("fulan"))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT username FROM users WHERE username = $1 FOR NO KEY UPDATE")).
+		WithArgs("fulan").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, owner, balance, currency, held_balance, frozen_at FROM accounts")).
+		WithArgs("acc1").
+		WillReturnRows(mock.NewRows([]string{"id", "owner", "balance", "currency", "held_balance", "frozen_at"}).
+			AddRow("acc1", "fulan", 5000, "IDR", 500, nil))
+	// The receiver was frozen after the hold was authorized.
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT frozen_at FROM accounts WHERE id = $1 LIMIT 1 FOR NO KEY UPDATE")).
+		WithArgs("acc2").
+		WillReturnRows(mock.NewRows([]string{"frozen_at"}).AddRow(now))
+	mock.ExpectRollback()
+
+	_, _, err = NewHoldsRepository(sqlx.NewDb(db, "sqlmock")).CaptureTx(context.TODO(), "hold1", model.Transfer{ID: "tr1", Amount: 500}, nil)
+	if !errors.Is(err, ErrAccountFrozen) {
+		t.Fatalf("CaptureTx() error = %v, want %v", err, ErrAccountFrozen)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
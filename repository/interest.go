@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/terajari/bank-api/model"
+)
+
+type InterestRepository interface {
+	ListCandidates(ctx context.Context, endOfDay time.Time) ([]model.InterestCandidate, error)
+	CreateAccrual(ctx context.Context, accrual model.InterestAccrual) (bool, error)
+	LastAccrualDate(ctx context.Context) (time.Time, bool, error)
+	ListUnposted(ctx context.Context, before time.Time) ([]model.InterestPosting, error)
+	MarkPosted(ctx context.Context, accountId string, before time.Time, transferId string) (int64, error)
+}
+
+type interestRepository struct {
+	db DBTX
+}
+
+func NewInterestRepository(db DBTX) InterestRepository {
+	return &interestRepository{db: db}
+}
+
+// ListCandidates returns every interest bearing account with its balance at endOfDay,
+// entries booked after endOfDay are taken back out of the current balance.
+func (r *interestRepository) ListCandidates(ctx context.Context, endOfDay time.Time) ([]model.InterestCandidate, error) {
+	query := `SELECT a.id, a.currency, t.annual_interest_rate_bps,
+	  a.balance - COALESCE((SELECT SUM(e.amount) FROM entries e WHERE e.account_id = a.id AND e.created_at >= $1), 0)
+	FROM accounts a JOIN account_types t ON t.name = a.type
+	WHERE t.annual_interest_rate_bps > 0 AND a.created_at < $1
+	ORDER BY a.id`
+
+	rows, err := r.db.QueryContext(ctx, query, endOfDay)
+	if err != nil {
+		return []model.InterestCandidate{}, err
+	}
+	defer rows.Close()
+	var candidates []model.InterestCandidate
+	for rows.Next() {
+		var c model.InterestCandidate
+		if err := rows.Scan(&c.AccountID, &c.Currency, &c.RateBps, &c.Balance); err != nil {
+			return []model.InterestCandidate{}, err
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+// CreateAccrual stores the accrual unless the account already accrued for that day.
+func (r *interestRepository) CreateAccrual(ctx context.Context, accrual model.InterestAccrual) (bool, error) {
+	query := "INSERT INTO interest_accruals (id, account_id, accrual_date, balance, rate_bps, amount) VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (account_id, accrual_date) DO NOTHING"
+	res, err := r.db.ExecContext(ctx, query, accrual.ID, accrual.AccountID, accrual.AccrualDate, accrual.Balance, accrual.RateBps, accrual.Amount)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func (r *interestRepository) LastAccrualDate(ctx context.Context) (time.Time, bool, error) {
+	query := "SELECT MAX(accrual_date) FROM interest_accruals"
+	var last sql.NullTime
+	if err := r.db.QueryRowContext(ctx, query).Scan(&last); err != nil {
+		return time.Time{}, false, err
+	}
+	return last.Time, last.Valid, nil
+}
+
+func (r *interestRepository) ListUnposted(ctx context.Context, before time.Time) ([]model.InterestPosting, error) {
+	query := `SELECT i.account_id, a.currency, SUM(i.amount), COUNT(*)
+	FROM interest_accruals i JOIN accounts a ON a.id = i.account_id
+	WHERE i.posted_at IS NULL AND i.accrual_date < $1
+	GROUP BY i.account_id, a.currency
+	ORDER BY i.account_id`
+
+	rows, err := r.db.QueryContext(ctx, query, before)
+	if err != nil {
+		return []model.InterestPosting{}, err
+	}
+	defer rows.Close()
+	var postings []model.InterestPosting
+	for rows.Next() {
+		var p model.InterestPosting
+		if err := rows.Scan(&p.AccountID, &p.Currency, &p.Amount, &p.Accruals); err != nil {
+			return []model.InterestPosting{}, err
+		}
+		postings = append(postings, p)
+	}
+	return postings, rows.Err()
+}
+
+// MarkPosted assigns the unposted accruals of the account to transferId and
+// returns their total, so the amount posted always matches what was marked.
+func (r *interestRepository) MarkPosted(ctx context.Context, accountId string, before time.Time, transferId string) (int64, error) {
+	query := `WITH marked AS (
+	  UPDATE interest_accruals SET transfer_id = $3, posted_at = now()
+	  WHERE account_id = $1 AND accrual_date < $2 AND posted_at IS NULL
+	  RETURNING amount
+	) SELECT COALESCE(SUM(amount), 0) FROM marked`
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, query, accountId, before, transferId).Scan(&total); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
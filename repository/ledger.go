@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/terajari/bank-api/model"
+)
+
+type LedgerRepository interface {
+	Check(ctx context.Context) (model.LedgerCheck, error)
+}
+
+type ledgerRepository struct {
+	db DBTX
+}
+
+func NewLedgerRepository(db DBTX) LedgerRepository {
+	return &ledgerRepository{db: db}
+}
+
+// Check runs the ledger checks in one read-only repeatable read transaction,
+// so transfers committing meanwhile are either fully seen by all checks or
+// not at all.
+func (l *ledgerRepository) Check(ctx context.Context) (model.LedgerCheck, error) {
+	tx, err := beginTx(ctx, l.db, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return model.LedgerCheck{}, err
+	}
+	defer tx.Rollback()
+
+	var check model.LedgerCheck
+	if check.BalanceDrifts, err = balanceDrifts(ctx, tx); err != nil {
+		return model.LedgerCheck{}, err
+	}
+	if check.TransferIssues, err = transferIssues(ctx, tx); err != nil {
+		return model.LedgerCheck{}, err
+	}
+	if check.CurrencyTotals, err = currencyTotals(ctx, tx); err != nil {
+		return model.LedgerCheck{}, err
+	}
+	return check, tx.Commit()
+}
+
+// balanceDrifts returns the accounts whose balance is not the sum of their
+// entries.
+func balanceDrifts(ctx context.Context, tx DBTX) ([]model.BalanceDrift, error) {
+	query := `SELECT a.id, a.currency, a.balance, COALESCE(e.total, 0)
+	FROM accounts a
+	LEFT JOIN (SELECT account_id, SUM(amount) AS total FROM entries GROUP BY account_id) e ON e.account_id = a.id
+	WHERE a.balance <> COALESCE(e.total, 0)
+	ORDER BY a.id`
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return []model.BalanceDrift{}, err
+	}
+	defer rows.Close()
+	var drifts []model.BalanceDrift
+	for rows.Next() {
+		var d model.BalanceDrift
+		if err := rows.Scan(&d.AccountID, &d.Currency, &d.Balance, &d.EntriesTotal); err != nil {
+			return []model.BalanceDrift{}, err
+		}
+		drifts = append(drifts, d)
+	}
+	return drifts, rows.Err()
+}
+
+// transferIssues returns the transfers whose entries, fee entries left
+// aside, are not exactly a debit of the sender and a credit of the receiver
+// for a completed transfer, or are not absent for any other.
+func transferIssues(ctx context.Context, tx DBTX) ([]model.TransferIssue, error) {
+	query := `SELECT t.id, t.status, t.amount, COUNT(e.id),
+	  COUNT(e.id) FILTER (WHERE e.account_id = t.sender_id AND e.amount = -t.amount),
+	  COUNT(e.id) FILTER (WHERE e.account_id = t.receiver_id AND e.amount = t.amount)
+	FROM transfers t
+	LEFT JOIN entries e ON e.transfer_id = t.id
+	  AND NOT EXISTS (SELECT 1 FROM transfer_fees f WHERE f.sender_entry_id = e.id OR f.revenue_entry_id = e.id)
+	GROUP BY t.id
+	HAVING CASE WHEN t.status = 'completed'
+	  THEN COUNT(e.id) <> 2
+	    OR COUNT(e.id) FILTER (WHERE e.account_id = t.sender_id AND e.amount = -t.amount) <> 1
+	    OR COUNT(e.id) FILTER (WHERE e.account_id = t.receiver_id AND e.amount = t.amount) <> 1
+	  ELSE COUNT(e.id) <> 0 END
+	ORDER BY t.id`
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return []model.TransferIssue{}, err
+	}
+	defer rows.Close()
+	var issues []model.TransferIssue
+	for rows.Next() {
+		var i model.TransferIssue
+		if err := rows.Scan(&i.TransferID, &i.Status, &i.Amount, &i.Entries, &i.SenderEntries, &i.ReceiverEntries); err != nil {
+			return []model.TransferIssue{}, err
+		}
+		issues = append(issues, i)
+	}
+	return issues, rows.Err()
+}
+
+// currencyTotals sums the balances and entries of every currency.
+func currencyTotals(ctx context.Context, tx DBTX) ([]model.CurrencyTotal, error) {
+	query := `SELECT a.currency, SUM(a.balance), COALESCE(SUM(e.total), 0)
+	FROM accounts a
+	LEFT JOIN (SELECT account_id, SUM(amount) AS total FROM entries GROUP BY account_id) e ON e.account_id = a.id
+	GROUP BY a.currency
+	ORDER BY a.currency`
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return []model.CurrencyTotal{}, err
+	}
+	defer rows.Close()
+	var totals []model.CurrencyTotal
+	for rows.Next() {
+		var c model.CurrencyTotal
+		if err := rows.Scan(&c.Currency, &c.Balances, &c.Entries); err != nil {
+			return []model.CurrencyTotal{}, err
+		}
+		totals = append(totals, c)
+	}
+	return totals, rows.Err()
+}
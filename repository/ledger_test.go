@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/terajari/bank-api/model"
+)
+
+func TestLedgerCheck(t *testing.T) {
+	const (
+		driftsQuery    = "SELECT a.id, a.currency, a.balance, COALESCE\\(e.total, 0\\) FROM accounts a"
+		transfersQuery = "SELECT t.id, t.status, t.amount, COUNT\\(e.id\\)"
+		totalsQuery    = "SELECT a.currency, SUM\\(a.balance\\)"
+	)
+
+	t.Run("snapshot", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(driftsQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "currency", "balance", "total"}).
+				AddRow("acc1", "IDR", 5000, 4000))
+		mock.ExpectQuery(transfersQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status", "amount", "entries", "sender", "receiver"}).
+				AddRow("tr1", "completed", 1000, 1, 1, 0))
+		mock.ExpectQuery(totalsQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"currency", "balances", "entries"}).
+				AddRow("IDR", 0, -1000).
+				AddRow("USD", 0, 0))
+		mock.ExpectCommit()
+
+		got, err := NewLedgerRepository(sqlx.NewDb(db, "sqlmock")).Check(context.TODO())
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := model.LedgerCheck{
+			BalanceDrifts:  []model.BalanceDrift{{AccountID: "acc1", Currency: "IDR", Balance: 5000, EntriesTotal: 4000}},
+			TransferIssues: []model.TransferIssue{{TransferID: "tr1", Status: "completed", Amount: 1000, Entries: 1, SenderEntries: 1}},
+			CurrencyTotals: []model.CurrencyTotal{{Currency: "IDR", Entries: -1000}, {Currency: "USD"}},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Check() = %+v, want %+v", got, want)
+		}
+		if got.CurrencyTotals[0].Imbalance() != -1000 {
+			t.Errorf("Imbalance() = %d, want -1000", got.CurrencyTotals[0].Imbalance())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("failed check rolls back", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(driftsQuery).WillReturnRows(sqlmock.NewRows([]string{"id", "currency", "balance", "total"}))
+		mock.ExpectQuery(transfersQuery).WillReturnError(errors.New("canceling statement due to statement timeout"))
+		mock.ExpectRollback()
+
+		if _, err := NewLedgerRepository(sqlx.NewDb(db, "sqlmock")).Check(context.TODO()); err == nil {
+			t.Fatal("Check() error = nil")
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/terajari/bank-api/model"
+)
+
+type limitUsage struct {
+	daily   int64
+	monthly int64
+	hourly  int64
+}
+
+// checkTransferLimits enforces the limits of the sender's owner and of the
+// sender account against the transfers already made in the current windows.
+// The caller must hold the locks that serialize transfers of the owner.
+func checkTransferLimits(ctx context.Context, tx DBTX, owner, currency string, transfer model.Transfer) error {
+	if owner == model.SystemOwner {
+		return nil
+	}
+
+	limits, err := listTransferLimits(ctx, tx, owner, transfer.SenderId, currency)
+	if err != nil {
+		return err
+	}
+	if len(limits) == 0 {
+		return nil
+	}
+
+	var now time.Time
+	if err := tx.QueryRowContext(ctx, "SELECT now()").Scan(&now); err != nil {
+		return err
+	}
+	now = now.UTC()
+	hourStart := now.Truncate(time.Hour)
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	usage := map[string]limitUsage{}
+	for _, limit := range limits {
+		subject := owner
+		if limit.Scope == model.LimitScopeAccount {
+			subject = transfer.SenderId
+		}
+		exceeded := &model.LimitExceededError{
+			Scope:     limit.Scope,
+			Subject:   subject,
+			Requested: transfer.Amount,
+		}
+
+		if limit.MaxSingle != nil && transfer.Amount > *limit.MaxSingle {
+			exceeded.Limit = model.LimitMaxSingle
+			exceeded.Max = *limit.MaxSingle
+			return exceeded
+		}
+
+		used, ok := usage[limit.Scope]
+		if !ok {
+			used, err = transferUsage(ctx, tx, limit.Scope, subject, currency, monthStart, dayStart, hourStart)
+			if err != nil {
+				return err
+			}
+			usage[limit.Scope] = used
+		}
+
+		var resetsAt time.Time
+		switch {
+		case limit.MaxDaily != nil && used.daily+transfer.Amount > *limit.MaxDaily:
+			exceeded.Limit, exceeded.Max, exceeded.Used = model.LimitMaxDaily, *limit.MaxDaily, used.daily
+			resetsAt = dayStart.AddDate(0, 0, 1)
+		case limit.MaxMonthly != nil && used.monthly+transfer.Amount > *limit.MaxMonthly:
+			exceeded.Limit, exceeded.Max, exceeded.Used = model.LimitMaxMonthly, *limit.MaxMonthly, used.monthly
+			resetsAt = monthStart.AddDate(0, 1, 0)
+		case limit.MaxHourlyCount != nil && used.hourly+1 > *limit.MaxHourlyCount:
+			exceeded.Limit, exceeded.Max, exceeded.Used, exceeded.Requested = model.LimitMaxHourlyCount, *limit.MaxHourlyCount, used.hourly, 1
+			resetsAt = hourStart.Add(time.Hour)
+		default:
+			continue
+		}
+		exceeded.ResetsAt = &resetsAt
+		return exceeded
+	}
+	return nil
+}
+
+// listTransferLimits returns the limits in force for the owner and the account,
+// at most one per scope. For each limit kind the row for the subject overrides
+// the `*` row of its scope, so an override can raise a default as well as
+// lower it. Kinds the subject's row leaves unset fall back to the `*` row.
+func listTransferLimits(ctx context.Context, tx DBTX, owner, accountId, currency string) ([]model.TransferLimit, error) {
+	query := `SELECT id, scope, subject, currency, max_single, max_daily, max_monthly, max_hourly_count, created_at, updated_at
+	FROM transfer_limits
+	WHERE currency = $3 AND ((scope = 'user' AND subject IN ($1, '*')) OR (scope = 'account' AND subject IN ($2, '*')))
+	ORDER BY scope DESC, subject = '*'`
+
+	rows, err := tx.QueryContext(ctx, query, owner, accountId, currency)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	// Rows come ordered by scope with the subject's row before the `*` row.
+	var limits []model.TransferLimit
+	for rows.Next() {
+		var l model.TransferLimit
+		if err := rows.Scan(&l.ID, &l.Scope, &l.Subject, &l.Currency, &l.MaxSingle, &l.MaxDaily, &l.MaxMonthly, &l.MaxHourlyCount, &l.CreatedAt, &l.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if n := len(limits); n > 0 && limits[n-1].Scope == l.Scope {
+			inheritLimit(&limits[n-1].MaxSingle, l.MaxSingle)
+			inheritLimit(&limits[n-1].MaxDaily, l.MaxDaily)
+			inheritLimit(&limits[n-1].MaxMonthly, l.MaxMonthly)
+			inheritLimit(&limits[n-1].MaxHourlyCount, l.MaxHourlyCount)
+			continue
+		}
+		limits = append(limits, l)
+	}
+	return limits, rows.Err()
+}
+
+func inheritLimit(max **int64, fallback *int64) {
+	if *max == nil {
+		*max = fallback
+	}
+}
+
+// transferUsage sums the transfers of the subject in the current windows
+// together with its authorized holds, which are transfers yet to be captured.
+func transferUsage(ctx context.Context, tx DBTX, scope, subject, currency string, monthStart, dayStart, hourStart time.Time) (limitUsage, error) {
+	senders := "= $1"
+	if scope == model.LimitScopeUser {
+		senders = "IN (SELECT id FROM accounts WHERE owner = $1 AND currency = $5)"
+	}
+	query := `SELECT
+		COALESCE(SUM(amount) FILTER (WHERE created_at >= $3), 0),
+		COALESCE(SUM(amount), 0),
+		COUNT(*) FILTER (WHERE created_at >= $4)
+	FROM (
+		SELECT amount, created_at FROM transfers
+		WHERE sender_id ` + senders + ` AND created_at >= $2 AND status <> 'rejected'
+		UNION ALL
+		SELECT amount, created_at FROM holds
+		WHERE account_id ` + senders + ` AND created_at >= $2 AND status = 'authorized'
+	) usage`
+
+	args := []interface{}{subject, monthStart, dayStart, hourStart}
+	if scope == model.LimitScopeUser {
+		args = append(args, currency)
+	}
+
+	var u limitUsage
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&u.daily, &u.monthly, &u.hourly); err != nil {
+		return limitUsage{}, err
+	}
+	return u, nil
+}
+
+// checkPayeeCoolingOff caps what the owner sends to transfer.ReceiverId while
+// it is a payee added less than policy.Period ago, however the receiver was
+// named. Transfers and authorized holds to it since the payee was added count
+// towards the cap. The caller must hold the locks that serialize transfers of
+// the owner.
+func checkPayeeCoolingOff(ctx context.Context, tx DBTX, owner string, transfer model.Transfer, policy model.PayeeCoolingOff) error {
+	if owner == model.SystemOwner || policy.Period <= 0 {
+		return nil
+	}
+
+	query := `SELECT p.id, p.created_at, (
+		SELECT COALESCE(SUM(amount), 0) FROM (
+			SELECT amount FROM transfers
+			WHERE receiver_id = p.account_id AND created_at >= p.created_at AND status <> 'rejected'
+			AND sender_id IN (SELECT id FROM accounts WHERE owner = p.owner)
+			UNION ALL
+			SELECT amount FROM holds
+			WHERE receiver_id = p.account_id AND created_at >= p.created_at AND status = 'authorized'
+			AND account_id IN (SELECT id FROM accounts WHERE owner = p.owner)
+		) sent)
+	FROM payees p
+	WHERE p.owner = $1 AND p.account_id = $2 AND p.created_at > now() - make_interval(secs => $3)
+	ORDER BY p.created_at DESC
+	LIMIT 1`
+
+	var (
+		payeeId   string
+		createdAt time.Time
+		used      int64
+	)
+	err := tx.QueryRowContext(ctx, query, owner, transfer.ReceiverId, policy.Period.Seconds()).Scan(&payeeId, &createdAt, &used)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if used+transfer.Amount <= policy.MaxAmount {
+		return nil
+	}
+	return &model.PayeeCoolingOffError{
+		PayeeId:     payeeId,
+		Max:         policy.MaxAmount,
+		Used:        used,
+		Requested:   transfer.Amount,
+		AvailableAt: createdAt.Add(policy.Period),
+	}
+}
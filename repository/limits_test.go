@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/terajari/bank-api/model"
+)
+
+func TestCheckTransferLimits(t *testing.T) {
+	now := time.Date(2023, 10, 31, 9, 30, 0, 0, time.UTC)
+	limitColumns := []string{"id", "scope", "subject", "currency", "max_single", "max_daily", "max_monthly", "max_hourly_count", "created_at", "updated_at"}
+	transfer := model.Transfer{ID: "tr1", SenderId: "acc1", ReceiverId: "acc2", Amount: 500}
+
+	test := []struct {
+		name      string
+		actual    func(sqlmock.Sqlmock)
+		wantLimit string
+		wantReset time.Time
+	}{
+		{
+			name: "no limits",
+			actual: func(s sqlmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta("FROM transfer_limits")).
+					WithArgs("fulan", "acc1", "IDR").
+					WillReturnRows(s.NewRows(limitColumns))
+			},
+		},
+		{
+			name: "single transfer over max",
+			actual: func(s sqlmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta("FROM transfer_limits")).
+					WithArgs("fulan", "acc1", "IDR").
+					WillReturnRows(s.NewRows(limitColumns).
+						AddRow("l1", "account", "acc1", "IDR", 100, nil, nil, nil, now, now))
+				s.ExpectQuery(regexp.QuoteMeta("SELECT now()")).
+					WillReturnRows(s.NewRows([]string{"now"}).AddRow(now))
+			},
+			wantLimit: model.LimitMaxSingle,
+		},
+		{
+			name: "daily total over max",
+			actual: func(s sqlmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta("FROM transfer_limits")).
+					WithArgs("fulan", "acc1", "IDR").
+					WillReturnRows(s.NewRows(limitColumns).
+						AddRow("l1", "user", "*", "IDR", nil, 1000, 5000, nil, now, now))
+				s.ExpectQuery(regexp.QuoteMeta("SELECT now()")).
+					WillReturnRows(s.NewRows([]string{"now"}).AddRow(now))
+				s.ExpectQuery(regexp.QuoteMeta("FROM transfers")).
+					WithArgs("fulan", time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 10, 31, 0, 0, 0, 0, time.UTC), time.Date(2023, 10, 31, 9, 0, 0, 0, time.UTC), "IDR").
+					WillReturnRows(s.NewRows([]string{"daily", "monthly", "hourly"}).AddRow(600, 600, 1))
+			},
+			wantLimit: model.LimitMaxDaily,
+			wantReset: time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "authorized holds count towards the daily total",
+			actual: func(s sqlmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta("FROM transfer_limits")).
+					WithArgs("fulan", "acc1", "IDR").
+					WillReturnRows(s.NewRows(limitColumns).
+						AddRow("l1", "account", "acc1", "IDR", nil, 1000, nil, nil, now, now))
+				s.ExpectQuery(regexp.QuoteMeta("SELECT now()")).
+					WillReturnRows(s.NewRows([]string{"now"}).AddRow(now))
+				s.ExpectQuery(`FROM transfers WHERE sender_id = \$1 .* UNION ALL SELECT amount, created_at FROM holds WHERE account_id = \$1 AND created_at >= \$2 AND status = 'authorized'`).
+					WithArgs("acc1", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnRows(s.NewRows([]string{"daily", "monthly", "hourly"}).AddRow(800, 800, 2))
+			},
+			wantLimit: model.LimitMaxDaily,
+		},
+		{
+			name: "hourly count reached",
+			actual: func(s sqlmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta("FROM transfer_limits")).
+					WithArgs("fulan", "acc1", "IDR").
+					WillReturnRows(s.NewRows(limitColumns).
+						AddRow("l1", "account", "acc1", "IDR", nil, 10000, nil, 3, now, now))
+				s.ExpectQuery(regexp.QuoteMeta("SELECT now()")).
+					WillReturnRows(s.NewRows([]string{"now"}).AddRow(now))
+				s.ExpectQuery(regexp.QuoteMeta("FROM transfers")).
+					WithArgs("acc1", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnRows(s.NewRows([]string{"daily", "monthly", "hourly"}).AddRow(900, 900, 3))
+			},
+			wantLimit: model.LimitMaxHourlyCount,
+			wantReset: time.Date(2023, 10, 31, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "override raises the default",
+			actual: func(s sqlmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta("FROM transfer_limits")).
+					WithArgs("fulan", "acc1", "IDR").
+					WillReturnRows(s.NewRows(limitColumns).
+						AddRow("l2", "user", "fulan", "IDR", 1000, 5000, nil, nil, now, now).
+						AddRow("l1", "user", "*", "IDR", 100, 1000, nil, nil, now, now))
+				s.ExpectQuery(regexp.QuoteMeta("SELECT now()")).
+					WillReturnRows(s.NewRows([]string{"now"}).AddRow(now))
+				s.ExpectQuery(regexp.QuoteMeta("FROM transfers")).
+					WithArgs("fulan", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "IDR").
+					WillReturnRows(s.NewRows([]string{"daily", "monthly", "hourly"}).AddRow(600, 600, 1))
+			},
+		},
+		{
+			name: "override inherits unset limits from the default",
+			actual: func(s sqlmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta("FROM transfer_limits")).
+					WithArgs("fulan", "acc1", "IDR").
+					WillReturnRows(s.NewRows(limitColumns).
+						AddRow("l2", "account", "acc1", "IDR", 1000, nil, nil, nil, now, now).
+						AddRow("l1", "account", "*", "IDR", 100, 1000, nil, nil, now, now))
+				s.ExpectQuery(regexp.QuoteMeta("SELECT now()")).
+					WillReturnRows(s.NewRows([]string{"now"}).AddRow(now))
+				s.ExpectQuery(regexp.QuoteMeta("FROM transfers")).
+					WithArgs("acc1", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnRows(s.NewRows([]string{"daily", "monthly", "hourly"}).AddRow(600, 600, 1))
+			},
+			wantLimit: model.LimitMaxDaily,
+		},
+		{
+			name: "within limits",
+			actual: func(s sqlmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta("FROM transfer_limits")).
+					WithArgs("fulan", "acc1", "IDR").
+					WillReturnRows(s.NewRows(limitColumns).
+						AddRow("l1", "account", "acc1", "IDR", 1000, 10000, 100000, 10, now, now))
+				s.ExpectQuery(regexp.QuoteMeta("SELECT now()")).
+					WillReturnRows(s.NewRows([]string{"now"}).AddRow(now))
+				s.ExpectQuery(regexp.QuoteMeta("FROM transfers")).
+					WithArgs("acc1", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnRows(s.NewRows([]string{"daily", "monthly", "hourly"}).AddRow(900, 900, 3))
+			},
+		},
+	}
+
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mock.ExpectBegin()
+			tt.actual(mock)
+
+			tx, err := sqlx.NewDb(db, "sqlmock").BeginTxx(context.TODO(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = checkTransferLimits(context.TODO(), tx, "fulan", "IDR", transfer)
+
+			var limitErr *model.LimitExceededError
+			if tt.wantLimit == "" {
+				if err != nil {
+					t.Errorf("checkTransferLimits() error = %v, want nil", err)
+				}
+			} else if !errors.As(err, &limitErr) {
+				t.Errorf("checkTransferLimits() error = %v, want limit %s", err, tt.wantLimit)
+			} else {
+				if limitErr.Limit != tt.wantLimit {
+					t.Errorf("checkTransferLimits() limit = %s, want %s", limitErr.Limit, tt.wantLimit)
+				}
+				if !tt.wantReset.IsZero() && (limitErr.ResetsAt == nil || !limitErr.ResetsAt.Equal(tt.wantReset)) {
+					t.Errorf("checkTransferLimits() resets at = %v, want %v", limitErr.ResetsAt, tt.wantReset)
+				}
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestCheckPayeeCoolingOff(t *testing.T) {
+	added := time.Date(2023, 10, 31, 9, 30, 0, 0, time.UTC)
+	policy := model.PayeeCoolingOff{Period: 24 * time.Hour, MaxAmount: 1000}
+	transfer := model.Transfer{ID: "tr1", SenderId: "acc1", ReceiverId: "acc2", Amount: 500}
+
+	test := []struct {
+		name     string
+		owner    string
+		policy   model.PayeeCoolingOff
+		actual   func(sqlmock.Sqlmock)
+		wantUsed int64
+		wantErr  bool
+	}{
+		{
+			name:   "receiver is not a recent payee",
+			owner:  "fulan",
+			policy: policy,
+			actual: func(s sqlmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta("FROM payees p WHERE p.owner = $1 AND p.account_id = $2")).
+					WithArgs("fulan", "acc2", float64(86400)).
+					WillReturnRows(s.NewRows([]string{"id", "created_at", "sent"}))
+			},
+		},
+		{
+			name:   "within the cap",
+			owner:  "fulan",
+			policy: policy,
+			actual: func(s sqlmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta("FROM payees p WHERE p.owner = $1 AND p.account_id = $2")).
+					WithArgs("fulan", "acc2", float64(86400)).
+					WillReturnRows(s.NewRows([]string{"id", "created_at", "sent"}).AddRow("payee1", added, 500))
+			},
+		},
+		{
+			name:   "sent during the window over the cap",
+			owner:  "fulan",
+			policy: policy,
+			actual: func(s sqlmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta("FROM payees p WHERE p.owner = $1 AND p.account_id = $2")).
+					WithArgs("fulan", "acc2", float64(86400)).
+					WillReturnRows(s.NewRows([]string{"id", "created_at", "sent"}).AddRow("payee1", added, 600))
+			},
+			wantUsed: 600,
+			wantErr:  true,
+		},
+		{
+			name:   "disabled",
+			owner:  "fulan",
+			actual: func(s sqlmock.Sqlmock) {},
+		},
+		{
+			name:   "system owner",
+			owner:  model.SystemOwner,
+			policy: policy,
+			actual: func(s sqlmock.Sqlmock) {},
+		},
+	}
+
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mock.ExpectBegin()
+			tt.actual(mock)
+
+			tx, err := sqlx.NewDb(db, "sqlmock").BeginTxx(context.TODO(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = checkPayeeCoolingOff(context.TODO(), tx, tt.owner, transfer, tt.policy)
+
+			var coolingOffErr *model.PayeeCoolingOffError
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("checkPayeeCoolingOff() error = %v, want nil", err)
+				}
+			} else if !errors.As(err, &coolingOffErr) {
+				t.Errorf("checkPayeeCoolingOff() error = %v, want *model.PayeeCoolingOffError", err)
+			} else {
+				if coolingOffErr.PayeeId != "payee1" || coolingOffErr.Used != tt.wantUsed || coolingOffErr.Requested != transfer.Amount {
+					t.Errorf("checkPayeeCoolingOff() error = %+v", coolingOffErr)
+				}
+				if want := added.Add(24 * time.Hour); !coolingOffErr.AvailableAt.Equal(want) {
+					t.Errorf("checkPayeeCoolingOff() available at = %v, want %v", coolingOffErr.AvailableAt, want)
+				}
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
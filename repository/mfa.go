@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/terajari/bank-api/model"
+)
+
+type MfaRepository interface {
+	Upsert(ctx context.Context, mfa model.UserMfa) (model.UserMfa, error)
+	Get(ctx context.Context, username string) (model.UserMfa, error)
+	Enable(ctx context.Context, username string, step int64, codes []model.RecoveryCode) error
+	UseStep(ctx context.Context, username string, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, username, codeHash string) (bool, error)
+	CountAttempt(ctx context.Context, username string, maxAttempts int, lockout time.Duration) (bool, error)
+	ResetAttempts(ctx context.Context, username string) error
+	UseChallenge(ctx context.Context, id, username string, expiresAt time.Time) (bool, error)
+}
+
+type mfaRepository struct {
+	db DBTX
+}
+
+func NewMfaRepository(db DBTX) MfaRepository {
+	return &mfaRepository{db: db}
+}
+
+func (m *mfaRepository) Upsert(ctx context.Context, mfa model.UserMfa) (model.UserMfa, error) {
+	query := "INSERT INTO user_mfa (username, secret) VALUES ($1, $2) ON CONFLICT (username) DO UPDATE SET secret = EXCLUDED.secret, enabled = false, last_used_step = 0, confirmed_at = NULL, failed_attempts = 0, locked_until = NULL RETURNING username, secret, enabled, last_used_step, failed_attempts, locked_until, confirmed_at, created_at"
+	row := m.db.QueryRowContext(ctx, query, mfa.Username, mfa.Secret)
+	var um model.UserMfa
+	if err := row.Scan(&um.Username, &um.Secret, &um.Enabled, &um.LastUsedStep, &um.FailedAttempts, &um.LockedUntil, &um.ConfirmedAt, &um.CreatedAt); err != nil {
+		return model.UserMfa{}, err
+	}
+	return um, nil
+}
+
+func (m *mfaRepository) Get(ctx context.Context, username string) (model.UserMfa, error) {
+	query := "SELECT username, secret, enabled, last_used_step, failed_attempts, locked_until, confirmed_at, created_at FROM user_mfa WHERE username = $1 LIMIT 1"
+	row := m.db.QueryRowContext(ctx, query, username)
+	var um model.UserMfa
+	if err := row.Scan(&um.Username, &um.Secret, &um.Enabled, &um.LastUsedStep, &um.FailedAttempts, &um.LockedUntil, &um.ConfirmedAt, &um.CreatedAt); err != nil {
+		return model.UserMfa{}, err
+	}
+	return um, nil
+}
+
+// Enable marks the enrollment as confirmed and replaces the user's recovery codes in one transaction.
+func (m *mfaRepository) Enable(ctx context.Context, username string, step int64, codes []model.RecoveryCode) error {
+	tx, err := beginTx(ctx, m.db, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	queryEnable := "UPDATE user_mfa SET enabled = true, last_used_step = $2, confirmed_at = now() WHERE username = $1 AND enabled = false"
+	res, err := tx.ExecContext(ctx, queryEnable, username, step)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM mfa_recovery_codes WHERE username = $1", username); err != nil {
+		return err
+	}
+
+	queryCode := "INSERT INTO mfa_recovery_codes (id, username, code_hash) VALUES ($1, $2, $3)"
+	for _, code := range codes {
+		if _, err := tx.ExecContext(ctx, queryCode, code.ID, username, code.CodeHash); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UseStep records step as the last accepted TOTP step. It reports false when
+// the step is not newer than the last one, so a code can only be used once.
+func (m *mfaRepository) UseStep(ctx context.Context, username string, step int64) (bool, error) {
+	query := "UPDATE user_mfa SET last_used_step = $2 WHERE username = $1 AND enabled = true AND last_used_step < $2"
+	res, err := m.db.ExecContext(ctx, query, username, step)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func (m *mfaRepository) UseRecoveryCode(ctx context.Context, username, codeHash string) (bool, error) {
+	query := "UPDATE mfa_recovery_codes SET used_at = now() WHERE username = $1 AND code_hash = $2 AND used_at IS NULL"
+	res, err := m.db.ExecContext(ctx, query, username, codeHash)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// CountAttempt counts a verification attempt before the code is checked, so
+// concurrent guesses cannot slip past the limit. The user is locked out for
+// lockout once maxAttempts attempts have been counted without a reset. It
+// reports false while the user is locked out.
+func (m *mfaRepository) CountAttempt(ctx context.Context, username string, maxAttempts int, lockout time.Duration) (bool, error) {
+	query := `UPDATE user_mfa SET
+		failed_attempts = CASE WHEN locked_until IS NULL THEN failed_attempts + 1 ELSE 1 END,
+		locked_until = CASE WHEN (CASE WHEN locked_until IS NULL THEN failed_attempts + 1 ELSE 1 END) >= $2 THEN now() + make_interval(secs => $3) END
+		WHERE username = $1 AND enabled = true AND (locked_until IS NULL OR locked_until <= now())`
+	res, err := m.db.ExecContext(ctx, query, username, maxAttempts, lockout.Seconds())
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// ResetAttempts clears the attempt count after a code was accepted.
+func (m *mfaRepository) ResetAttempts(ctx context.Context, username string) error {
+	query := "UPDATE user_mfa SET failed_attempts = 0, locked_until = NULL WHERE username = $1"
+	_, err := m.db.ExecContext(ctx, query, username)
+	return err
+}
+
+// UseChallenge records the login challenge id as used. It reports false when
+// the challenge was already used. Expired challenges of the user are pruned,
+// their tokens are rejected before they get here.
+func (m *mfaRepository) UseChallenge(ctx context.Context, id, username string, expiresAt time.Time) (bool, error) {
+	if _, err := m.db.ExecContext(ctx, "DELETE FROM mfa_challenges WHERE username = $1 AND expires_at < now()", username); err != nil {
+		return false, err
+	}
+
+	query := "INSERT INTO mfa_challenges (id, username, expires_at) VALUES ($1, $2, $3) ON CONFLICT (id) DO NOTHING"
+	res, err := m.db.ExecContext(ctx, query, id, username, expiresAt)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/terajari/bank-api/model"
+)
+
+type PayeesRepository interface {
+	Create(ctx context.Context, payee model.Payee) (model.Payee, error)
+	Get(ctx context.Context, id, owner string) (model.Payee, error)
+	List(ctx context.Context, owner string, limit, offset int) ([]model.Payee, error)
+	UpdateNickname(ctx context.Context, id, owner, nickname string) (model.Payee, error)
+	Delete(ctx context.Context, id, owner string) error
+}
+
+type payeesRepository struct {
+	db DBTX
+}
+
+func NewPayeesRepository(db DBTX) PayeesRepository {
+	return &payeesRepository{db: db}
+}
+
+const payeeColumns = "id, owner, nickname, account_id, currency, created_at, updated_at"
+
+func scanPayee(row rowScanner) (model.Payee, error) {
+	var p model.Payee
+	if err := row.Scan(&p.ID, &p.Owner, &p.Nickname, &p.AccountId, &p.Currency, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return model.Payee{}, err
+	}
+	return p, nil
+}
+
+func (r *payeesRepository) Create(ctx context.Context, payee model.Payee) (model.Payee, error) {
+	query := "INSERT INTO payees (id, owner, nickname, account_id, currency) VALUES ($1, $2, $3, $4, $5) RETURNING " + payeeColumns
+	row := r.db.QueryRowContext(ctx, query, payee.ID, payee.Owner, payee.Nickname, payee.AccountId, payee.Currency)
+	return scanPayee(row)
+}
+
+// Get returns the payee only when it belongs to owner; other users' payees
+// are reported as sql.ErrNoRows.
+func (r *payeesRepository) Get(ctx context.Context, id, owner string) (model.Payee, error) {
+	query := "SELECT " + payeeColumns + " FROM payees WHERE id = $1 AND owner = $2 LIMIT 1"
+	row := r.db.QueryRowContext(ctx, query, id, owner)
+	return scanPayee(row)
+}
+
+func (r *payeesRepository) List(ctx context.Context, owner string, limit, offset int) ([]model.Payee, error) {
+	query := "SELECT " + payeeColumns + " FROM payees WHERE owner = $1 ORDER BY nickname LIMIT $2 OFFSET $3"
+	rows, err := r.db.QueryContext(ctx, query, owner, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payees := []model.Payee{}
+	for rows.Next() {
+		p, err := scanPayee(rows)
+		if err != nil {
+			return nil, err
+		}
+		payees = append(payees, p)
+	}
+	return payees, rows.Err()
+}
+
+func (r *payeesRepository) UpdateNickname(ctx context.Context, id, owner, nickname string) (model.Payee, error) {
+	query := "UPDATE payees SET nickname = $3, updated_at = now() WHERE id = $1 AND owner = $2 RETURNING " + payeeColumns
+	row := r.db.QueryRowContext(ctx, query, id, owner, nickname)
+	return scanPayee(row)
+}
+
+func (r *payeesRepository) Delete(ctx context.Context, id, owner string) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM payees WHERE id = $1 AND owner = $2", id, owner)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package repository
+
+// Repositories gives access to every repository, all bound to the same
+// connection or transaction.
+type Repositories interface {
+	AccountsRepo() AccountsRepository
+	AccountTypesRepo() AccountTypesRepository
+	EntryRepo() EntryRepository
+	TransferRepo() TransferRepository
+	UsersRepo() UsersRepository
+	SessionsRepo() SessionsRepository
+	MfaRepo() MfaRepository
+	InterestRepo() InterestRepository
+	FeeRulesRepo() FeeRulesRepository
+	HoldsRepo() HoldsRepository
+	BatchJobsRepo() BatchJobsRepository
+	PayeesRepo() PayeesRepository
+	SchemaRepo() SchemaRepository
+	LedgerRepo() LedgerRepository
+}
@@ -0,0 +1,27 @@
+package repository
+
+import "context"
+
+type SchemaRepository interface {
+	Version(ctx context.Context) (int64, bool, error)
+}
+
+type schemaRepository struct {
+	db DBTX
+}
+
+func NewSchemaRepository(db DBTX) SchemaRepository {
+	return &schemaRepository{db: db}
+}
+
+// Version returns the migration version recorded by golang-migrate and
+// whether the last migration failed halfway. It returns sql.ErrNoRows when
+// no migration has run.
+func (s *schemaRepository) Version(ctx context.Context) (int64, bool, error) {
+	var version int64
+	var dirty bool
+	if err := s.db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty); err != nil {
+		return 0, false, err
+	}
+	return version, dirty, nil
+}
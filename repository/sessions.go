@@ -2,9 +2,9 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/terajari/bank-api/model"
 )
 
@@ -13,36 +13,38 @@ type SessionsRepository interface {
 	Get(ctx context.Context, id uuid.UUID) (model.Sessions, error)
 	Update(ctx context.Context, id uuid.UUID, isBlocked bool) error
 	Last(ctx context.Context) (model.Sessions, error)
+	Renew(ctx context.Context, id uuid.UUID, oldRefreshToken, refreshToken string, expiresAt time.Time) (model.Sessions, error)
 }
 
 type sessionsRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
-func NewSessionsRepository(db *sqlx.DB) SessionsRepository {
+func NewSessionsRepository(db DBTX) SessionsRepository {
 	return &sessionsRepository{db}
 }
 
-func (s *sessionsRepository) Create(ctx context.Context, sessions model.Sessions) (model.Sessions, error) {
-	query := "INSERT INTO sessions (id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at"
-	row := s.db.QueryRowContext(ctx, query, sessions.Id, sessions.Username, sessions.RefreshToken, sessions.UserAgent, sessions.ClientIp, sessions.IsBlocked, sessions.ExpiresAt)
+const sessionColumns = "id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, renewal_count, last_renewed_at"
+
+func scanSession(row rowScanner) (model.Sessions, error) {
 	var ss model.Sessions
-	err := row.Scan(&ss.Id, &ss.Username, &ss.RefreshToken, &ss.UserAgent, &ss.ClientIp, &ss.IsBlocked, &ss.ExpiresAt, &ss.CreatedAt)
+	err := row.Scan(&ss.Id, &ss.Username, &ss.RefreshToken, &ss.UserAgent, &ss.ClientIp, &ss.IsBlocked, &ss.ExpiresAt, &ss.CreatedAt, &ss.RenewalCount, &ss.LastRenewedAt)
 	if err != nil {
 		return model.Sessions{}, err
 	}
 	return ss, nil
 }
 
+func (s *sessionsRepository) Create(ctx context.Context, sessions model.Sessions) (model.Sessions, error) {
+	query := "INSERT INTO sessions (id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING " + sessionColumns
+	row := s.db.QueryRowContext(ctx, query, sessions.Id, sessions.Username, sessions.RefreshToken, sessions.UserAgent, sessions.ClientIp, sessions.IsBlocked, sessions.ExpiresAt)
+	return scanSession(row)
+}
+
 func (s *sessionsRepository) Get(ctx context.Context, id uuid.UUID) (model.Sessions, error) {
-	query := "SELECT id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at FROM sessions WHERE id = $1 LIMIT 1"
+	query := "SELECT " + sessionColumns + " FROM sessions WHERE id = $1 LIMIT 1"
 	row := s.db.QueryRowContext(ctx, query, id)
-	var ss model.Sessions
-	err := row.Scan(&ss.Id, &ss.Username, &ss.RefreshToken, &ss.UserAgent, &ss.ClientIp, &ss.IsBlocked, &ss.ExpiresAt, &ss.CreatedAt)
-	if err != nil {
-		return model.Sessions{}, err
-	}
-	return ss, nil
+	return scanSession(row)
 }
 
 func (s *sessionsRepository) Update(ctx context.Context, id uuid.UUID, isBlocked bool) error {
@@ -55,12 +57,17 @@ func (s *sessionsRepository) Update(ctx context.Context, id uuid.UUID, isBlocked
 }
 
 func (s *sessionsRepository) Last(ctx context.Context) (model.Sessions, error) {
-	query := "SELECT id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at FROM sessions ORDER BY created_at DESC LIMIT 1"
+	query := "SELECT " + sessionColumns + " FROM sessions ORDER BY created_at DESC LIMIT 1"
 	row := s.db.QueryRowContext(ctx, query)
-	var ss model.Sessions
-	err := row.Scan(&ss.Id, &ss.Username, &ss.RefreshToken, &ss.UserAgent, &ss.ClientIp, &ss.IsBlocked, &ss.ExpiresAt, &ss.CreatedAt)
-	if err != nil {
-		return model.Sessions{}, err
-	}
-	return ss, nil
+	return scanSession(row)
+}
+
+// Renew records a renewal and stores the session's refresh token and expiry.
+// It only matches an unblocked session still holding oldRefreshToken, so of
+// two concurrent renewals with the same token only one succeeds; the other
+// gets sql.ErrNoRows.
+func (s *sessionsRepository) Renew(ctx context.Context, id uuid.UUID, oldRefreshToken, refreshToken string, expiresAt time.Time) (model.Sessions, error) {
+	query := "UPDATE sessions SET refresh_token = $3, expires_at = $4, renewal_count = renewal_count + 1, last_renewed_at = now() WHERE id = $1 AND refresh_token = $2 AND NOT is_blocked RETURNING " + sessionColumns
+	row := s.db.QueryRowContext(ctx, query, id, oldRefreshToken, refreshToken, expiresAt)
+	return scanSession(row)
 }
@@ -3,33 +3,48 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"time"
 
-	"github.com/jmoiron/sqlx"
 	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/logging"
 	"github.com/terajari/bank-api/model"
+	"github.com/terajari/bank-api/tracing"
 	"github.com/terajari/bank-api/utils"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type TransferRepository interface {
 	Create(ctx context.Context, transfer model.Transfer) (model.Transfer, error)
 	Get(ctx context.Context, id string) (model.Transfer, error)
-	List(ctx context.Context, transfer model.Transfer, limit, offset int) ([]model.Transfer, error)
-	TransferTx(ctx context.Context, transfer model.Transfer) (dto.MakeTransferResponse, error)
+	List(ctx context.Context, filter model.TransfersFilter, limit, offset int) ([]model.Transfer, error)
+	ListByStatus(ctx context.Context, status string, limit, offset int) ([]model.Transfer, error)
+	RecentActivity(ctx context.Context, senderId string, since time.Time) (int64, int64, error)
+	HasPaid(ctx context.Context, owner, receiverId string) (bool, error)
+	TransferTx(ctx context.Context, arg TransferTxParams) (dto.MakeTransferResponse, error)
+	TransferBatchTx(ctx context.Context, args []TransferTxParams) ([]dto.MakeTransferResponse, error)
+	ApproveTx(ctx context.Context, id, reviewer string, fees []model.TransferFee) (dto.MakeTransferResponse, error)
+	Reject(ctx context.Context, id, reviewer string) (model.Transfer, error)
+}
+
+// TransferTxParams describes a transfer and the fees charged to its sender.
+// A transfer with status pending_review is only recorded, its entries are
+// posted by ApproveTx.
+// Fee IDs, rule, name, amount and revenue account must be set by the caller.
+// CoolingOff is checked against payees of the sender's owner.
+type TransferTxParams struct {
+	Transfer   model.Transfer
+	Fees       []model.TransferFee
+	CoolingOff model.PayeeCoolingOff
 }
 
 type transferRepository struct {
-	accRepo AccountsRepository
-	entRepo EntryRepository
-	db      *sqlx.DB
+	db DBTX
 }
 
-func NewTransferRepository(db *sqlx.DB) TransferRepository {
-	return &transferRepository{
-		accRepo: NewAccountsRepository(db),
-		entRepo: NewEntryRepository(db),
-		db:      db,
-	}
+func NewTransferRepository(db DBTX) TransferRepository {
+	return &transferRepository{db: db}
 }
 
 func (t *transferRepository) Create(ctx context.Context, transfer model.Transfer) (model.Transfer, error) {
@@ -43,99 +58,402 @@ func (t *transferRepository) Create(ctx context.Context, transfer model.Transfer
 }
 
 func (t *transferRepository) Get(ctx context.Context, id string) (model.Transfer, error) {
-	query := "SELECT id, sender_id, receiver_id, amount, created_at FROM transfer WHERE id = $1 LIMIT 1"
-	row := t.db.QueryRowContext(ctx, query, id)
-	var tr model.Transfer
-	if err := row.Scan(&tr.ID, &tr.SenderId, &tr.ReceiverId, &tr.Amount, &tr.CreatedAt); err != nil {
-		return model.Transfer{}, err
+	query := "SELECT " + transferColumns + " FROM transfers WHERE id = $1 LIMIT 1"
+	return scanTransfer(t.db.QueryRowContext(ctx, query, id))
+}
+
+// List returns the account's transfers, newest first.
+func (tr *transferRepository) List(ctx context.Context, filter model.TransfersFilter, limit, offset int) ([]model.Transfer, error) {
+	query := "SELECT " + transferColumns + " FROM transfers WHERE (sender_id = $1 OR receiver_id = $1) AND ($2 = '' OR reference = $2) ORDER BY created_at DESC, id LIMIT $3 OFFSET $4"
+	rows, err := tr.db.QueryContext(ctx, query, filter.AccountId, filter.Reference, limit, offset)
+	if err != nil {
+		return []model.Transfer{}, err
 	}
-	return tr, nil
+	defer rows.Close()
+	transfers := []model.Transfer{}
+	for rows.Next() {
+		tr, err := scanTransfer(rows)
+		if err != nil {
+			return []model.Transfer{}, err
+		}
+		transfers = append(transfers, tr)
+	}
+	return transfers, rows.Err()
 }
 
-func (tr *transferRepository) List(ctx context.Context, transfer model.Transfer, limit, offset int) ([]model.Transfer, error) {
-	query := "SELECT  id, sender_id, receiver_id, amount, created_at FROM transfer WHERE sender_id = $1 OR receiver_id = $2 ORDER BY id LIMIT $3 OFFSET $4"
-	rows, err := tr.db.QueryContext(ctx, query, transfer.SenderId, transfer.ReceiverId, limit, offset)
+func (t *transferRepository) ListByStatus(ctx context.Context, status string, limit, offset int) ([]model.Transfer, error) {
+	query := "SELECT " + transferColumns + " FROM transfers WHERE status = $1 ORDER BY created_at, id LIMIT $2 OFFSET $3"
+	rows, err := t.db.QueryContext(ctx, query, status, limit, offset)
 	if err != nil {
 		return []model.Transfer{}, err
 	}
 	defer rows.Close()
-	var transfers []model.Transfer
+	transfers := []model.Transfer{}
 	for rows.Next() {
-		var tr model.Transfer
-		if err := rows.Scan(&tr.ID, &tr.SenderId, &tr.ReceiverId, &tr.Amount, &tr.CreatedAt); err != nil {
+		tr, err := scanTransfer(rows)
+		if err != nil {
 			return []model.Transfer{}, err
 		}
 		transfers = append(transfers, tr)
 	}
-	return transfers, nil
+	return transfers, rows.Err()
 }
 
-func (t *transferRepository) TransferTx(ctx context.Context, transfer model.Transfer) (dto.MakeTransferResponse, error) {
-	var response dto.MakeTransferResponse
-	tx, err := t.db.BeginTxx(ctx, &sql.TxOptions{})
+// RecentActivity counts and sums the transfers sent from an account since the
+// given time. Rejected transfers are ignored.
+func (t *transferRepository) RecentActivity(ctx context.Context, senderId string, since time.Time) (int64, int64, error) {
+	query := "SELECT COUNT(*), COALESCE(SUM(amount), 0) FROM transfers WHERE sender_id = $1 AND created_at >= $2 AND status <> 'rejected'"
+	var count, amount int64
+	if err := t.db.QueryRowContext(ctx, query, senderId, since).Scan(&count, &amount); err != nil {
+		return 0, 0, err
+	}
+	return count, amount, nil
+}
+
+// HasPaid reports whether any account of owner has completed a transfer to receiverId.
+func (t *transferRepository) HasPaid(ctx context.Context, owner, receiverId string) (bool, error) {
+	query := `SELECT EXISTS (
+		SELECT 1 FROM transfers t JOIN accounts a ON a.id = t.sender_id
+		WHERE a.owner = $1 AND t.receiver_id = $2 AND t.status = 'completed'
+	)`
+	var paid bool
+	if err := t.db.QueryRowContext(ctx, query, owner, receiverId).Scan(&paid); err != nil {
+		return false, err
+	}
+	return paid, nil
+}
+
+// TransferTx records the transfer and, unless it is held for review, posts
+// its entries, fees and balance changes in the same transaction.
+func (t *transferRepository) TransferTx(ctx context.Context, arg TransferTxParams) (response dto.MakeTransferResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TransferRepository.TransferTx", attribute.String("transfer.id", arg.Transfer.ID))
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := beginTx(ctx, t.db, &sql.TxOptions{})
 	if err != nil {
-		fmt.Println("error repo1")
+		logging.FromContext(ctx).Error("transfer: cannot begin transaction", "error", err)
 		return dto.MakeTransferResponse{}, err
 	}
 	defer tx.Rollback()
 
-	queryTranfer := "INSERT INTO transfers (id, sender_id, receiver_id, amount) VALUES ($1, $2, $3, $4) RETURNING id, sender_id, receiver_id, amount, created_at"
-	row := tx.QueryRowContext(ctx, queryTranfer, transfer.ID, transfer.SenderId, transfer.ReceiverId, transfer.Amount)
-	var tr model.Transfer
-	if err := row.Scan(&tr.ID, &tr.SenderId, &tr.ReceiverId, &tr.Amount, &tr.CreatedAt); err != nil {
-		fmt.Println("error repo2")
+	response, err = transferInTx(ctx, tx, arg)
+	if err != nil {
 		return dto.MakeTransferResponse{}, err
 	}
-	response.Transfer = tr
 
-	senderEntryId := utils.GenerateUUID()
-	querySenderEntry := "INSERT INTO entries (id, account_id, amount) VALUES ($1, $2, $3) RETURNING id, account_id, amount, created_at"
-	row = tx.QueryRowContext(ctx, querySenderEntry, senderEntryId, transfer.SenderId, -transfer.Amount)
-	var senderEnt model.Entries
-	if err := row.Scan(&senderEnt.ID, &senderEnt.AccountId, &senderEnt.Amount, &senderEnt.CreatedAt); err != nil {
-		fmt.Println("error repo3")
+	err = tx.Commit()
+	if err != nil {
+		logging.FromContext(ctx).Error("transfer: cannot commit", "transfer_id", arg.Transfer.ID, "error", err)
 		return dto.MakeTransferResponse{}, err
 	}
-	response.SenderEntry = senderEnt
 
-	receiverEntryId := utils.GenerateUUID()
-	queryReceiverEntry := "INSERT INTO entries (id, account_id, amount) VALUES ($1, $2, $3) RETURNING id, account_id, amount, created_at"
-	row = tx.QueryRowContext(ctx, queryReceiverEntry, receiverEntryId, transfer.ReceiverId, transfer.Amount)
-	var receiverEnt model.Entries
-	if err := row.Scan(&receiverEnt.ID, &receiverEnt.AccountId, &receiverEnt.Amount, &receiverEnt.CreatedAt); err != nil {
-		fmt.Println("error repo4")
+	return response, nil
+}
+
+// BatchItemError reports which transfer of a batch failed.
+type BatchItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// TransferBatchTx runs every transfer in one transaction: either all of them
+// are committed or none is. A failing transfer is reported as *BatchItemError.
+func (t *transferRepository) TransferBatchTx(ctx context.Context, args []TransferTxParams) ([]dto.MakeTransferResponse, error) {
+	tx, err := beginTx(ctx, t.db, &sql.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	responses := make([]dto.MakeTransferResponse, 0, len(args))
+	for i, arg := range args {
+		response, err := transferInTx(ctx, tx, arg)
+		if err != nil {
+			return nil, &BatchItemError{Index: i, Err: err}
+		}
+		responses = append(responses, response)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return responses, nil
+}
+
+func transferInTx(ctx context.Context, tx DBTX, arg TransferTxParams) (dto.MakeTransferResponse, error) {
+	var response dto.MakeTransferResponse
+	transfer := arg.Transfer
+	if transfer.Status == "" {
+		transfer.Status = model.TransferStatusCompleted
+	}
+
+	sender, err := lockSender(ctx, tx, transfer.SenderId)
+	if err != nil {
+		return dto.MakeTransferResponse{}, err
+	}
+	if err := checkAvailable(sender, transfer.Amount, arg.Fees); err != nil {
+		return dto.MakeTransferResponse{}, err
+	}
+
+	if err := checkTransferLimits(ctx, tx, sender.Owner, sender.Currency, transfer); err != nil {
+		return dto.MakeTransferResponse{}, err
+	}
+	if err := checkPayeeCoolingOff(ctx, tx, sender.Owner, transfer, arg.CoolingOff); err != nil {
 		return dto.MakeTransferResponse{}, err
 	}
-	response.ReceiverEntry = receiverEnt
 
-	querySenderUpdate := "SELECT id, owner, balance, currency FROM accounts WHERE id = $1 LIMIT 1 FOR NO KEY UPDATE"
-	_, err = tx.ExecContext(ctx, querySenderUpdate, transfer.SenderId)
+	tr, err := insertTransfer(ctx, tx, transfer)
 	if err != nil {
+		logging.FromContext(ctx).Error("transfer: cannot insert transfer", "transfer_id", transfer.ID, "error", err)
 		return dto.MakeTransferResponse{}, err
 	}
+	response.Transfer = tr
 
-	updateBalanceQuery := "UPDATE accounts SET balance = balance + $2 WHERE id = $1 RETURNING id, owner, balance, currency, created_at"
+	if tr.Status == model.TransferStatusCompleted {
+		if err := postTransfer(ctx, tx, tr, arg.Fees, &response); err != nil {
+			return dto.MakeTransferResponse{}, err
+		}
+	}
+	return response, nil
+}
 
-	row = tx.QueryRowContext(ctx, updateBalanceQuery, transfer.SenderId, -transfer.Amount)
-	var senderAcc model.Accounts
-	if err := row.Scan(&senderAcc.ID, &senderAcc.Owner, &senderAcc.Balance, &senderAcc.Currency, &senderAcc.CreatedAt); err != nil {
+// ApproveTx posts a transfer held for review and marks it completed. It
+// returns sql.ErrNoRows when the transfer is not pending review.
+func (t *transferRepository) ApproveTx(ctx context.Context, id, reviewer string, fees []model.TransferFee) (dto.MakeTransferResponse, error) {
+	var response dto.MakeTransferResponse
+	tx, err := beginTx(ctx, t.db, &sql.TxOptions{})
+	if err != nil {
 		return dto.MakeTransferResponse{}, err
 	}
-	response.Sender = senderAcc
+	defer tx.Rollback()
 
-	row = tx.QueryRowContext(ctx, updateBalanceQuery, transfer.ReceiverId, transfer.Amount)
-	var receiverAcc model.Accounts
-	if err := row.Scan(&receiverAcc.ID, &receiverAcc.Owner, &receiverAcc.Balance, &receiverAcc.Currency, &receiverAcc.CreatedAt); err != nil {
+	queryPending := "SELECT " + transferColumns + " FROM transfers WHERE id = $1 AND status = 'pending_review' FOR UPDATE"
+	transfer, err := scanTransfer(tx.QueryRowContext(ctx, queryPending, id))
+	if err != nil {
 		return dto.MakeTransferResponse{}, err
 	}
-	response.Receiver = receiverAcc
 
-	err = tx.Commit()
+	sender, err := lockSender(ctx, tx, transfer.SenderId)
 	if err != nil {
-		fmt.Println("error repo5")
+		return dto.MakeTransferResponse{}, err
+	}
+	if err := checkAvailable(sender, transfer.Amount, fees); err != nil {
+		return dto.MakeTransferResponse{}, err
+	}
+	// The receiver may have been frozen while the transfer waited for review.
+	if err := lockReceiver(ctx, tx, transfer.ReceiverId); err != nil {
 		return dto.MakeTransferResponse{}, err
 	}
 
+	if err := postTransfer(ctx, tx, transfer, fees, &response); err != nil {
+		return dto.MakeTransferResponse{}, err
+	}
+
+	queryReview := "UPDATE transfers SET status = 'completed', reviewed_by = $2, reviewed_at = now() WHERE id = $1 RETURNING " + transferColumns
+	response.Transfer, err = scanTransfer(tx.QueryRowContext(ctx, queryReview, id, reviewer))
+	if err != nil {
+		return dto.MakeTransferResponse{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.MakeTransferResponse{}, err
+	}
 	return response, nil
+}
+
+// Reject marks a transfer held for review as rejected. It returns
+// sql.ErrNoRows when the transfer is not pending review.
+func (t *transferRepository) Reject(ctx context.Context, id, reviewer string) (model.Transfer, error) {
+	query := "UPDATE transfers SET status = 'rejected', reviewed_by = $2, reviewed_at = now() WHERE id = $1 AND status = 'pending_review' RETURNING " + transferColumns
+	return scanTransfer(t.db.QueryRowContext(ctx, query, id, reviewer))
+}
+
+// lockSender locks the sender's owner before the sender account so that
+// transfers from all accounts of one user are serialized. It fails with
+// ErrAccountFrozen when the sender is frozen.
+func lockSender(ctx context.Context, tx DBTX, senderId string) (model.Accounts, error) {
+	var owner string
+	if err := tx.QueryRowContext(ctx, "SELECT owner FROM accounts WHERE id = $1 LIMIT 1", senderId).Scan(&owner); err != nil {
+		return model.Accounts{}, err
+	}
+	if _, err := tx.ExecContext(ctx, "SELECT username FROM users WHERE username = $1 FOR NO KEY UPDATE", owner); err != nil {
+		return model.Accounts{}, err
+	}
+	querySenderUpdate := "SELECT id, owner, balance, currency, held_balance, frozen_at FROM accounts WHERE id = $1 LIMIT 1 FOR NO KEY UPDATE"
+	var acc model.Accounts
+	if err := tx.QueryRowContext(ctx, querySenderUpdate, senderId).Scan(&acc.ID, &acc.Owner, &acc.Balance, &acc.Currency, &acc.HeldBalance, &acc.FrozenAt); err != nil {
+		return model.Accounts{}, err
+	}
+	// The usecase checks the account too; checking again under the lock
+	// stops the transfers that raced with a freeze.
+	if acc.Frozen() {
+		return model.Accounts{}, ErrAccountFrozen
+	}
+	return acc, nil
+}
+
+// checkAvailable refuses a transfer of amount and fees above the available
+// balance of the locked sender, so that it cannot spend funds reserved by a
+// hold or already sent by a concurrent transfer. The bank's own accounts may
+// go negative.
+func checkAvailable(sender model.Accounts, amount int64, fees []model.TransferFee) error {
+	if sender.Owner == model.SystemOwner {
+		return nil
+	}
+	total := amount
+	for _, fee := range fees {
+		total += fee.Amount
+	}
+	if sender.AvailableBalance() < total {
+		return ErrInsufficientFunds
+	}
+	return nil
+}
+
+// lockReceiver locks the account a transfer is about to credit. It fails with
+// ErrAccountFrozen when the receiver is frozen, which also stops the credits
+// that raced with a freeze.
+func lockReceiver(ctx context.Context, tx DBTX, receiverId string) error {
+	var frozenAt *time.Time
+	query := "SELECT frozen_at FROM accounts WHERE id = $1 LIMIT 1 FOR NO KEY UPDATE"
+	if err := tx.QueryRowContext(ctx, query, receiverId).Scan(&frozenAt); err != nil {
+		return err
+	}
+	if frozenAt != nil {
+		return ErrAccountFrozen
+	}
+	return nil
+}
+
+func insertTransfer(ctx context.Context, tx DBTX, transfer model.Transfer) (model.Transfer, error) {
+	reasons, err := json.Marshal(nonNilStrings(transfer.RiskReasons))
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	metadata, err := json.Marshal(nonNilMetadata(transfer.Metadata))
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	query := "INSERT INTO transfers (id, sender_id, receiver_id, amount, status, description, reference, metadata, risk_score, risk_reasons) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING " + transferColumns
+	return scanTransfer(tx.QueryRowContext(ctx, query, transfer.ID, transfer.SenderId, transfer.ReceiverId, transfer.Amount, transfer.Status, transfer.Description, transfer.Reference, metadata, transfer.RiskScore, reasons))
+}
+
+// postTransfer writes the entries of a transfer and its fees and applies
+// them to the balances.
+func postTransfer(ctx context.Context, tx DBTX, transfer model.Transfer, fees []model.TransferFee, response *dto.MakeTransferResponse) error {
+	accounts := NewAccountsRepository(tx)
+	entries := NewEntryRepository(tx)
+	createEntry := func(accountId string, amount int64) (model.Entries, error) {
+		return entries.Create(ctx, model.Entries{ID: utils.GenerateUUID(), AccountId: accountId, Amount: amount, TransferId: transfer.ID})
+	}
+
+	senderEnt, err := createEntry(transfer.SenderId, -transfer.Amount)
+	if err != nil {
+		logging.FromContext(ctx).Error("transfer: cannot create sender entry", "transfer_id", transfer.ID, "error", err)
+		return err
+	}
+	response.SenderEntry = senderEnt
 
+	receiverEnt, err := createEntry(transfer.ReceiverId, transfer.Amount)
+	if err != nil {
+		logging.FromContext(ctx).Error("transfer: cannot create receiver entry", "transfer_id", transfer.ID, "error", err)
+		return err
+	}
+	response.ReceiverEntry = receiverEnt
+
+	// Each fee is its own pair of entries: a debit on the sender and a credit
+	// on the bank's revenue account.
+	var totalFee int64
+	revenue := map[string]int64{}
+	queryFee := "INSERT INTO transfer_fees (id, transfer_id, rule_id, name, amount, revenue_account_id, sender_entry_id, revenue_entry_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING created_at"
+	for _, fee := range fees {
+		feeSenderEnt, err := createEntry(transfer.SenderId, -fee.Amount)
+		if err != nil {
+			return err
+		}
+		feeRevenueEnt, err := createEntry(fee.RevenueAccountID, fee.Amount)
+		if err != nil {
+			return err
+		}
+		fee.TransferID = transfer.ID
+		fee.SenderEntryID = feeSenderEnt.ID
+		fee.RevenueEntryID = feeRevenueEnt.ID
+		row := tx.QueryRowContext(ctx, queryFee, fee.ID, fee.TransferID, fee.RuleID, fee.Name, fee.Amount, fee.RevenueAccountID, fee.SenderEntryID, fee.RevenueEntryID)
+		if err := row.Scan(&fee.CreatedAt); err != nil {
+			return err
+		}
+		response.Fees = append(response.Fees, fee)
+		totalFee += fee.Amount
+		revenue[fee.RevenueAccountID] += fee.Amount
+	}
+	response.TotalFee = totalFee
+
+	senderAcc, err := accounts.AddBalance(ctx, transfer.SenderId, -(transfer.Amount + totalFee))
+	if err != nil {
+		return err
+	}
+	response.Sender = senderAcc
+
+	receiverAcc, err := accounts.AddBalance(ctx, transfer.ReceiverId, transfer.Amount)
+	if err != nil {
+		return err
+	}
+	response.Receiver = receiverAcc
+
+	for accountId, amount := range revenue {
+		if _, err := accounts.AddBalance(ctx, accountId, amount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const transferColumns = "id, sender_id, receiver_id, amount, status, description, reference, metadata, risk_score, risk_reasons, reviewed_by, reviewed_at, created_at"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTransfer(row rowScanner) (model.Transfer, error) {
+	var tr model.Transfer
+	var metadata, reasons []byte
+	if err := row.Scan(&tr.ID, &tr.SenderId, &tr.ReceiverId, &tr.Amount, &tr.Status, &tr.Description, &tr.Reference, &metadata, &tr.RiskScore, &reasons, &tr.ReviewedBy, &tr.ReviewedAt, &tr.CreatedAt); err != nil {
+		return model.Transfer{}, err
+	}
+	if len(metadata) > 0 {
+		if err := json.Unmarshal(metadata, &tr.Metadata); err != nil {
+			return model.Transfer{}, err
+		}
+		if len(tr.Metadata) == 0 {
+			tr.Metadata = nil
+		}
+	}
+	if len(reasons) > 0 {
+		if err := json.Unmarshal(reasons, &tr.RiskReasons); err != nil {
+			return model.Transfer{}, err
+		}
+	}
+	return tr, nil
+}
+
+func nonNilMetadata(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	return m
+}
+
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
 }
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/terajari/bank-api/model"
+)
+
+func TestListTransfers(t *testing.T) {
+	columns := []string{"id", "sender_id", "receiver_id", "amount", "status", "description", "reference", "metadata", "risk_score", "risk_reasons", "reviewed_by", "reviewed_at", "created_at"}
+
+	test := []struct {
+		name   string
+		filter model.TransfersFilter
+		actual func(sqlmock.Sqlmock)
+		want   []model.Transfer
+	}{
+		{
+			name:   "list by reference",
+			filter: model.TransfersFilter{AccountId: "acc1", Reference: "INV-1"},
+			actual: func(s sqlmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta("SELECT "+transferColumns+" FROM transfers WHERE (sender_id = $1 OR receiver_id = $1) AND ($2 = '' OR reference = $2) ORDER BY created_at DESC, id LIMIT $3 OFFSET $4")).
+					WithArgs("acc1", "INV-1", 20, 0).
+					WillReturnRows(s.NewRows(columns).
+						AddRow("tr1", "acc1", "acc2", 500, model.TransferStatusCompleted, "rent", "INV-1", []byte(`{"order":"42"}`), 0, []byte(`[]`), nil, nil, ""))
+			},
+			want: []model.Transfer{{ID: "tr1", SenderId: "acc1", ReceiverId: "acc2", Amount: 500, Status: model.TransferStatusCompleted, Description: "rent", Reference: "INV-1", Metadata: map[string]string{"order": "42"}, RiskReasons: []string{}}},
+		},
+		{
+			name:   "empty metadata is omitted",
+			filter: model.TransfersFilter{AccountId: "acc1"},
+			actual: func(s sqlmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta("SELECT "+transferColumns+" FROM transfers")).
+					WithArgs("acc1", "", 20, 0).
+					WillReturnRows(s.NewRows(columns).
+						AddRow("tr2", "acc2", "acc1", 700, model.TransferStatusCompleted, "", "", []byte(`{}`), 0, []byte(`[]`), nil, nil, ""))
+			},
+			want: []model.Transfer{{ID: "tr2", SenderId: "acc2", ReceiverId: "acc1", Amount: 700, Status: model.TransferStatusCompleted, RiskReasons: []string{}}},
+		},
+	}
+
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			tt.actual(mock)
+
+			r := NewTransferRepository(sqlx.NewDb(db, "sqlmock"))
+			got, err := r.List(context.TODO(), tt.filter, 20, 0)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("List() got = %+v, want %+v", got, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestTransferBatchTxRollsBackOnItemError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	transferRows := []string{"id", "sender_id", "receiver_id", "amount", "status", "description", "reference", "metadata", "risk_score", "risk_reasons", "reviewed_by", "reviewed_at", "created_at"}
+	expectLockSender := func(frozenAt *time.Time) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT owner FROM accounts WHERE id = $1")).
+			WithArgs("acc1").
+			WillReturnRows(mock.NewRows([]string{"owner"}).AddRow("fulan"))
+		mock.ExpectExec(regexp.QuoteMeta("SELECT username FROM users WHERE username = $1 FOR NO KEY UPDATE")).
+			WithArgs("fulan").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, owner, balance, currency, held_balance, frozen_at FROM accounts")).
+			WithArgs("acc1").
+			WillReturnRows(mock.NewRows([]string{"id", "owner", "balance", "currency", "held_balance", "frozen_at"}).
+				AddRow("acc1", "fulan", 5000, "IDR", 0, frozenAt))
+	}
+
+	mock.ExpectBegin()
+	// The first item is held for review, so it is only recorded.
+	expectLockSender(nil)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM transfer_limits")).
+		WithArgs("fulan", "acc1", "IDR").
+		WillReturnRows(mock.NewRows([]string{"id", "scope", "subject", "currency", "max_single", "max_daily", "max_monthly", "max_hourly_count", "created_at", "updated_at"}))
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO transfers")).
+		WillReturnRows(mock.NewRows(transferRows).
+			AddRow("tr1", "acc1", "acc2", 500, model.TransferStatusPendingReview, "", "", []byte(`{}`), 70, []byte(`[]`), nil, nil, ""))
+	// The account is frozen before the second item.
+	frozenAt := time.Now()
+	expectLockSender(&frozenAt)
+	mock.ExpectRollback()
+
+	_, err = NewTransferRepository(sqlx.NewDb(db, "sqlmock")).TransferBatchTx(context.TODO(), []TransferTxParams{
+		{Transfer: model.Transfer{ID: "tr1", SenderId: "acc1", ReceiverId: "acc2", Amount: 500, Status: model.TransferStatusPendingReview}},
+		{Transfer: model.Transfer{ID: "tr2", SenderId: "acc1", ReceiverId: "acc3", Amount: 700}},
+	})
+	var itemErr *BatchItemError
+	if !errors.As(err, &itemErr) || itemErr.Index != 1 || !errors.Is(err, ErrAccountFrozen) {
+		t.Fatalf("TransferBatchTx() error = %v, want item 1 frozen", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+// TestTransferChecksLockedBalance covers a sender that looked funded when the
+// usecase read it, but whose balance was reserved by a hold before the lock.
+func TestTransferChecksLockedBalance(t *testing.T) {
+	transferRows := []string{"id", "sender_id", "receiver_id", "amount", "status", "description", "reference", "metadata", "risk_score", "risk_reasons", "reviewed_by", "reviewed_at", "created_at"}
+	fees := []model.TransferFee{{ID: "fee1", RuleID: "rule1", Amount: 100}}
+
+	test := []struct {
+		name   string
+		actual func(sqlmock.Sqlmock)
+		run    func(TransferRepository) error
+	}{
+		{
+			name:   "transfer",
+			actual: func(s sqlmock.Sqlmock) {},
+			run: func(r TransferRepository) error {
+				_, err := r.TransferTx(context.TODO(), TransferTxParams{
+					Transfer: model.Transfer{ID: "tr1", SenderId: "acc1", ReceiverId: "acc2", Amount: 1000},
+					Fees:     fees,
+				})
+				return err
+			},
+		},
+		{
+			name: "approval",
+			actual: func(s sqlmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta("FROM transfers WHERE id = $1 AND status = 'pending_review' FOR UPDATE")).
+					WithArgs("tr1").
+					WillReturnRows(s.NewRows(transferRows).
+						AddRow("tr1", "acc1", "acc2", 1000, model.TransferStatusPendingReview, "", "", []byte(`{}`), 70, []byte(`[]`), nil, nil, ""))
+			},
+			run: func(r TransferRepository) error {
+				_, err := r.ApproveTx(context.TODO(), "tr1", "admin", fees)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range test {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mock.ExpectBegin()
+			tt.actual(mock)
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT owner FROM accounts WHERE id = $1")).
+				WithArgs("acc1").
+				WillReturnRows(mock.NewRows([]string{"owner"}).AddRow("fulan"))
+			mock.ExpectExec(regexp.QuoteMeta("SELECT username FROM users WHERE username = $1 FOR NO KEY UPDATE")).
+				WithArgs("fulan").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			// 5000 on the books, 4000 of them held: 1000 plus the fee of 100
+			// is more than is available.
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT id, owner, balance, currency, held_balance, frozen_at FROM accounts")).
+				WithArgs("acc1").
+				WillReturnRows(mock.NewRows([]string{"id", "owner", "balance", "currency", "held_balance", "frozen_at"}).
+					AddRow("acc1", "fulan", 5000, "IDR", 4000, nil))
+			mock.ExpectRollback()
+
+			if err := tt.run(NewTransferRepository(sqlx.NewDb(db, "sqlmock"))); !errors.Is(err, ErrInsufficientFunds) {
+				t.Fatalf("error = %v, want %v", err, ErrInsufficientFunds)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestApproveRefusesFrozenReceiver(t *testing.T) {
+	transferRows := []string{"id", "sender_id", "receiver_id", "amount", "status", "description", "reference", "metadata", "risk_score", "risk_reasons", "reviewed_by", "reviewed_at", "created_at"}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM transfers WHERE id = $1 AND status = 'pending_review' FOR UPDATE")).
+		WithArgs("tr1").
+		WillReturnRows(mock.NewRows(transferRows).
+			AddRow("tr1", "acc1", "acc2", 1000, model.TransferStatusPendingReview, "", "", []byte(`{}`), 70, []byte(`[]`), nil, nil, ""))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT owner FROM accounts WHERE id = $1")).
+		WithArgs("acc1").
+		WillReturnRows(mock.NewRows([]string{"owner"}).AddRow("fulan"))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT username FROM users WHERE username = $1 FOR NO KEY UPDATE")).
+		WithArgs("fulan").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, owner, balance, currency, held_balance, frozen_at FROM accounts")).
+		WithArgs("acc1").
+		WillReturnRows(mock.NewRows([]string{"id", "owner", "balance", "currency", "held_balance", "frozen_at"}).
+			AddRow("acc1", "fulan", 5000, "IDR", 0, nil))
+	// The receiver was frozen while the transfer waited for review.
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT frozen_at FROM accounts WHERE id = $1 LIMIT 1 FOR NO KEY UPDATE")).
+		WithArgs("acc2").
+		WillReturnRows(mock.NewRows([]string{"frozen_at"}).AddRow(time.Now()))
+	mock.ExpectRollback()
+
+	_, err = NewTransferRepository(sqlx.NewDb(db, "sqlmock")).ApproveTx(context.TODO(), "tr1", "admin", nil)
+	if !errors.Is(err, ErrAccountFrozen) {
+		t.Fatalf("ApproveTx() error = %v, want %v", err, ErrAccountFrozen)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -3,7 +3,6 @@ package repository
 import (
 	"context"
 
-	"github.com/jmoiron/sqlx"
 	"github.com/terajari/bank-api/model"
 )
 
@@ -11,23 +10,24 @@ type UsersRepository interface {
 	Create(ctx context.Context, user model.Users) (model.Users, error)
 	Get(ctx context.Context, username string) (model.Users, error)
 	Update(ctx context.Context, user model.Users) (model.Users, error)
+	UpdateHashedPassword(ctx context.Context, username, hashedPassword string) error
 }
 
 type userRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
-func NewUsersRepository(db *sqlx.DB) UsersRepository {
+func NewUsersRepository(db DBTX) UsersRepository {
 	return &userRepository{
 		db: db,
 	}
 }
 
 func (u *userRepository) Create(ctx context.Context, user model.Users) (model.Users, error) {
-	query := "INSERT INTO users (username, hashed_password, full_name, email) VALUES ($1, $2, $3, $4) RETURNING username, hashed_password, full_name, email, password_changed_at, created_at"
-	row := u.db.QueryRowContext(ctx, query, user.Username, user.HashedPassword, user.FullName, user.Email)
+	query := "INSERT INTO users (username, hashed_password, full_name, email, role) VALUES ($1, $2, $3, $4, $5) RETURNING username, hashed_password, full_name, email, role, password_changed_at, created_at"
+	row := u.db.QueryRowContext(ctx, query, user.Username, user.HashedPassword, user.FullName, user.Email, user.Role)
 	var us model.Users
-	err := row.Scan(&us.Username, &us.HashedPassword, &us.FullName, &us.Email, &us.PasswordChangedAt, &us.CreatedAt)
+	err := row.Scan(&us.Username, &us.HashedPassword, &us.FullName, &us.Email, &us.Role, &us.PasswordChangedAt, &us.CreatedAt)
 	if err != nil {
 		return model.Users{}, err
 	}
@@ -35,10 +35,10 @@ func (u *userRepository) Create(ctx context.Context, user model.Users) (model.Us
 }
 
 func (u *userRepository) Get(ctx context.Context, username string) (model.Users, error) {
-	query := `SELECT username, hashed_password, full_name, email, password_changed_at, created_at FROM users WHERE username = $1 LIMIT 1`
+	query := `SELECT username, hashed_password, full_name, email, role, password_changed_at, created_at FROM users WHERE username = $1 LIMIT 1`
 	row := u.db.QueryRowContext(ctx, query, username)
 	var us model.Users
-	err := row.Scan(&us.Username, &us.HashedPassword, &us.FullName, &us.Email, &us.PasswordChangedAt, &us.CreatedAt)
+	err := row.Scan(&us.Username, &us.HashedPassword, &us.FullName, &us.Email, &us.Role, &us.PasswordChangedAt, &us.CreatedAt)
 	if err != nil {
 		return model.Users{}, err
 	}
@@ -64,3 +64,14 @@ func (u *userRepository) Update(ctx context.Context, user model.Users) (model.Us
 	}
 	return us, nil
 }
+
+// UpdateHashedPassword replaces the stored hash without touching
+// password_changed_at, it is used to upgrade hashes of the same password.
+func (u *userRepository) UpdateHashedPassword(ctx context.Context, username, hashedPassword string) error {
+	query := "UPDATE users SET hashed_password = $2 WHERE username = $1"
+	_, err := u.db.ExecContext(ctx, query, username, hashedPassword)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Package risk provides the built-in transfer risk evaluator. Rules are
+// declared in a JSON file; every matching rule adds its score and may force
+// a decision on its own.
+package risk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/terajari/bank-api/model"
+)
+
+// Condition matches a transfer when every field that is set holds. The hour
+// range is [HourFrom, HourTo) in UTC and wraps past midnight when HourFrom is
+// greater than HourTo.
+type Condition struct {
+	AmountGte       *int64 `json:"amount_gte"`
+	Currency        string `json:"currency"`
+	NewPayee        *bool  `json:"new_payee"`
+	HourFrom        *int   `json:"hour_from"`
+	HourTo          *int   `json:"hour_to"`
+	RecentCountGte  *int64 `json:"recent_count_gte"`
+	RecentAmountGte *int64 `json:"recent_amount_gte"`
+}
+
+type Rule struct {
+	Name   string    `json:"name"`
+	When   Condition `json:"when"`
+	Score  int       `json:"score"`
+	Action string    `json:"action"`
+}
+
+// Rules holds the rule set and the score thresholds. A zero threshold is not
+// applied.
+type Rules struct {
+	ReviewScore int    `json:"review_score"`
+	DenyScore   int    `json:"deny_score"`
+	Rules       []Rule `json:"rules"`
+}
+
+type RuleEvaluator struct {
+	rules Rules
+}
+
+func NewRuleEvaluator(rules Rules) (*RuleEvaluator, error) {
+	for _, rule := range rules.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("risk rule without a name")
+		}
+		switch rule.Action {
+		case "", model.RiskReview, model.RiskDeny:
+		default:
+			return nil, fmt.Errorf("risk rule %q: unsupported action %q", rule.Name, rule.Action)
+		}
+		if (rule.When.HourFrom == nil) != (rule.When.HourTo == nil) {
+			return nil, fmt.Errorf("risk rule %q: hour_from and hour_to must be set together", rule.Name)
+		}
+		if rule.When.HourFrom != nil && (*rule.When.HourFrom < 0 || *rule.When.HourFrom > 23 || *rule.When.HourTo < 0 || *rule.When.HourTo > 24) {
+			return nil, fmt.Errorf("risk rule %q: hour out of range", rule.Name)
+		}
+	}
+	return &RuleEvaluator{rules: rules}, nil
+}
+
+// LoadRules reads a rule set from a JSON file.
+func LoadRules(path string) (*RuleEvaluator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules Rules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("risk rules %s: %w", path, err)
+	}
+	return NewRuleEvaluator(rules)
+}
+
+func (e *RuleEvaluator) Evaluate(ctx context.Context, in model.RiskInput) (model.RiskResult, error) {
+	result := model.RiskResult{Decision: model.RiskAllow, Reasons: []string{}}
+	forced := model.RiskAllow
+	for _, rule := range e.rules.Rules {
+		if !rule.When.matches(in) {
+			continue
+		}
+		result.Score += rule.Score
+		result.Reasons = append(result.Reasons, rule.Name)
+		if rule.Action == model.RiskDeny || (rule.Action == model.RiskReview && forced == model.RiskAllow) {
+			forced = rule.Action
+		}
+	}
+
+	switch {
+	case forced == model.RiskDeny || (e.rules.DenyScore > 0 && result.Score >= e.rules.DenyScore):
+		result.Decision = model.RiskDeny
+	case forced == model.RiskReview || (e.rules.ReviewScore > 0 && result.Score >= e.rules.ReviewScore):
+		result.Decision = model.RiskReview
+	}
+	return result, nil
+}
+
+func (c Condition) matches(in model.RiskInput) bool {
+	if c.AmountGte != nil && in.Amount < *c.AmountGte {
+		return false
+	}
+	if c.Currency != "" && in.Currency != c.Currency {
+		return false
+	}
+	if c.NewPayee != nil && in.NewPayee != *c.NewPayee {
+		return false
+	}
+	if c.HourFrom != nil && c.HourTo != nil {
+		from, to := *c.HourFrom, *c.HourTo
+		if from <= to {
+			if in.Hour < from || in.Hour >= to {
+				return false
+			}
+		} else if in.Hour < from && in.Hour >= to {
+			return false
+		}
+	}
+	if c.RecentCountGte != nil && in.RecentCount < *c.RecentCountGte {
+		return false
+	}
+	if c.RecentAmountGte != nil && in.RecentAmount < *c.RecentAmountGte {
+		return false
+	}
+	return true
+}
+
+// AllowAll is used when no rule file is configured.
+type AllowAll struct{}
+
+func (AllowAll) Evaluate(ctx context.Context, in model.RiskInput) (model.RiskResult, error) {
+	return model.RiskResult{Decision: model.RiskAllow, Reasons: []string{}}, nil
+}
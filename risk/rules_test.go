@@ -0,0 +1,121 @@
+package risk
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/terajari/bank-api/model"
+)
+
+func int64p(v int64) *int64 { return &v }
+func intp(v int) *int       { return &v }
+func boolp(v bool) *bool    { return &v }
+
+func TestRuleEvaluatorEvaluate(t *testing.T) {
+	evaluator, err := NewRuleEvaluator(Rules{
+		ReviewScore: 50,
+		DenyScore:   100,
+		Rules: []Rule{
+			{Name: "large amount", When: Condition{AmountGte: int64p(1000)}, Score: 40},
+			{Name: "new payee", When: Condition{NewPayee: boolp(true)}, Score: 20},
+			{Name: "night", When: Condition{HourFrom: intp(22), HourTo: intp(5)}, Score: 20},
+			{Name: "velocity", When: Condition{RecentCountGte: int64p(5)}, Score: 60},
+			{Name: "usd to new payee", When: Condition{Currency: "USD", NewPayee: boolp(true)}, Action: model.RiskReview},
+			{Name: "huge", When: Condition{AmountGte: int64p(1000000)}, Action: model.RiskDeny},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name string
+		in   model.RiskInput
+		want model.RiskResult
+	}{
+		{
+			name: "nothing matches",
+			in:   model.RiskInput{Amount: 10, Currency: "IDR", Hour: 12},
+			want: model.RiskResult{Decision: model.RiskAllow, Score: 0, Reasons: []string{}},
+		},
+		{
+			name: "score below review",
+			in:   model.RiskInput{Amount: 1000, Currency: "IDR", Hour: 12},
+			want: model.RiskResult{Decision: model.RiskAllow, Score: 40, Reasons: []string{"large amount"}},
+		},
+		{
+			name: "score reaches review",
+			in:   model.RiskInput{Amount: 1000, Currency: "IDR", NewPayee: true, Hour: 12},
+			want: model.RiskResult{Decision: model.RiskReview, Score: 60, Reasons: []string{"large amount", "new payee"}},
+		},
+		{
+			name: "hour range wraps midnight",
+			in:   model.RiskInput{Amount: 1000, Currency: "IDR", Hour: 2},
+			want: model.RiskResult{Decision: model.RiskReview, Score: 60, Reasons: []string{"large amount", "night"}},
+		},
+		{
+			name: "score reaches deny",
+			in:   model.RiskInput{Amount: 1000, Currency: "IDR", Hour: 23, RecentCount: 5},
+			want: model.RiskResult{Decision: model.RiskDeny, Score: 120, Reasons: []string{"large amount", "night", "velocity"}},
+		},
+		{
+			name: "rule forces review",
+			in:   model.RiskInput{Amount: 10, Currency: "USD", NewPayee: true, Hour: 12},
+			want: model.RiskResult{Decision: model.RiskReview, Score: 20, Reasons: []string{"new payee", "usd to new payee"}},
+		},
+		{
+			name: "rule forces deny",
+			in:   model.RiskInput{Amount: 1000000, Currency: "IDR", Hour: 12},
+			want: model.RiskResult{Decision: model.RiskDeny, Score: 40, Reasons: []string{"large amount", "huge"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := evaluator.Evaluate(context.TODO(), tc.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Evaluate() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewRuleEvaluatorInvalid(t *testing.T) {
+	testCases := []struct {
+		name string
+		rule Rule
+	}{
+		{name: "missing name", rule: Rule{}},
+		{name: "unknown action", rule: Rule{Name: "a", Action: "block"}},
+		{name: "half hour range", rule: Rule{Name: "a", When: Condition{HourFrom: intp(1)}}},
+		{name: "hour out of range", rule: Rule{Name: "a", When: Condition{HourFrom: intp(1), HourTo: intp(25)}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewRuleEvaluator(Rules{Rules: []Rule{tc.rule}}); err == nil {
+				t.Errorf("NewRuleEvaluator() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestLoadRulesExample(t *testing.T) {
+	if _, err := LoadRules(filepath.Join("..", "risk-rules.example.json")); err != nil {
+		t.Fatalf("LoadRules() error = %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRules(path); err == nil {
+		t.Errorf("LoadRules() error = nil, want error for invalid json")
+	}
+}
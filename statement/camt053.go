@@ -0,0 +1,182 @@
+package statement
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+
+	"github.com/terajari/bank-api/model"
+)
+
+const camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+// camt053Writer writes a BkToCstmrStmt with one Stmt. The document is
+// opened in Begin, every entry is encoded as its own Ntry element and the
+// open elements are closed in End.
+type camt053Writer struct {
+	w      io.Writer
+	enc    *xml.Encoder
+	header model.StatementHeader
+}
+
+func newCamt053Writer(w io.Writer) *camt053Writer {
+	return &camt053Writer{w: w, enc: xml.NewEncoder(w)}
+}
+
+type camtAmount struct {
+	Ccy   string `xml:"Ccy,attr"`
+	Value string `xml:",chardata"`
+}
+
+type camtGroupHeader struct {
+	XMLName  xml.Name `xml:"GrpHdr"`
+	MsgId    string   `xml:"MsgId"`
+	CreDtTm  string   `xml:"CreDtTm"`
+	PgNb     int      `xml:"MsgPgntn>PgNb"`
+	LastPgIn bool     `xml:"MsgPgntn>LastPgInd"`
+}
+
+type camtPeriod struct {
+	XMLName xml.Name `xml:"FrToDt"`
+	FrDtTm  string   `xml:"FrDtTm"`
+	ToDtTm  string   `xml:"ToDtTm"`
+}
+
+type camtAccount struct {
+	XMLName xml.Name `xml:"Acct"`
+	Id      string   `xml:"Id>Othr>Id"`
+	Ccy     string   `xml:"Ccy"`
+	Ownr    string   `xml:"Ownr>Nm"`
+}
+
+type camtBalance struct {
+	XMLName   xml.Name   `xml:"Bal"`
+	Cd        string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amt       camtAmount `xml:"Amt"`
+	CdtDbtInd string     `xml:"CdtDbtInd"`
+	Dt        string     `xml:"Dt>Dt"`
+}
+
+type camtEntry struct {
+	XMLName     xml.Name   `xml:"Ntry"`
+	NtryRef     string     `xml:"NtryRef"`
+	Amt         camtAmount `xml:"Amt"`
+	CdtDbtInd   string     `xml:"CdtDbtInd"`
+	Sts         string     `xml:"Sts"`
+	BookgDt     string     `xml:"BookgDt>DtTm"`
+	ValDt       string     `xml:"ValDt>DtTm"`
+	BkTxCd      string     `xml:"BkTxCd>Prtry>Cd"`
+	AcctSvcrRef string     `xml:"NtryDtls>TxDtls>Refs>AcctSvcrRef,omitempty"`
+	EndToEndId  string     `xml:"NtryDtls>TxDtls>Refs>EndToEndId,omitempty"`
+	DbtrAcct    string     `xml:"NtryDtls>TxDtls>RltdPties>DbtrAcct>Id>Othr>Id,omitempty"`
+	CdtrAcct    string     `xml:"NtryDtls>TxDtls>RltdPties>CdtrAcct>Id>Othr>Id,omitempty"`
+	Ustrd       string     `xml:"NtryDtls>TxDtls>RmtInf>Ustrd,omitempty"`
+}
+
+func creditDebit(amount int64) string {
+	if amount < 0 {
+		return "DBIT"
+	}
+	return "CRDT"
+}
+
+func (c *camt053Writer) Begin(header model.StatementHeader) error {
+	c.header = header
+	if _, err := io.WriteString(c.w, xml.Header); err != nil {
+		return err
+	}
+	c.enc.Indent("", "  ")
+
+	document := xml.StartElement{Name: xml.Name{Local: "Document"}, Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: camt053Namespace}}}
+	if err := c.enc.EncodeToken(document); err != nil {
+		return err
+	}
+	if err := c.enc.EncodeToken(xml.StartElement{Name: xml.Name{Local: "BkToCstmrStmt"}}); err != nil {
+		return err
+	}
+
+	created := header.GeneratedAt.UTC().Format(time.RFC3339)
+	statementId := header.AccountId + "-" + header.From.Format("20060102") + "-" + header.To.Format("20060102")
+	if err := c.enc.Encode(camtGroupHeader{MsgId: statementId, CreDtTm: created, PgNb: 1, LastPgIn: true}); err != nil {
+		return err
+	}
+
+	if err := c.enc.EncodeToken(xml.StartElement{Name: xml.Name{Local: "Stmt"}}); err != nil {
+		return err
+	}
+	// The statement identification, period and account come first in Stmt,
+	// in the order of the schema.
+	if err := c.enc.EncodeElement(statementId, xml.StartElement{Name: xml.Name{Local: "Id"}}); err != nil {
+		return err
+	}
+	if err := c.enc.EncodeElement(created, xml.StartElement{Name: xml.Name{Local: "CreDtTm"}}); err != nil {
+		return err
+	}
+	err := c.enc.Encode(camtPeriod{
+		FrDtTm: header.From.UTC().Format(time.RFC3339),
+		ToDtTm: header.To.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.enc.Encode(camtAccount{Id: header.AccountId, Ccy: header.Currency, Ownr: header.Owner}); err != nil {
+		return err
+	}
+	if err := c.balance("OPBD", header.OpeningBalance, header.From); err != nil {
+		return err
+	}
+	// camt.053 dates the closing balance with the last day of the statement.
+	return c.balance("CLBD", header.ClosingBalance, header.To.Add(-time.Nanosecond))
+}
+
+func (c *camt053Writer) balance(code string, amount int64, at time.Time) error {
+	return c.enc.Encode(camtBalance{
+		Cd:        code,
+		Amt:       camtAmount{Ccy: c.header.Currency, Value: formatDecimal(amount, c.header.Currency)},
+		CdtDbtInd: creditDebit(amount),
+		Dt:        at.UTC().Format(time.DateOnly),
+	})
+}
+
+func (c *camt053Writer) Entry(entry model.StatementEntry) error {
+	booked := entry.CreatedAt.UTC().Format(time.RFC3339)
+	code := "TRANSFER"
+	if entry.Kind == model.StatementEntryFee {
+		code = "FEE"
+	}
+	// The counterparty of a credit is the debtor, of a debit the creditor.
+	ntry := camtEntry{
+		NtryRef:     entry.EntryId,
+		Amt:         camtAmount{Ccy: c.header.Currency, Value: formatDecimal(entry.Amount, c.header.Currency)},
+		CdtDbtInd:   creditDebit(entry.Amount),
+		Sts:         "BOOK",
+		BookgDt:     booked,
+		ValDt:       booked,
+		BkTxCd:      code,
+		AcctSvcrRef: entry.TransferId,
+		EndToEndId:  entry.Reference,
+		Ustrd:       entry.Description,
+	}
+	if ntry.CdtDbtInd == "CRDT" {
+		ntry.DbtrAcct = entry.Counterparty
+	} else {
+		ntry.CdtrAcct = entry.Counterparty
+	}
+	if err := c.enc.Encode(ntry); err != nil {
+		return err
+	}
+	return c.enc.Flush()
+}
+
+func (c *camt053Writer) End() error {
+	for _, name := range []string{"Stmt", "BkToCstmrStmt", "Document"} {
+		if err := c.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: name}}); err != nil {
+			return err
+		}
+	}
+	if err := c.enc.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(c.w, "\n")
+	return err
+}
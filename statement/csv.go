@@ -0,0 +1,54 @@
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/terajari/bank-api/model"
+)
+
+type csvWriter struct {
+	w      *csv.Writer
+	header model.StatementHeader
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) Begin(header model.StatementHeader) error {
+	c.header = header
+	if err := c.w.Write([]string{"date", "entry_id", "transfer_id", "type", "counterparty", "description", "reference", "amount", "balance"}); err != nil {
+		return err
+	}
+	return c.w.Write([]string{header.From.Format(time.RFC3339), "", "", "opening_balance", "", "", "", "", strconv.FormatInt(header.OpeningBalance, 10)})
+}
+
+func (c *csvWriter) Entry(entry model.StatementEntry) error {
+	err := c.w.Write([]string{
+		entry.CreatedAt.UTC().Format(time.RFC3339),
+		entry.EntryId,
+		entry.TransferId,
+		entry.Kind,
+		entry.Counterparty,
+		entry.Description,
+		entry.Reference,
+		strconv.FormatInt(entry.Amount, 10),
+		strconv.FormatInt(entry.Balance, 10),
+	})
+	if err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) End() error {
+	if err := c.w.Write([]string{c.header.To.Format(time.RFC3339), "", "", "closing_balance", "", "", "", "", strconv.FormatInt(c.header.ClosingBalance, 10)}); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,87 @@
+package statement
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/terajari/bank-api/model"
+)
+
+type jsonlWriter struct {
+	enc     *json.Encoder
+	header  model.StatementHeader
+	entries int
+}
+
+func newJSONLWriter(w io.Writer) *jsonlWriter {
+	return &jsonlWriter{enc: json.NewEncoder(w)}
+}
+
+type jsonlHeader struct {
+	Type           string    `json:"type"`
+	AccountId      string    `json:"account_id"`
+	Currency       string    `json:"currency"`
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	OpeningBalance int64     `json:"opening_balance"`
+	ClosingBalance int64     `json:"closing_balance"`
+	GeneratedAt    time.Time `json:"generated_at"`
+}
+
+type jsonlEntry struct {
+	Type         string    `json:"type"`
+	EntryId      string    `json:"entry_id"`
+	TransferId   string    `json:"transfer_id,omitempty"`
+	Kind         string    `json:"kind"`
+	Counterparty string    `json:"counterparty,omitempty"`
+	Description  string    `json:"description,omitempty"`
+	Reference    string    `json:"reference,omitempty"`
+	Amount       int64     `json:"amount"`
+	Balance      int64     `json:"balance"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type jsonlFooter struct {
+	Type           string `json:"type"`
+	EntryCount     int    `json:"entry_count"`
+	ClosingBalance int64  `json:"closing_balance"`
+}
+
+func (j *jsonlWriter) Begin(header model.StatementHeader) error {
+	j.header = header
+	return j.enc.Encode(jsonlHeader{
+		Type:           "header",
+		AccountId:      header.AccountId,
+		Currency:       header.Currency,
+		From:           header.From,
+		To:             header.To,
+		OpeningBalance: header.OpeningBalance,
+		ClosingBalance: header.ClosingBalance,
+		GeneratedAt:    header.GeneratedAt,
+	})
+}
+
+func (j *jsonlWriter) Entry(entry model.StatementEntry) error {
+	j.entries++
+	return j.enc.Encode(jsonlEntry{
+		Type:         "entry",
+		EntryId:      entry.EntryId,
+		TransferId:   entry.TransferId,
+		Kind:         entry.Kind,
+		Counterparty: entry.Counterparty,
+		Description:  entry.Description,
+		Reference:    entry.Reference,
+		Amount:       entry.Amount,
+		Balance:      entry.Balance,
+		CreatedAt:    entry.CreatedAt.UTC(),
+	})
+}
+
+func (j *jsonlWriter) End() error {
+	return j.enc.Encode(jsonlFooter{
+		Type:           "footer",
+		EntryCount:     j.entries,
+		ClosingBalance: j.header.ClosingBalance,
+	})
+}
@@ -0,0 +1,86 @@
+// Package statement renders account statements as CSV, JSON Lines or
+// ISO 20022 camt.053 XML. Writers emit every entry as it arrives so a
+// statement never has to be held in memory.
+package statement
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/terajari/bank-api/model"
+)
+
+const (
+	FormatCSV     = "csv"
+	FormatJSONL   = "jsonl"
+	FormatCamt053 = "camt053"
+)
+
+type Writer interface {
+	Begin(header model.StatementHeader) error
+	Entry(entry model.StatementEntry) error
+	End() error
+}
+
+// New returns a writer for format that writes to w.
+func New(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatJSONL:
+		return newJSONLWriter(w), nil
+	case FormatCamt053:
+		return newCamt053Writer(w), nil
+	}
+	return nil, fmt.Errorf("unsupported statement format %q", format)
+}
+
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatJSONL:
+		return "application/x-ndjson"
+	case FormatCamt053:
+		return "application/xml; charset=utf-8"
+	}
+	return "application/octet-stream"
+}
+
+func FileExtension(format string) string {
+	switch format {
+	case FormatCSV:
+		return "csv"
+	case FormatJSONL:
+		return "jsonl"
+	case FormatCamt053:
+		return "xml"
+	}
+	return "txt"
+}
+
+// minorUnits is the ISO 4217 exponent of the supported currencies.
+var minorUnits = map[string]int{
+	"IDR": 2,
+	"USD": 2,
+	"EUR": 2,
+}
+
+// formatDecimal renders an amount in minor units as an unsigned decimal.
+func formatDecimal(amount int64, currency string) string {
+	if amount < 0 {
+		amount = -amount
+	}
+	exp, ok := minorUnits[currency]
+	if !ok {
+		exp = 2
+	}
+	div := int64(1)
+	for i := 0; i < exp; i++ {
+		div *= 10
+	}
+	if exp == 0 {
+		return fmt.Sprintf("%d", amount)
+	}
+	return fmt.Sprintf("%d.%0*d", amount/div, exp, amount%div)
+}
@@ -0,0 +1,229 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/terajari/bank-api/model"
+)
+
+var (
+	testHeader = model.StatementHeader{
+		AccountId:      "acc1",
+		Owner:          "alice",
+		Currency:       "USD",
+		From:           time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC),
+		To:             time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC),
+		OpeningBalance: 10000,
+		ClosingBalance: 8950,
+		GeneratedAt:    time.Date(2023, 12, 2, 8, 0, 0, 0, time.UTC),
+	}
+	testEntries = []model.StatementEntry{
+		{EntryId: "e1", TransferId: "t1", Kind: model.StatementEntryTransfer, Counterparty: "acc2", Description: "November rent", Reference: "INV-2023-11", Amount: -1000, Balance: 9000, CreatedAt: time.Date(2023, 11, 3, 10, 0, 0, 0, time.UTC)},
+		{EntryId: "e2", TransferId: "t1", Kind: model.StatementEntryFee, Counterparty: "sys-fee-revenue-usd", Amount: -50, Balance: 8950, CreatedAt: time.Date(2023, 11, 3, 10, 0, 0, 0, time.UTC)},
+	}
+)
+
+func render(t *testing.T, format string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := New(format, &buf)
+	if err != nil {
+		t.Fatalf("New(%q) error = %v", format, err)
+	}
+	if err := w.Begin(testHeader); err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	for _, e := range testEntries {
+		if err := w.Entry(e); err != nil {
+			t.Fatalf("Entry() error = %v", err)
+		}
+	}
+	if err := w.End(); err != nil {
+		t.Fatalf("End() error = %v", err)
+	}
+	return buf.String()
+}
+
+func TestCSV(t *testing.T) {
+	want := strings.Join([]string{
+		"date,entry_id,transfer_id,type,counterparty,description,reference,amount,balance",
+		"2023-11-01T00:00:00Z,,,opening_balance,,,,,10000",
+		"2023-11-03T10:00:00Z,e1,t1,transfer,acc2,November rent,INV-2023-11,-1000,9000",
+		"2023-11-03T10:00:00Z,e2,t1,fee,sys-fee-revenue-usd,,,-50,8950",
+		"2023-12-01T00:00:00Z,,,closing_balance,,,,,8950",
+		"",
+	}, "\n")
+	if got := render(t, FormatCSV); got != want {
+		t.Errorf("csv got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestJSONL(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(render(t, FormatJSONL)), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4", len(lines))
+	}
+	types := []string{"header", "entry", "entry", "footer"}
+	for i, line := range lines {
+		var v map[string]any
+		if err := json.Unmarshal([]byte(line), &v); err != nil {
+			t.Fatalf("line %d is not JSON: %v", i, err)
+		}
+		if v["type"] != types[i] {
+			t.Errorf("line %d type = %v, want %s", i, v["type"], types[i])
+		}
+	}
+	if !strings.Contains(lines[3], `"entry_count":2`) {
+		t.Errorf("footer = %s, want entry_count 2", lines[3])
+	}
+}
+
+func TestCamt053(t *testing.T) {
+	var doc struct {
+		XMLName xml.Name `xml:"Document"`
+		Stmt    struct {
+			Bal []struct {
+				Cd        string `xml:"Tp>CdOrPrtry>Cd"`
+				Amt       string `xml:"Amt"`
+				CdtDbtInd string `xml:"CdtDbtInd"`
+				Dt        string `xml:"Dt>Dt"`
+			} `xml:"Bal"`
+			Ntry []struct {
+				Amt       string `xml:"Amt"`
+				CdtDbtInd string `xml:"CdtDbtInd"`
+				BkTxCd    string `xml:"BkTxCd>Prtry>Cd"`
+				EndToEnd  string `xml:"NtryDtls>TxDtls>Refs>EndToEndId"`
+				Dbtr      string `xml:"NtryDtls>TxDtls>RltdPties>DbtrAcct>Id>Othr>Id"`
+				Cdtr      string `xml:"NtryDtls>TxDtls>RltdPties>CdtrAcct>Id>Othr>Id"`
+				Ustrd     string `xml:"NtryDtls>TxDtls>RmtInf>Ustrd"`
+			} `xml:"Ntry"`
+		} `xml:"BkToCstmrStmt>Stmt"`
+	}
+	if err := xml.Unmarshal([]byte(render(t, FormatCamt053)), &doc); err != nil {
+		t.Fatalf("invalid xml: %v", err)
+	}
+	if len(doc.Stmt.Bal) != 2 || doc.Stmt.Bal[0].Cd != "OPBD" || doc.Stmt.Bal[0].Amt != "100.00" || doc.Stmt.Bal[1].Cd != "CLBD" || doc.Stmt.Bal[1].Amt != "89.50" {
+		t.Errorf("balances = %+v", doc.Stmt.Bal)
+	}
+	if doc.Stmt.Bal[1].Dt != "2023-11-30" {
+		t.Errorf("closing balance date = %s, want 2023-11-30", doc.Stmt.Bal[1].Dt)
+	}
+	if len(doc.Stmt.Ntry) != 2 {
+		t.Fatalf("got %d entries, want 2", len(doc.Stmt.Ntry))
+	}
+	if e := doc.Stmt.Ntry[0]; e.EndToEnd != "INV-2023-11" || e.Ustrd != "November rent" || e.Cdtr != "acc2" || e.Dbtr != "" {
+		t.Errorf("transfer entry = %+v", e)
+	}
+	if e := doc.Stmt.Ntry[1]; e.Amt != "0.50" || e.CdtDbtInd != "DBIT" || e.BkTxCd != "FEE" {
+		t.Errorf("fee entry = %+v", e)
+	}
+}
+
+func TestCamt053CreditCounterparty(t *testing.T) {
+	var buf bytes.Buffer
+	w := newCamt053Writer(&buf)
+	w.header = testHeader
+	err := w.Entry(model.StatementEntry{EntryId: "e3", TransferId: "t2", Kind: model.StatementEntryTransfer, Counterparty: "acc3", Amount: 2500, CreatedAt: time.Date(2023, 11, 4, 9, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ntry struct {
+		CdtDbtInd string `xml:"CdtDbtInd"`
+		Dbtr      string `xml:"NtryDtls>TxDtls>RltdPties>DbtrAcct>Id>Othr>Id"`
+		Cdtr      string `xml:"NtryDtls>TxDtls>RltdPties>CdtrAcct>Id>Othr>Id"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &ntry); err != nil {
+		t.Fatalf("invalid xml: %v", err)
+	}
+	if ntry.CdtDbtInd != "CRDT" || ntry.Dbtr != "acc3" || ntry.Cdtr != "" {
+		t.Errorf("credit entry = %+v, want the debtor account acc3", ntry)
+	}
+}
+
+// camt053Sequences lists, for the camt.053.001.02 complex types the writer
+// uses, the child elements in the order of the schema's xs:sequence.
+var camt053Sequences = map[string][]string{
+	"Document":      {"BkToCstmrStmt"},
+	"BkToCstmrStmt": {"GrpHdr", "Stmt", "SplmtryData"},
+	"GrpHdr":        {"MsgId", "CreDtTm", "MsgRcpt", "MsgPgntn", "AddtlInf"},
+	"MsgPgntn":      {"PgNb", "LastPgInd"},
+	"Stmt":          {"Id", "ElctrncSeqNb", "LglSeqNb", "CreDtTm", "FrToDt", "CpyDplctInd", "RptgSrc", "Acct", "RltdAcct", "Intrst", "Bal", "TxsSummry", "Ntry", "AddtlStmtInf"},
+	"FrToDt":        {"FrDtTm", "ToDtTm"},
+	"Acct":          {"Id", "Tp", "Ccy", "Nm", "Ownr", "Svcr"},
+	"Ownr":          {"Nm", "PstlAdr", "Id", "CtryOfRes", "CtctDtls"},
+	"Bal":           {"Tp", "CdtLine", "Amt", "CdtDbtInd", "Dt", "Avlbty"},
+	"Tp":            {"CdOrPrtry", "SubTp"},
+	"Ntry":          {"NtryRef", "Amt", "CdtDbtInd", "RvslInd", "Sts", "BookgDt", "ValDt", "AcctSvcrRef", "Avlbty", "BkTxCd", "ComssnWvrInd", "AddtlInfInd", "AmtDtls", "Chrgs", "TechInptChanl", "Intrst", "NtryDtls", "AddtlNtryInf"},
+	"BkTxCd":        {"Domn", "Prtry"},
+	"Prtry":         {"Cd", "Issr"},
+	"NtryDtls":      {"Btch", "TxDtls"},
+	"TxDtls":        {"Refs", "AmtDtls", "Avlbty", "BkTxCd", "Chrgs", "Intrst", "RltdPties", "RltdAgts", "Purp", "RltdRmtInf", "RmtInf", "RltdDts", "RltdPric", "RltdQties", "FinInstrmId", "Tax", "RtrInf", "CorpActn", "SfkpgAcct", "AddtlTxInf"},
+	"Refs":          {"MsgId", "AcctSvcrRef", "PmtInfId", "InstrId", "EndToEndId", "TxId", "MndtId", "ChqNb", "ClrSysRef", "Prtry"},
+	"RltdPties":     {"InitgPty", "Dbtr", "DbtrAcct", "UltmtDbtr", "Cdtr", "CdtrAcct", "UltmtCdtr", "TradgPty", "Prtry"},
+	"RmtInf":        {"Ustrd", "Strd"},
+}
+
+func TestCamt053ElementOrder(t *testing.T) {
+	// Every open element keeps the schema position of its last child.
+	type open struct {
+		name string
+		pos  int
+	}
+	var stack []open
+	var path []string
+	dec := xml.NewDecoder(strings.NewReader(render(t, FormatCamt053)))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("invalid xml: %v", err)
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			name := tok.Name.Local
+			if n := len(stack); n > 0 {
+				parent := &stack[n-1]
+				// Simple types and the leaves of paths such as Id>Othr>Id
+				// are not listed.
+				if sequence, ok := camt053Sequences[parent.name]; ok {
+					pos := -1
+					for i, child := range sequence {
+						if child == name {
+							pos = i
+						}
+					}
+					switch {
+					case pos < 0:
+						t.Errorf("%s: %s is not part of the schema", strings.Join(path, ">"), name)
+					case pos < parent.pos:
+						t.Errorf("%s: %s comes after %s", strings.Join(path, ">"), name, sequence[parent.pos])
+					default:
+						parent.pos = pos
+					}
+				}
+			}
+			stack = append(stack, open{name: name})
+			path = append(path, name)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+			path = path[:len(path)-1]
+		}
+	}
+	if len(stack) != 0 {
+		t.Errorf("unclosed elements %v", path)
+	}
+}
+
+func TestUnsupportedFormat(t *testing.T) {
+	if _, err := New("pdf", &bytes.Buffer{}); err == nil {
+		t.Error("New(pdf) error = nil, want error")
+	}
+}
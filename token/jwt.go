@@ -36,6 +36,28 @@ func (maker *JWTMaker) CreateToken(
 	return token, payload, err
 }
 
+func (maker *JWTMaker) CreateChallengeToken(
+	username string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, duration)
+	if err != nil {
+		return "", nil, err
+	}
+	payload.Purpose = PurposeMfaChallenge
+
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
+	token, err := jwtToken.SignedString([]byte(maker.secretKey))
+	return token, payload, err
+}
+
+func (maker *JWTMaker) ReissueToken(
+	payload *Payload, duration time.Duration) (string, *Payload, error) {
+	payload = payload.reissue(duration)
+
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
+	token, err := jwtToken.SignedString([]byte(maker.secretKey))
+	return token, payload, err
+}
+
 func (maker *JWTMaker) VerifyToken(token string) (*Payload, error) {
 	keyFunc := func(token *jwt.Token) (interface{}, error) {
 		_, ok := token.Method.(*jwt.SigningMethodHMAC)
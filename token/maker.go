@@ -4,5 +4,9 @@ import "time"
 
 type Maker interface {
 	CreateToken(username string, duration time.Duration) (string, *Payload, error)
-	VerifyToken(token string) (*Payload, error)
+	CreateChallengeToken(username string, duration time.Duration) (string, *Payload, error)
+	// ReissueToken returns a new token with the id, username and purpose of
+	// payload that expires after duration.
+	ReissueToken(payload *Payload, duration time.Duration) (string, *Payload, error)
+	VerifyToken(tokenString string) (*Payload, error)
 }
@@ -39,6 +39,26 @@ func (maker *PasetoMaker) CreateToken(
 	return token, payload, err
 }
 
+func (maker *PasetoMaker) CreateChallengeToken(
+	username string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, duration)
+	if err != nil {
+		return "", nil, err
+	}
+	payload.Purpose = PurposeMfaChallenge
+
+	token, err := maker.paseto.Encrypt(maker.symmetricKey, payload, nil)
+	return token, payload, err
+}
+
+func (maker *PasetoMaker) ReissueToken(
+	payload *Payload, duration time.Duration) (string, *Payload, error) {
+	payload = payload.reissue(duration)
+
+	token, err := maker.paseto.Encrypt(maker.symmetricKey, payload, nil)
+	return token, payload, err
+}
+
 func (maker *PasetoMaker) VerifyToken(
 	token string) (*Payload, error) {
 	payload := &Payload{}
@@ -46,7 +66,7 @@ func (maker *PasetoMaker) VerifyToken(
 	err := maker.paseto.Decrypt(
 		token, maker.symmetricKey, payload, nil)
 	if err != nil {
-		return nil, err
+		return nil, ErrInvalidToken
 	}
 
 	err = payload.Valid()
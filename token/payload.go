@@ -1,18 +1,21 @@
 package token
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/terajari/bank-api/apperror"
 )
 
-var ErrExpiredToken = fmt.Errorf("token expired")
-var ErrInvalidToken = fmt.Errorf("invalid token")
+var ErrExpiredToken = apperror.New(apperror.KindUnauthorized, "token_expired", "token expired")
+var ErrInvalidToken = apperror.New(apperror.KindUnauthorized, "invalid_token", "invalid token")
+
+const PurposeMfaChallenge = "mfa_challenge"
 
 type Payload struct {
 	ID        uuid.UUID `json:"id"`
 	Username  string    `json:"username"`
+	Purpose   string    `json:"purpose,omitempty"`
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiredAt time.Time `json:"expired_at"`
 }
@@ -32,6 +35,18 @@ func NewPayload(
 	}, nil
 }
 
+// reissue copies payload with a new lifetime starting now.
+func (payload *Payload) reissue(duration time.Duration) *Payload {
+	now := time.Now()
+	return &Payload{
+		ID:        payload.ID,
+		Username:  payload.Username,
+		Purpose:   payload.Purpose,
+		IssuedAt:  now,
+		ExpiredAt: now.Add(duration),
+	}
+}
+
 func (payload *Payload) Valid() error {
 	if time.Now().After(payload.ExpiredAt) {
 		return ErrExpiredToken
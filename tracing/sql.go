@@ -0,0 +1,305 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	rowsAffectedKey = attribute.Key("db.rows_affected")
+	returnedRowsKey = attribute.Key("db.response.returned_rows")
+
+	// maxStatementLength bounds the statement recorded on a span.
+	maxStatementLength = 2048
+)
+
+// OpenDB opens a database like sql.Open whose connections record a span for
+// every statement they execute or query, with the sanitized statement and
+// the number of rows affected or returned. Statements are never prepared by
+// the repositories, so prepared statements are not traced.
+func OpenDB(driverName, dsn string) (*sql.DB, error) {
+	// sql.Open is the only way to look a registered driver up by name; it
+	// does not connect.
+	lookup, err := sql.Open(driverName, "")
+	if err != nil {
+		return nil, err
+	}
+	drv := lookup.Driver()
+	lookup.Close()
+
+	var inner driver.Connector = dsnConnector{dsn: dsn, driver: drv}
+	if dc, ok := drv.(driver.DriverContext); ok {
+		if inner, err = dc.OpenConnector(dsn); err != nil {
+			return nil, err
+		}
+	}
+	return sql.OpenDB(&connector{Connector: inner, system: dbSystem(driverName)}), nil
+}
+
+func dbSystem(driverName string) attribute.KeyValue {
+	if driverName == "postgres" {
+		return semconv.DBSystemPostgreSQL
+	}
+	return semconv.DBSystemKey.String(driverName)
+}
+
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) { return c.driver.Open(c.dsn) }
+func (c dsnConnector) Driver() driver.Driver                        { return c.driver }
+
+type connector struct {
+	driver.Connector
+	system attribute.KeyValue
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	cn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{Conn: cn, system: c.system}, nil
+}
+
+// conn traces the statements run on a connection. The optional interfaces
+// the wrapped connection lacks fall back to what database/sql does without
+// them.
+type conn struct {
+	driver.Conn
+	system attribute.KeyValue
+}
+
+func (c *conn) start(ctx context.Context, query string) (context.Context, trace.Span) {
+	operation := Operation(query)
+	return Tracer().Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(c.system, semconv.DBOperationName(operation), semconv.DBQueryText(SanitizeStatement(query))),
+	)
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, span := c.start(ctx, query)
+	res, err := execer.ExecContext(ctx, query, args)
+	if err == nil {
+		if n, err := res.RowsAffected(); err == nil {
+			span.SetAttributes(rowsAffectedKey.Int64(n))
+		}
+	}
+	endStatement(span, err)
+	return res, err
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, span := c.start(ctx, query)
+	rs, err := queryer.QueryContext(ctx, query, args)
+	if err != nil {
+		endStatement(span, err)
+		return nil, err
+	}
+	return &rows{Rows: rs, span: span}, nil
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	// Drivers without BeginTx only support the default options.
+	return c.Conn.Begin()
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *conn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *conn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// rows counts the rows read and ends the span of the query when closed.
+type rows struct {
+	driver.Rows
+	span trace.Span
+	n    int64
+	err  error
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	switch {
+	case err == nil:
+		r.n++
+	case err != io.EOF:
+		r.err = err
+	}
+	return err
+}
+
+func (r *rows) Close() error {
+	err := r.Rows.Close()
+	r.span.SetAttributes(returnedRowsKey.Int64(r.n))
+	endStatement(r.span, errors.Join(r.err, err))
+	return err
+}
+
+func (r *rows) HasNextResultSet() bool {
+	if rs, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return rs.HasNextResultSet()
+	}
+	return false
+}
+
+func (r *rows) NextResultSet() error {
+	if rs, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return rs.NextResultSet()
+	}
+	return io.EOF
+}
+
+func (r *rows) ColumnTypeScanType(index int) reflect.Type {
+	if rs, ok := r.Rows.(driver.RowsColumnTypeScanType); ok {
+		return rs.ColumnTypeScanType(index)
+	}
+	return reflect.TypeOf(new(any)).Elem()
+}
+
+func (r *rows) ColumnTypeDatabaseTypeName(index int) string {
+	if rs, ok := r.Rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return rs.ColumnTypeDatabaseTypeName(index)
+	}
+	return ""
+}
+
+func (r *rows) ColumnTypeLength(index int) (int64, bool) {
+	if rs, ok := r.Rows.(driver.RowsColumnTypeLength); ok {
+		return rs.ColumnTypeLength(index)
+	}
+	return 0, false
+}
+
+func (r *rows) ColumnTypeNullable(index int) (bool, bool) {
+	if rs, ok := r.Rows.(driver.RowsColumnTypeNullable); ok {
+		return rs.ColumnTypeNullable(index)
+	}
+	return false, false
+}
+
+func (r *rows) ColumnTypePrecisionScale(index int) (int64, int64, bool) {
+	if rs, ok := r.Rows.(driver.RowsColumnTypePrecisionScale); ok {
+		return rs.ColumnTypePrecisionScale(index)
+	}
+	return 0, 0, false
+}
+
+func endStatement(span trace.Span, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		err = nil
+	}
+	End(span, err)
+}
+
+// Operation returns the first keyword of query, e.g. SELECT.
+func Operation(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "SQL"
+	}
+	return strings.ToUpper(strings.TrimRight(fields[0], "("))
+}
+
+// SanitizeStatement replaces the string and number literals of query with ?
+// and collapses whitespace, so that values written into a statement never
+// reach the traces. Placeholders such as $1 are kept.
+func SanitizeStatement(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+	space := false
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			space = b.Len() > 0
+			continue
+		case ch == '\'':
+			// '' escapes a quote inside a literal.
+			for i++; i < len(query); i++ {
+				if query[i] == '\'' {
+					if i+1 < len(query) && query[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			ch = '?'
+		case isDigit(ch) && (i == 0 || !isIdentChar(query[i-1])):
+			for i+1 < len(query) && (isDigit(query[i+1]) || query[i+1] == '.') {
+				i++
+			}
+			ch = '?'
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteByte(ch)
+		if b.Len() >= maxStatementLength {
+			break
+		}
+	}
+	return b.String()
+}
+
+func isDigit(ch byte) bool {
+	return '0' <= ch && ch <= '9'
+}
+
+func isIdentChar(ch byte) bool {
+	return ch == '_' || ch == '$' || isDigit(ch) || ('a' <= ch && ch <= 'z') || ('A' <= ch && ch <= 'Z')
+}
@@ -0,0 +1,131 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans installs a tracer provider that records every span for the
+// duration of the test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func attr(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestSanitizeStatement(t *testing.T) {
+	testCases := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "placeholders kept",
+			query: "SELECT id FROM accounts WHERE owner = $1 AND currency = $2",
+			want:  "SELECT id FROM accounts WHERE owner = $1 AND currency = $2",
+		},
+		{
+			name:  "literals replaced",
+			query: "UPDATE transfers SET status = 'completed', note = 'it''s paid' WHERE amount > 100.50 LIMIT 10",
+			want:  "UPDATE transfers SET status = ?, note = ? WHERE amount > ? LIMIT ?",
+		},
+		{
+			name:  "identifiers with digits kept",
+			query: "SELECT a1.id FROM accounts a1 WHERE a1.balance >= 0",
+			want:  "SELECT a1.id FROM accounts a1 WHERE a1.balance >= ?",
+		},
+		{
+			name:  "whitespace collapsed",
+			query: "\n\tSELECT 1\n\t\tFROM  schema_migrations\n",
+			want:  "SELECT ? FROM schema_migrations",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := SanitizeStatement(tc.query); got != tc.want {
+				t.Errorf("SanitizeStatement() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOpenDB(t *testing.T) {
+	recorder := recordSpans(t)
+	_, mock, err := sqlmock.NewWithDSN("tracing_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := OpenDB("sqlmock", "tracing_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT id FROM accounts").WithArgs("fulan1234").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("acc1").AddRow("acc2"))
+	mock.ExpectExec("UPDATE accounts").WithArgs(int64(100), "acc1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM holds").WillReturnError(context.DeadlineExceeded)
+
+	ctx, parent := Start(context.Background(), "parent")
+	rows, err := db.QueryContext(ctx, "SELECT id FROM accounts WHERE owner = $1 AND status = 'open'", "fulan1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rows.Next() {
+	}
+	rows.Close()
+	if _, err := db.ExecContext(ctx, "UPDATE accounts SET balance = balance + $1 WHERE id = $2", int64(100), "acc1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, "DELETE FROM holds"); err == nil {
+		t.Fatal("ExecContext() error = nil")
+	}
+	parent.End()
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 4 {
+		t.Fatalf("got %d spans, want 4", len(spans))
+	}
+	query, update, failed := spans[0], spans[1], spans[2]
+	for _, span := range spans[:3] {
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %s is not a child of the parent span", span.Name())
+		}
+	}
+	if query.Name() != "SELECT" || attr(query, "db.query.text").AsString() != "SELECT id FROM accounts WHERE owner = $1 AND status = ?" {
+		t.Errorf("query span = %s %v", query.Name(), query.Attributes())
+	}
+	if got := attr(query, returnedRowsKey).AsInt64(); got != 2 {
+		t.Errorf("returned rows = %d, want 2", got)
+	}
+	if update.Name() != "UPDATE" || attr(update, rowsAffectedKey).AsInt64() != 1 {
+		t.Errorf("update span = %s %v", update.Name(), update.Attributes())
+	}
+	if failed.Status().Code != codes.Error {
+		t.Errorf("failed span status = %v, want error", failed.Status())
+	}
+}
@@ -2,59 +2,183 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"io"
+	"time"
 
+	"github.com/lib/pq"
+	"github.com/terajari/bank-api/apperror"
 	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/logging"
 	"github.com/terajari/bank-api/model"
 	"github.com/terajari/bank-api/repository"
+	"github.com/terajari/bank-api/statement"
 	"github.com/terajari/bank-api/utils"
 )
 
+// accountNumberAttempts bounds the retries when a generated account number
+// is already taken.
+const accountNumberAttempts = 3
+
+var (
+	ErrUnsupportedAccountType = apperror.New(apperror.KindInvalid, "unsupported_account_type", "unsupported account type")
+	ErrInvalidStatementRange  = apperror.New(apperror.KindInvalid, "invalid_statement_range", "statement start date must not be after its end date")
+)
+
 type AccountsUsecase interface {
 	RegisterNewAccounts(ctx context.Context, req dto.RegisterNewAccountsRequest) (dto.RegisterNewAccountsResponse, error)
+	OpenFundedAccount(ctx context.Context, req dto.OpenFundedAccountRequest) (dto.RegisterNewAccountsResponse, error)
 	GetAccount(ctx context.Context, id string) (dto.GetAccountResponse, error)
 	ListAccounts(ctx context.Context, req dto.ListAccountsRequest) ([]dto.GetAccountResponse, error)
 	UpdateAccount(ctx context.Context, req dto.UpdateAccountRequest) (dto.UpdateAccountResponse, error)
+	UpdateNickname(ctx context.Context, req dto.UpdateAccountNicknameRequest) (dto.UpdateAccountResponse, error)
 	DeleteAccount(ctx context.Context, id string) error
+	SetFrozen(ctx context.Context, id string, frozen bool) (dto.GetAccountResponse, error)
+	ExportStatement(ctx context.Context, req dto.StatementRequest, w io.Writer) error
 }
 
 type accountsUsecase struct {
-	repo repository.AccountsRepository
+	repo        repository.AccountsRepository
+	typesRepo   repository.AccountTypesRepository
+	entriesRepo repository.EntryRepository
+	uow         UnitOfWork
 }
 
-func NewAccountsUsecase(repo repository.AccountsRepository) AccountsUsecase {
-	return &accountsUsecase{repo: repo}
+func NewAccountsUsecase(repo repository.AccountsRepository, typesRepo repository.AccountTypesRepository, entriesRepo repository.EntryRepository, uow UnitOfWork) AccountsUsecase {
+	return &accountsUsecase{repo: repo, typesRepo: typesRepo, entriesRepo: entriesRepo, uow: uow}
 }
 
 func (a *accountsUsecase) RegisterNewAccounts(ctx context.Context, req dto.RegisterNewAccountsRequest) (dto.RegisterNewAccountsResponse, error) {
-	id := utils.GenerateUUID()
-	account, err := a.repo.Create(ctx, model.Accounts{
-		ID:       id,
-		Owner:    req.Owner,
-		Balance:  0,
-		Currency: req.Currency,
-	})
+	return a.register(ctx, req, 0)
+}
+
+// OpenFundedAccount opens an account and transfers req.InitialDeposit to it
+// from the settlement account of its currency in the same unit of work.
+func (a *accountsUsecase) OpenFundedAccount(ctx context.Context, req dto.OpenFundedAccountRequest) (dto.RegisterNewAccountsResponse, error) {
+	return a.register(ctx, req.RegisterNewAccountsRequest, req.InitialDeposit)
+}
+
+func (a *accountsUsecase) register(ctx context.Context, req dto.RegisterNewAccountsRequest, deposit int64) (dto.RegisterNewAccountsResponse, error) {
+	if req.Type == "" {
+		req.Type = model.AccountTypeChecking
+	}
+	accountType, err := a.typesRepo.Get(ctx, req.Type)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return dto.RegisterNewAccountsResponse{}, ErrUnsupportedAccountType
+		}
 		return dto.RegisterNewAccountsResponse{}, err
 	}
+	// System accounts belong to the bank's own ledger.
+	if !accountType.UserSelectable {
+		return dto.RegisterNewAccountsResponse{}, ErrUnsupportedAccountType
+	}
+
+	// A failed insert aborts the transaction, so a taken account number is
+	// retried with a new unit of work.
+	id := utils.GenerateUUID()
+	var account model.Accounts
+	for attempt := 1; ; attempt++ {
+		number, err := utils.GenerateAccountNumber()
+		if err != nil {
+			return dto.RegisterNewAccountsResponse{}, err
+		}
+		err = a.uow.WithTx(ctx, func(repos repository.Repositories) error {
+			var err error
+			account, err = openAccount(ctx, repos, model.Accounts{
+				ID:                id,
+				Number:            number,
+				Owner:             req.Owner,
+				Balance:           0,
+				Currency:          req.Currency,
+				Nickname:          req.Nickname,
+				Type:              accountType.Name,
+				UniquePerCurrency: accountType.UniquePerCurrency,
+			}, deposit)
+			return err
+		})
+		if err == nil {
+			break
+		}
+		if pqErr, ok := err.(*pq.Error); !ok || pqErr.Constraint != "accounts_number_key" || attempt == accountNumberAttempts {
+			return dto.RegisterNewAccountsResponse{}, err
+		}
+	}
 	return dto.RegisterNewAccountsResponse{
-		Id:        account.ID,
-		Owner:     account.Owner,
-		Balance:   account.Balance,
-		Currency:  account.Currency,
-		CreatedAt: account.CreatedAt,
+		Id:               account.ID,
+		Number:           account.Number,
+		Owner:            account.Owner,
+		Balance:          account.Balance,
+		AvailableBalance: account.AvailableBalance(),
+		Currency:         account.Currency,
+		Nickname:         account.Nickname,
+		Type:             account.Type,
+		CreatedAt:        account.CreatedAt,
 	}, nil
 }
 
+// openAccount creates the account and funds it with deposit, if any, by a
+// transfer from the settlement account of its currency.
+func openAccount(ctx context.Context, repos repository.Repositories, account model.Accounts, deposit int64) (model.Accounts, error) {
+	created, err := repos.AccountsRepo().Create(ctx, account)
+	if err != nil || deposit == 0 {
+		return created, err
+	}
+
+	response, err := repos.TransferRepo().TransferTx(ctx, repository.TransferTxParams{
+		Transfer: model.Transfer{
+			ID:          utils.GenerateUUID(),
+			SenderId:    model.SettlementAccountID(created.Currency),
+			ReceiverId:  created.ID,
+			Amount:      deposit,
+			Description: "Initial deposit",
+		},
+	})
+	if err != nil {
+		return model.Accounts{}, err
+	}
+	funded := response.Receiver
+	funded.CreatedAt = created.CreatedAt
+	return funded, nil
+}
+
+// getAccount looks an account up by its ID or by its account number. An
+// account number of the wrong length or with wrong check digits is rejected
+// without a lookup.
+func getAccount(ctx context.Context, repo repository.AccountsRepository, ref string) (model.Accounts, error) {
+	var account model.Accounts
+	var err error
+	if utils.IsAccountNumber(ref) {
+		var number string
+		number, err = utils.ParseAccountNumber(ref)
+		if err != nil {
+			return model.Accounts{}, err
+		}
+		account, err = repo.GetByNumber(ctx, number)
+	} else {
+		account, err = repo.Get(ctx, ref)
+	}
+	if err == sql.ErrNoRows {
+		return model.Accounts{}, apperror.ErrAccountNotFound
+	}
+	return account, err
+}
+
 func (a *accountsUsecase) GetAccount(ctx context.Context, id string) (dto.GetAccountResponse, error) {
-	account, err := a.repo.Get(ctx, id)
+	account, err := getAccount(ctx, a.repo, id)
 	if err != nil {
 		return dto.GetAccountResponse{}, err
 	}
 	return dto.GetAccountResponse{
-		Id:       account.ID,
-		Owner:    account.Owner,
-		Balance:  account.Balance,
-		Currency: account.Currency,
+		Id:               account.ID,
+		Number:           account.Number,
+		Owner:            account.Owner,
+		Balance:          account.Balance,
+		AvailableBalance: account.AvailableBalance(),
+		Currency:         account.Currency,
+		Nickname:         account.Nickname,
+		Type:             account.Type,
+		FrozenAt:         account.FrozenAt,
 	}, nil
 }
 
@@ -64,24 +188,32 @@ func (a *accountsUsecase) ListAccounts(ctx context.Context, req dto.ListAccounts
 		size = 5
 	}
 	page := (req.Page - 1) * size
-	accounts, err := a.repo.List(ctx, req.Owner, size, page)
+	accounts, err := a.repo.List(ctx, model.AccountsFilter{
+		Owner:    req.Owner,
+		Currency: req.Currency,
+		Type:     req.Type,
+	}, size, page)
 	if err != nil {
 		return []dto.GetAccountResponse{}, err
 	}
 	var accountsDto []dto.GetAccountResponse
 	for _, account := range accounts {
 		accountsDto = append(accountsDto, dto.GetAccountResponse{
-			Id:       account.ID,
-			Owner:    account.Owner,
-			Balance:  account.Balance,
-			Currency: account.Currency,
+			Id:               account.ID,
+			Number:           account.Number,
+			Owner:            account.Owner,
+			Balance:          account.Balance,
+			AvailableBalance: account.AvailableBalance(),
+			Currency:         account.Currency,
+			Nickname:         account.Nickname,
+			Type:             account.Type,
 		})
 	}
 	return accountsDto, nil
 }
 
 func (a *accountsUsecase) UpdateAccount(ctx context.Context, req dto.UpdateAccountRequest) (dto.UpdateAccountResponse, error) {
-	acc, err := a.repo.Get(ctx, req.Id)
+	acc, err := getAccount(ctx, a.repo, req.Id)
 	if err != nil {
 		return dto.UpdateAccountResponse{}, err
 	}
@@ -95,17 +227,122 @@ func (a *accountsUsecase) UpdateAccount(ctx context.Context, req dto.UpdateAccou
 		return dto.UpdateAccountResponse{}, err
 	}
 	return dto.UpdateAccountResponse{
-		Id:       updatedAccount.ID,
-		Owner:    updatedAccount.Owner,
-		Balance:  updatedAccount.Balance,
-		Currency: updatedAccount.Currency,
+		Id:               updatedAccount.ID,
+		Number:           updatedAccount.Number,
+		Owner:            updatedAccount.Owner,
+		Balance:          updatedAccount.Balance,
+		AvailableBalance: updatedAccount.AvailableBalance(),
+		Currency:         updatedAccount.Currency,
+		Nickname:         updatedAccount.Nickname,
+		Type:             updatedAccount.Type,
+	}, nil
+}
+
+func (a *accountsUsecase) UpdateNickname(ctx context.Context, req dto.UpdateAccountNicknameRequest) (dto.UpdateAccountResponse, error) {
+	updatedAccount, err := a.repo.UpdateNickname(ctx, req.Id, req.Nickname)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return dto.UpdateAccountResponse{}, apperror.ErrAccountNotFound
+		}
+		return dto.UpdateAccountResponse{}, err
+	}
+	return dto.UpdateAccountResponse{
+		Id:               updatedAccount.ID,
+		Number:           updatedAccount.Number,
+		Owner:            updatedAccount.Owner,
+		Balance:          updatedAccount.Balance,
+		AvailableBalance: updatedAccount.AvailableBalance(),
+		Currency:         updatedAccount.Currency,
+		Nickname:         updatedAccount.Nickname,
+		Type:             updatedAccount.Type,
 	}, nil
 }
 
 func (a *accountsUsecase) DeleteAccount(ctx context.Context, id string) error {
-	acc, err := a.repo.Get(ctx, id)
+	acc, err := getAccount(ctx, a.repo, id)
 	if err != nil {
 		return err
 	}
 	return a.repo.Delete(ctx, acc.ID)
 }
+
+// SetFrozen freezes or unfreezes an account, looked up by its ID or account
+// number. Transfers from and to a frozen account are refused.
+func (a *accountsUsecase) SetFrozen(ctx context.Context, id string, frozen bool) (dto.GetAccountResponse, error) {
+	acc, err := getAccount(ctx, a.repo, id)
+	if err != nil {
+		return dto.GetAccountResponse{}, err
+	}
+	account, err := a.repo.SetFrozen(ctx, acc.ID, frozen)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return dto.GetAccountResponse{}, apperror.ErrAccountNotFound
+		}
+		return dto.GetAccountResponse{}, err
+	}
+	logging.FromContext(ctx).Info("account freeze changed", "account_id", account.ID, "frozen", account.Frozen())
+	return dto.GetAccountResponse{
+		Id:               account.ID,
+		Number:           account.Number,
+		Owner:            account.Owner,
+		Balance:          account.Balance,
+		AvailableBalance: account.AvailableBalance(),
+		Currency:         account.Currency,
+		Nickname:         account.Nickname,
+		Type:             account.Type,
+		FrozenAt:         account.FrozenAt,
+	}, nil
+}
+
+// ExportStatement writes the statement for req to w in the requested format.
+// Nothing is written to w before the account and balances have been read, so
+// a caller can still report an error when w is untouched.
+func (a *accountsUsecase) ExportStatement(ctx context.Context, req dto.StatementRequest, w io.Writer) error {
+	now := time.Now().UTC()
+	if req.Format == "" {
+		req.Format = statement.FormatCSV
+	}
+	if req.From.IsZero() {
+		req.From = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	if req.To.IsZero() {
+		req.To = now.Truncate(24 * time.Hour)
+	}
+	if req.To.Before(req.From) {
+		return ErrInvalidStatementRange
+	}
+	to := req.To.AddDate(0, 0, 1)
+
+	account, err := getAccount(ctx, a.repo, req.AccountId)
+	if err != nil {
+		return err
+	}
+	writer, err := statement.New(req.Format, w)
+	if err != nil {
+		return err
+	}
+
+	var balance int64
+	begin := func(opening, closing int64) error {
+		balance = opening
+		return writer.Begin(model.StatementHeader{
+			AccountId:      account.ID,
+			Owner:          account.Owner,
+			Currency:       account.Currency,
+			From:           req.From,
+			To:             to,
+			OpeningBalance: opening,
+			ClosingBalance: closing,
+			GeneratedAt:    now,
+		})
+	}
+	each := func(entry model.StatementEntry) error {
+		balance += entry.Amount
+		entry.Balance = balance
+		return writer.Entry(entry)
+	}
+	if err := a.entriesRepo.StreamStatement(ctx, account.ID, req.From, to, begin, each); err != nil {
+		return err
+	}
+	return writer.End()
+}
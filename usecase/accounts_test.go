@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/terajari/bank-api/apperror"
+	"github.com/terajari/bank-api/dto"
+	mockrepo "github.com/terajari/bank-api/mock/repository"
+	mockusecase "github.com/terajari/bank-api/mock/usecase"
+	"github.com/terajari/bank-api/model"
+	"github.com/terajari/bank-api/repository"
+	"github.com/terajari/bank-api/utils"
+)
+
+func TestRegisterNewAccounts(t *testing.T) {
+	savings := model.AccountType{Name: model.AccountTypeSavings, UserSelectable: true}
+	created := model.Accounts{ID: "acc1", Number: "4312345678901234", Owner: "fulan1234", Currency: "IDR", Type: model.AccountTypeSavings}
+
+	type mocks struct {
+		uow      *mockusecase.MockUnitOfWork
+		repos    *mockrepo.MockRepositories
+		accounts *mockrepo.MockAccountsRepository
+		types    *mockrepo.MockAccountTypesRepository
+	}
+	runTx := func(m mocks) {
+		m.uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(repository.Repositories) error) error {
+				return fn(m.repos)
+			})
+	}
+
+	testCases := []struct {
+		name    string
+		setup   func(m mocks)
+		wantErr error
+	}{
+		{
+			name: "opened",
+			setup: func(m mocks) {
+				m.types.EXPECT().Get(gomock.Any(), model.AccountTypeSavings).Return(savings, nil)
+				runTx(m)
+				m.accounts.EXPECT().Create(gomock.Any(), gomock.Any()).Return(created, nil)
+			},
+		},
+		{
+			name: "account number taken",
+			setup: func(m mocks) {
+				m.types.EXPECT().Get(gomock.Any(), model.AccountTypeSavings).Return(savings, nil)
+				runTx(m)
+				m.accounts.EXPECT().Create(gomock.Any(), gomock.Any()).Return(model.Accounts{}, &pq.Error{Code: "23505", Constraint: "accounts_number_key"})
+				runTx(m)
+				m.accounts.EXPECT().Create(gomock.Any(), gomock.Any()).Return(created, nil)
+			},
+		},
+		{
+			name: "type not selectable by customers",
+			setup: func(m mocks) {
+				m.types.EXPECT().Get(gomock.Any(), model.AccountTypeSavings).Return(model.AccountType{Name: model.AccountTypeSavings}, nil)
+			},
+			wantErr: ErrUnsupportedAccountType,
+		},
+		{
+			name: "unsupported account type",
+			setup: func(m mocks) {
+				m.types.EXPECT().Get(gomock.Any(), model.AccountTypeSavings).Return(model.AccountType{}, sql.ErrNoRows)
+			},
+			wantErr: ErrUnsupportedAccountType,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks{
+				uow:      mockusecase.NewMockUnitOfWork(ctrl),
+				repos:    mockrepo.NewMockRepositories(ctrl),
+				accounts: mockrepo.NewMockAccountsRepository(ctrl),
+				types:    mockrepo.NewMockAccountTypesRepository(ctrl),
+			}
+			m.repos.EXPECT().AccountsRepo().Return(m.accounts).AnyTimes()
+			tc.setup(m)
+
+			uc := NewAccountsUsecase(mockrepo.NewMockAccountsRepository(ctrl), m.types, mockrepo.NewMockEntryRepository(ctrl), m.uow)
+			got, err := uc.RegisterNewAccounts(context.Background(), dto.RegisterNewAccountsRequest{
+				Owner:    "fulan1234",
+				Currency: "IDR",
+				Type:     model.AccountTypeSavings,
+			})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("RegisterNewAccounts() error = %v, want %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Id != "acc1" || got.Balance != 0 {
+				t.Errorf("RegisterNewAccounts() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestOpenFundedAccount(t *testing.T) {
+	savings := model.AccountType{Name: model.AccountTypeSavings, UserSelectable: true}
+	created := model.Accounts{ID: "acc1", Number: "4312345678901234", Owner: "fulan1234", Currency: "IDR", Type: model.AccountTypeSavings}
+	funded := created
+	funded.Balance = 5000
+
+	type mocks struct {
+		uow       *mockusecase.MockUnitOfWork
+		repos     *mockrepo.MockRepositories
+		accounts  *mockrepo.MockAccountsRepository
+		types     *mockrepo.MockAccountTypesRepository
+		transfers *mockrepo.MockTransferRepository
+	}
+
+	testCases := []struct {
+		name        string
+		deposit     int64
+		setup       func(m mocks)
+		wantBalance int64
+		wantErr     error
+	}{
+		{
+			name:    "deposit from the settlement account",
+			deposit: 5000,
+			setup: func(m mocks) {
+				m.accounts.EXPECT().Create(gomock.Any(), gomock.Any()).Return(created, nil)
+				m.transfers.EXPECT().TransferTx(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, arg repository.TransferTxParams) (dto.MakeTransferResponse, error) {
+						tr := arg.Transfer
+						if tr.ID == "" || tr.SenderId != model.SettlementAccountID("IDR") || tr.ReceiverId != "acc1" || tr.Amount != 5000 {
+							t.Errorf("transfer = %+v", tr)
+						}
+						return dto.MakeTransferResponse{Transfer: tr, Receiver: funded}, nil
+					})
+			},
+			wantBalance: 5000,
+		},
+		{
+			name: "without deposit",
+			setup: func(m mocks) {
+				m.accounts.EXPECT().Create(gomock.Any(), gomock.Any()).Return(created, nil)
+			},
+		},
+		{
+			name:    "failed deposit",
+			deposit: 5000,
+			setup: func(m mocks) {
+				m.accounts.EXPECT().Create(gomock.Any(), gomock.Any()).Return(created, nil)
+				m.transfers.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Return(dto.MakeTransferResponse{}, sql.ErrConnDone)
+			},
+			wantErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks{
+				uow:       mockusecase.NewMockUnitOfWork(ctrl),
+				repos:     mockrepo.NewMockRepositories(ctrl),
+				accounts:  mockrepo.NewMockAccountsRepository(ctrl),
+				types:     mockrepo.NewMockAccountTypesRepository(ctrl),
+				transfers: mockrepo.NewMockTransferRepository(ctrl),
+			}
+			m.types.EXPECT().Get(gomock.Any(), model.AccountTypeSavings).Return(savings, nil)
+			m.uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(repository.Repositories) error) error {
+					return fn(m.repos)
+				})
+			m.repos.EXPECT().AccountsRepo().Return(m.accounts).AnyTimes()
+			m.repos.EXPECT().TransferRepo().Return(m.transfers).AnyTimes()
+			tc.setup(m)
+
+			uc := NewAccountsUsecase(mockrepo.NewMockAccountsRepository(ctrl), m.types, mockrepo.NewMockEntryRepository(ctrl), m.uow)
+			req := dto.OpenFundedAccountRequest{InitialDeposit: tc.deposit}
+			req.Owner, req.Currency, req.Type = "fulan1234", "IDR", model.AccountTypeSavings
+			got, err := uc.OpenFundedAccount(context.Background(), req)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("OpenFundedAccount() error = %v, want %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Id != "acc1" || got.Balance != tc.wantBalance {
+				t.Errorf("OpenFundedAccount() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestSetFrozen(t *testing.T) {
+	frozenAt := time.Date(2023, 11, 9, 9, 45, 0, 0, time.UTC)
+	account := model.Accounts{ID: "acc1", Number: "4312345678901234", Owner: "fulan1234", Currency: "IDR"}
+	frozen := account
+	frozen.FrozenAt = &frozenAt
+
+	testCases := []struct {
+		name    string
+		ref     string
+		setup   func(m *mockrepo.MockAccountsRepository)
+		want    *time.Time
+		wantErr error
+	}{
+		{
+			name: "freeze",
+			ref:  "acc1",
+			setup: func(m *mockrepo.MockAccountsRepository) {
+				m.EXPECT().Get(gomock.Any(), "acc1").Return(account, nil)
+				m.EXPECT().SetFrozen(gomock.Any(), "acc1", true).Return(frozen, nil)
+			},
+			want: &frozenAt,
+		},
+		{
+			name: "by account number",
+			ref:  "ID43 1234 5678 9012 34",
+			setup: func(m *mockrepo.MockAccountsRepository) {
+				m.EXPECT().GetByNumber(gomock.Any(), "ID4312345678901234").Return(account, nil)
+				m.EXPECT().SetFrozen(gomock.Any(), "acc1", true).Return(frozen, nil)
+			},
+			want: &frozenAt,
+		},
+		{
+			name:    "account number with a digit missing",
+			ref:     "ID431234567890123",
+			setup:   func(m *mockrepo.MockAccountsRepository) {},
+			wantErr: utils.ErrInvalidAccountNumber,
+		},
+		{
+			name: "unknown account",
+			ref:  "acc2",
+			setup: func(m *mockrepo.MockAccountsRepository) {
+				m.EXPECT().Get(gomock.Any(), "acc2").Return(model.Accounts{}, sql.ErrNoRows)
+			},
+			wantErr: apperror.ErrAccountNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			accounts := mockrepo.NewMockAccountsRepository(ctrl)
+			tc.setup(accounts)
+
+			uc := NewAccountsUsecase(accounts, mockrepo.NewMockAccountTypesRepository(ctrl), mockrepo.NewMockEntryRepository(ctrl), mockusecase.NewMockUnitOfWork(ctrl))
+			got, err := uc.SetFrozen(context.Background(), tc.ref, true)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("SetFrozen() error = %v, want %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Id != "acc1" || got.FrozenAt == nil || !got.FrozenAt.Equal(*tc.want) {
+				t.Errorf("SetFrozen() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestCheckNotFrozen(t *testing.T) {
+	frozenAt := time.Now()
+	open := model.Accounts{ID: "acc1"}
+	frozen := model.Accounts{ID: "acc2", FrozenAt: &frozenAt}
+
+	if err := checkNotFrozen(open, open); err != nil {
+		t.Errorf("checkNotFrozen(open, open) = %v", err)
+	}
+	if err := checkNotFrozen(open, frozen); !errors.Is(err, apperror.ErrAccountFrozen) {
+		t.Errorf("checkNotFrozen(open, frozen) = %v, want %v", err, apperror.ErrAccountFrozen)
+	}
+}
@@ -0,0 +1,367 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/terajari/bank-api/apperror"
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/logging"
+	"github.com/terajari/bank-api/metrics"
+	"github.com/terajari/bank-api/model"
+	"github.com/terajari/bank-api/repository"
+	"github.com/terajari/bank-api/utils"
+)
+
+var ErrBatchJobNotFound = apperror.New(apperror.KindNotFound, "batch_job_not_found", "batch job not found")
+
+// MakeTransferBatch sends every item from req.SenderId. In best_effort mode
+// each item is an independent MakeTransfer. In all_or_nothing mode every item
+// is validated first and the transfers are committed in one transaction, so a
+// single failure leaves all other items skipped.
+func (t *transferUsecase) MakeTransferBatch(ctx context.Context, req dto.BatchTransferRequest) (dto.BatchTransferResult, error) {
+	return t.ResumeTransferBatch(ctx, req, nil, nil)
+}
+
+// ResumeTransferBatch is MakeTransferBatch for batch jobs. The items of a job
+// get transfer IDs derived from req.JobId, so an item whose transfer an
+// earlier run already made is reported from that transfer instead of being
+// sent again. In best_effort mode the outcomes in done, the progress of an
+// earlier run, are kept (skipped items are sent), and progress, when set, is
+// called with the result after every item.
+func (t *transferUsecase) ResumeTransferBatch(ctx context.Context, req dto.BatchTransferRequest, done []dto.BatchItemResult, progress func(dto.BatchTransferResult)) (dto.BatchTransferResult, error) {
+	sender, err := getAccount(ctx, t.accountRepo, req.SenderId)
+	if err != nil {
+		return dto.BatchTransferResult{}, err
+	}
+	if req.Owner != "" && sender.Owner != req.Owner {
+		return dto.BatchTransferResult{}, ErrNotAccountOwner
+	}
+
+	result := dto.BatchTransferResult{
+		Mode:     req.Mode,
+		SenderId: sender.ID,
+		Total:    len(req.Items),
+		Items:    make([]dto.BatchItemResult, len(req.Items)),
+	}
+	requests := make([]dto.MakeTransferRequest, len(req.Items))
+	for i, item := range req.Items {
+		result.Items[i] = dto.BatchItemResult{Index: i, ReceiverId: item.ReceiverId, PayeeId: item.PayeeId, Amount: item.Amount}
+		requests[i] = dto.MakeTransferRequest{
+			SenderId:        sender.ID,
+			ReceiverId:      item.ReceiverId,
+			PayeeId:         item.PayeeId,
+			Amount:          item.Amount,
+			Currency:        req.Currency,
+			TransferDetails: item.TransferDetails,
+			Owner:           req.Owner,
+		}
+		if req.JobId != "" {
+			requests[i].TransferId = batchItemTransferId(req.JobId, i)
+		}
+	}
+
+	switch req.Mode {
+	case model.BatchModeBestEffort:
+		for i := range done {
+			if i < len(result.Items) && done[i].Status != "" && done[i].Status != model.BatchItemSkipped {
+				result.Items[i] = done[i]
+			}
+		}
+		for i, request := range requests {
+			if result.Items[i].Status != "" {
+				continue
+			}
+			if ctx.Err() != nil {
+				break
+			}
+			resp, err := t.sendBatchItem(ctx, request)
+			switch {
+			case err == nil:
+				completeItem(&result.Items[i], resp)
+			case ctx.Err() != nil:
+				// Interrupted, the item is sent again when the job resumes.
+				continue
+			default:
+				failItem(&result.Items[i], err)
+			}
+			if progress != nil {
+				countItems(&result)
+				progress(result)
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			skipPending(result.Items)
+			result.Error = err.Error()
+		}
+	case model.BatchModeAllOrNothing:
+		if err := t.transferAllOrNothing(ctx, sender, requests, &result); err != nil {
+			return dto.BatchTransferResult{}, err
+		}
+	default:
+		return dto.BatchTransferResult{}, apperror.ErrInvalidRequest.Withf("unsupported batch mode %q", req.Mode)
+	}
+
+	countItems(&result)
+	return result, nil
+}
+
+// batchItemTransferId is the ID of the transfer of item index of a batch job.
+func batchItemTransferId(jobId string, index int) string {
+	return utils.NameUUID(fmt.Sprintf("batch/%s/%d", jobId, index))
+}
+
+// sendBatchItem makes the transfer of a batch item unless an earlier run of
+// the job already made it.
+func (t *transferUsecase) sendBatchItem(ctx context.Context, request dto.MakeTransferRequest) (dto.MakeTransferResponse, error) {
+	if request.TransferId != "" {
+		transfer, err := t.transferRepo.Get(ctx, request.TransferId)
+		if err == nil {
+			return dto.MakeTransferResponse{Transfer: transfer}, nil
+		}
+		if err != sql.ErrNoRows {
+			return dto.MakeTransferResponse{}, err
+		}
+	}
+	return t.MakeTransfer(ctx, request)
+}
+
+func countItems(result *dto.BatchTransferResult) {
+	result.Succeeded, result.Failed = 0, 0
+	for _, item := range result.Items {
+		if item.Status == model.BatchItemFailed {
+			result.Failed++
+		} else if item.Status != "" && item.Status != model.BatchItemSkipped {
+			result.Succeeded++
+		}
+	}
+}
+
+func (t *transferUsecase) transferAllOrNothing(ctx context.Context, sender model.Accounts, requests []dto.MakeTransferRequest, result *dto.BatchTransferResult) error {
+	// The transfers are committed together, if the first one exists an
+	// earlier run of the job made all of them.
+	if len(requests) > 0 && requests[0].TransferId != "" {
+		_, err := t.transferRepo.Get(ctx, requests[0].TransferId)
+		switch {
+		case err == nil:
+			for i, request := range requests {
+				resp, err := t.sendBatchItem(ctx, request)
+				if err != nil {
+					return err
+				}
+				completeItem(&result.Items[i], resp)
+			}
+			return nil
+		case err != sql.ErrNoRows:
+			return err
+		}
+	}
+
+	args := make([]repository.TransferTxParams, 0, len(requests))
+	var total int64
+	failed := false
+	for i, request := range requests {
+		arg, totalFee, err := t.prepareTransfer(ctx, sender, request)
+		if err != nil {
+			failItem(&result.Items[i], err)
+			failed = true
+			continue
+		}
+		args = append(args, arg)
+		total += request.Amount + totalFee
+	}
+	if !failed && sender.AvailableBalance() < total {
+		result.Error = fmt.Sprintf("insufficient balance: %d < %d", sender.AvailableBalance(), total)
+		failed = true
+	}
+	if failed {
+		skipPending(result.Items)
+		return nil
+	}
+
+	responses, err := t.transferRepo.TransferBatchTx(ctx, args)
+	if err != nil {
+		var itemErr *repository.BatchItemError
+		if !errors.As(err, &itemErr) {
+			return err
+		}
+		metrics.ObserveTransfer(sender.Currency, "", 0, itemErr.Err)
+		failItem(&result.Items[itemErr.Index], itemErr.Err)
+		skipPending(result.Items)
+		return nil
+	}
+	for i, resp := range responses {
+		metrics.ObserveTransfer(sender.Currency, resp.Transfer.Status, resp.Transfer.Amount, nil)
+		completeItem(&result.Items[i], resp)
+	}
+	return nil
+}
+
+func completeItem(item *dto.BatchItemResult, resp dto.MakeTransferResponse) {
+	item.Status = resp.Transfer.Status
+	item.TransferId = resp.Transfer.ID
+	item.ReceiverId = resp.Transfer.ReceiverId
+	item.TotalFee = resp.TotalFee
+}
+
+func failItem(item *dto.BatchItemResult, err error) {
+	item.Status = model.BatchItemFailed
+	item.Error = err.Error()
+}
+
+func skipPending(items []dto.BatchItemResult) {
+	for i := range items {
+		if items[i].Status == "" {
+			items[i].Status = model.BatchItemSkipped
+		}
+	}
+}
+
+type BatchUsecase interface {
+	Enqueue(ctx context.Context, req dto.BatchTransferRequest) (model.BatchJob, error)
+	GetJob(ctx context.Context, id, owner string) (model.BatchJob, error)
+	ProcessNext(ctx context.Context) (bool, error)
+	RequeueStale(ctx context.Context) (int, int, error)
+}
+
+type batchUsecase struct {
+	repo     repository.BatchJobsRepository
+	transfer TransferUsecase
+	config   *utils.Config
+}
+
+func NewBatchUsecase(repo repository.BatchJobsRepository, transfer TransferUsecase, cfg *utils.Config) BatchUsecase {
+	return &batchUsecase{
+		repo:     repo,
+		transfer: transfer,
+		config:   cfg,
+	}
+}
+
+// Enqueue stores the batch for the background worker. The TOTP code, if any,
+// has already been checked and is not persisted; the owner is kept on the job.
+func (b *batchUsecase) Enqueue(ctx context.Context, req dto.BatchTransferRequest) (model.BatchJob, error) {
+	req.TotpCode = ""
+	request, err := json.Marshal(req)
+	if err != nil {
+		return model.BatchJob{}, err
+	}
+	return b.repo.Create(ctx, model.BatchJob{
+		ID:        utils.GenerateUUID(),
+		Owner:     req.Owner,
+		SenderId:  req.SenderId,
+		Mode:      req.Mode,
+		ItemCount: len(req.Items),
+		Request:   request,
+	})
+}
+
+// GetJob returns the job only to the user who submitted it.
+func (b *batchUsecase) GetJob(ctx context.Context, id, owner string) (model.BatchJob, error) {
+	job, err := b.repo.Get(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.BatchJob{}, ErrBatchJobNotFound
+		}
+		return model.BatchJob{}, err
+	}
+	if job.Owner != owner {
+		return model.BatchJob{}, ErrBatchJobNotFound
+	}
+	return job, nil
+}
+
+// ProcessNext runs the oldest queued job. It reports false when there was
+// nothing to do. The job's lease is renewed while it runs and its progress is
+// stored after every best_effort item. A job interrupted by ctx, e.g. on
+// shutdown, is queued again and resumed from its progress.
+func (b *batchUsecase) ProcessNext(ctx context.Context) (bool, error) {
+	job, err := b.repo.ClaimNext(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	finishCtx := context.WithoutCancel(ctx)
+	logger := logging.FromContext(ctx).With("job_id", job.ID)
+
+	var req dto.BatchTransferRequest
+	if err := json.Unmarshal(job.Request, &req); err != nil {
+		return true, b.repo.Finish(finishCtx, job.ID, model.BatchJobFailed, nil, err.Error())
+	}
+	req.Owner = job.Owner
+	req.JobId = job.ID
+	var done dto.BatchTransferResult
+	if job.Result != nil {
+		if err := json.Unmarshal(job.Result, &done); err != nil {
+			return true, b.repo.Finish(finishCtx, job.ID, model.BatchJobFailed, nil, err.Error())
+		}
+	}
+
+	stop := b.keepAlive(finishCtx, job.ID)
+	result, err := b.transfer.ResumeTransferBatch(ctx, req, done.Items, func(progress dto.BatchTransferResult) {
+		out, err := json.Marshal(progress)
+		if err == nil {
+			err = b.repo.Heartbeat(finishCtx, job.ID, out)
+		}
+		if err != nil {
+			logger.Warn("batch: cannot store progress", "error", err)
+		}
+	})
+	stop()
+
+	if ctx.Err() != nil {
+		var out json.RawMessage
+		if err == nil {
+			if out, err = json.Marshal(result); err != nil {
+				return true, err
+			}
+		}
+		return true, b.repo.Requeue(finishCtx, job.ID, out)
+	}
+	if err != nil {
+		return true, b.repo.Finish(finishCtx, job.ID, model.BatchJobFailed, nil, err.Error())
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return true, err
+	}
+	return true, b.repo.Finish(finishCtx, job.ID, model.BatchJobCompleted, out, "")
+}
+
+// keepAlive renews the lease of the job until stop is called.
+func (b *batchUsecase) keepAlive(ctx context.Context, id string) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(b.config.BatchJobLease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := b.repo.Heartbeat(ctx, id, nil); err != nil && ctx.Err() == nil {
+					logging.FromContext(ctx).Warn("batch: cannot renew lease", "job_id", id, "error", err)
+				}
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-stopped
+	}
+}
+
+// RequeueStale queues the running jobs whose worker stopped renewing their
+// lease again, or fails them after BATCH_JOB_MAX_ATTEMPTS claims. It returns
+// the number of requeued and failed jobs.
+func (b *batchUsecase) RequeueStale(ctx context.Context) (int, int, error) {
+	return b.repo.RequeueStale(ctx, time.Now().Add(-b.config.BatchJobLease), b.config.BatchJobMaxAttempts)
+}
@@ -0,0 +1,297 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/terajari/bank-api/dto"
+	mockrepo "github.com/terajari/bank-api/mock/repository"
+	mockusecase "github.com/terajari/bank-api/mock/usecase"
+	"github.com/terajari/bank-api/model"
+	"github.com/terajari/bank-api/repository"
+	"github.com/terajari/bank-api/utils"
+)
+
+func TestMakeTransferBatch(t *testing.T) {
+	sender := model.Accounts{ID: "acc1", Owner: "fulan", Balance: 10000, Currency: "IDR"}
+	receivers := map[string]model.Accounts{
+		"acc2": {ID: "acc2", Owner: "other", Currency: "IDR"},
+		"acc3": {ID: "acc3", Owner: "other", Currency: "IDR"},
+		"acc4": {ID: "acc4", Owner: "other", Currency: "IDR", FrozenAt: &time.Time{}},
+	}
+	items := []dto.BatchTransferItem{{ReceiverId: "acc2", Amount: 500}, {ReceiverId: "acc3", Amount: 700}}
+
+	type mocks struct {
+		accounts  *mockrepo.MockAccountsRepository
+		transfers *mockrepo.MockTransferRepository
+	}
+	sent := func(arg repository.TransferTxParams) dto.MakeTransferResponse {
+		return dto.MakeTransferResponse{Transfer: model.Transfer{ID: arg.Transfer.ID, SenderId: arg.Transfer.SenderId, ReceiverId: arg.Transfer.ReceiverId, Amount: arg.Transfer.Amount, Status: model.TransferStatusCompleted}}
+	}
+
+	testCases := []struct {
+		name          string
+		req           dto.BatchTransferRequest
+		done          []dto.BatchItemResult
+		setup         func(m mocks)
+		wantStatuses  []string
+		wantSucceeded int
+		wantFailed    int
+		wantProgress  int
+	}{
+		{
+			name: "best effort keeps going after a failed item",
+			req:  dto.BatchTransferRequest{SenderId: "acc1", Mode: model.BatchModeBestEffort, Items: items},
+			setup: func(m mocks) {
+				m.transfers.EXPECT().TransferTx(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, arg repository.TransferTxParams) (dto.MakeTransferResponse, error) {
+						return sent(arg), nil
+					})
+				m.transfers.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Return(dto.MakeTransferResponse{}, repository.ErrInsufficientFunds)
+			},
+			wantStatuses:  []string{model.TransferStatusCompleted, model.BatchItemFailed},
+			wantSucceeded: 1,
+			wantFailed:    1,
+		},
+		{
+			name: "all or nothing commits every item at once",
+			req:  dto.BatchTransferRequest{SenderId: "acc1", Mode: model.BatchModeAllOrNothing, Items: items},
+			setup: func(m mocks) {
+				m.transfers.EXPECT().TransferBatchTx(gomock.Any(), gomock.Len(2)).
+					DoAndReturn(func(_ context.Context, args []repository.TransferTxParams) ([]dto.MakeTransferResponse, error) {
+						return []dto.MakeTransferResponse{sent(args[0]), sent(args[1])}, nil
+					})
+			},
+			wantStatuses:  []string{model.TransferStatusCompleted, model.TransferStatusCompleted},
+			wantSucceeded: 2,
+		},
+		{
+			name: "all or nothing skips every item when one is invalid",
+			req:  dto.BatchTransferRequest{SenderId: "acc1", Mode: model.BatchModeAllOrNothing, Items: []dto.BatchTransferItem{{ReceiverId: "acc2", Amount: 500}, {ReceiverId: "acc4", Amount: 700}}},
+			setup: func(m mocks) {
+				m.transfers.EXPECT().TransferBatchTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantStatuses: []string{model.BatchItemSkipped, model.BatchItemFailed},
+			wantFailed:   1,
+		},
+		{
+			name: "all or nothing reports a failing item",
+			req:  dto.BatchTransferRequest{SenderId: "acc1", Mode: model.BatchModeAllOrNothing, Items: items},
+			setup: func(m mocks) {
+				m.transfers.EXPECT().TransferBatchTx(gomock.Any(), gomock.Any()).
+					Return(nil, &repository.BatchItemError{Index: 0, Err: repository.ErrInsufficientFunds})
+			},
+			wantStatuses: []string{model.BatchItemFailed, model.BatchItemSkipped},
+			wantFailed:   1,
+		},
+		{
+			name: "resumed job sends only the missing items",
+			req:  dto.BatchTransferRequest{JobId: "job1", SenderId: "acc1", Mode: model.BatchModeBestEffort, Items: append(items, dto.BatchTransferItem{ReceiverId: "acc2", Amount: 900})},
+			done: []dto.BatchItemResult{{Index: 0, ReceiverId: "acc2", Amount: 500, Status: model.TransferStatusCompleted, TransferId: batchItemTransferId("job1", 0)}, {Index: 1, Status: model.BatchItemSkipped}},
+			setup: func(m mocks) {
+				// The second item was sent before the worker died, but its
+				// progress was not stored.
+				m.transfers.EXPECT().Get(gomock.Any(), batchItemTransferId("job1", 1)).
+					Return(model.Transfer{ID: batchItemTransferId("job1", 1), ReceiverId: "acc3", Amount: 700, Status: model.TransferStatusCompleted}, nil)
+				m.transfers.EXPECT().Get(gomock.Any(), batchItemTransferId("job1", 2)).Return(model.Transfer{}, sql.ErrNoRows)
+				m.transfers.EXPECT().TransferTx(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, arg repository.TransferTxParams) (dto.MakeTransferResponse, error) {
+						if arg.Transfer.ID != batchItemTransferId("job1", 2) {
+							t.Errorf("transfer ID = %s, want the item's derived ID", arg.Transfer.ID)
+						}
+						return sent(arg), nil
+					})
+			},
+			wantStatuses:  []string{model.TransferStatusCompleted, model.TransferStatusCompleted, model.TransferStatusCompleted},
+			wantSucceeded: 3,
+			wantProgress:  2,
+		},
+		{
+			name: "resumed all or nothing job that was committed",
+			req:  dto.BatchTransferRequest{JobId: "job1", SenderId: "acc1", Mode: model.BatchModeAllOrNothing, Items: items},
+			setup: func(m mocks) {
+				m.transfers.EXPECT().Get(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, id string) (model.Transfer, error) {
+						return model.Transfer{ID: id, Status: model.TransferStatusCompleted}, nil
+					}).Times(3)
+				m.transfers.EXPECT().TransferBatchTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantStatuses:  []string{model.TransferStatusCompleted, model.TransferStatusCompleted},
+			wantSucceeded: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks{
+				accounts:  mockrepo.NewMockAccountsRepository(ctrl),
+				transfers: mockrepo.NewMockTransferRepository(ctrl),
+			}
+			m.accounts.EXPECT().Get(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, id string) (model.Accounts, error) {
+					if id == sender.ID {
+						return sender, nil
+					}
+					return receivers[id], nil
+				}).AnyTimes()
+			m.transfers.EXPECT().HasPaid(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+			m.transfers.EXPECT().RecentActivity(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(0), int64(0), nil).AnyTimes()
+			fees := mockrepo.NewMockFeeRulesRepository(ctrl)
+			fees.EXPECT().ListActive(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			risk := mockusecase.NewMockRiskEvaluator(ctrl)
+			risk.EXPECT().Evaluate(gomock.Any(), gomock.Any()).Return(model.RiskResult{Decision: model.RiskAllow}, nil).AnyTimes()
+			tc.setup(m)
+
+			uc := NewTransferUsecase(m.accounts, nil, m.transfers, fees, nil, nil, risk, &utils.Config{})
+			var progress int
+			result, err := uc.ResumeTransferBatch(context.Background(), tc.req, tc.done, func(dto.BatchTransferResult) { progress++ })
+			if err != nil {
+				t.Fatalf("ResumeTransferBatch() error = %v", err)
+			}
+			if len(result.Items) != len(tc.wantStatuses) {
+				t.Fatalf("Items = %+v", result.Items)
+			}
+			for i, want := range tc.wantStatuses {
+				if result.Items[i].Status != want {
+					t.Errorf("item %d status = %q, want %q (%+v)", i, result.Items[i].Status, want, result.Items[i])
+				}
+			}
+			if result.Succeeded != tc.wantSucceeded || result.Failed != tc.wantFailed {
+				t.Errorf("Succeeded, Failed = %d, %d, want %d, %d", result.Succeeded, result.Failed, tc.wantSucceeded, tc.wantFailed)
+			}
+			if tc.req.Mode == model.BatchModeBestEffort && tc.done == nil {
+				tc.wantProgress = len(tc.req.Items)
+			}
+			if progress != tc.wantProgress {
+				t.Errorf("progress called %d times, want %d", progress, tc.wantProgress)
+			}
+		})
+	}
+}
+
+func TestProcessNext(t *testing.T) {
+	request, _ := json.Marshal(dto.BatchTransferRequest{SenderId: "acc1", Mode: model.BatchModeBestEffort, Items: []dto.BatchTransferItem{{ReceiverId: "acc2", Amount: 500}}})
+	job := model.BatchJob{ID: "job1", Owner: "fulan", Request: request}
+	result := dto.BatchTransferResult{Mode: model.BatchModeBestEffort, Items: []dto.BatchItemResult{{Status: model.TransferStatusCompleted}}, Succeeded: 1}
+	progress, _ := json.Marshal(result)
+
+	testCases := []struct {
+		name          string
+		setup         func(ctx context.Context, cancel context.CancelFunc, repo *mockrepo.MockBatchJobsRepository, transfer *mockusecase.MockTransferUsecase)
+		wantProcessed bool
+		wantErr       bool
+	}{
+		{
+			name: "empty queue",
+			setup: func(_ context.Context, _ context.CancelFunc, repo *mockrepo.MockBatchJobsRepository, _ *mockusecase.MockTransferUsecase) {
+				repo.EXPECT().ClaimNext(gomock.Any()).Return(model.BatchJob{}, sql.ErrNoRows)
+			},
+		},
+		{
+			name: "completes the job and stores its progress",
+			setup: func(_ context.Context, _ context.CancelFunc, repo *mockrepo.MockBatchJobsRepository, transfer *mockusecase.MockTransferUsecase) {
+				repo.EXPECT().ClaimNext(gomock.Any()).Return(job, nil)
+				transfer.EXPECT().ResumeTransferBatch(gomock.Any(), gomock.Any(), gomock.Nil(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, req dto.BatchTransferRequest, _ []dto.BatchItemResult, progress func(dto.BatchTransferResult)) (dto.BatchTransferResult, error) {
+						if req.Owner != "fulan" || req.JobId != "job1" {
+							t.Errorf("request = %+v", req)
+						}
+						progress(result)
+						return result, nil
+					})
+				repo.EXPECT().Heartbeat(gomock.Any(), "job1", json.RawMessage(progress)).Return(nil)
+				repo.EXPECT().Finish(gomock.Any(), "job1", model.BatchJobCompleted, json.RawMessage(progress), "").Return(nil)
+			},
+			wantProcessed: true,
+		},
+		{
+			name: "resumes from the stored progress",
+			setup: func(_ context.Context, _ context.CancelFunc, repo *mockrepo.MockBatchJobsRepository, transfer *mockusecase.MockTransferUsecase) {
+				resumed := job
+				resumed.Result = progress
+				repo.EXPECT().ClaimNext(gomock.Any()).Return(resumed, nil)
+				transfer.EXPECT().ResumeTransferBatch(gomock.Any(), gomock.Any(), result.Items, gomock.Any()).Return(result, nil)
+				repo.EXPECT().Finish(gomock.Any(), "job1", model.BatchJobCompleted, gomock.Any(), "").Return(nil)
+			},
+			wantProcessed: true,
+		},
+		{
+			name: "interrupted job is queued again",
+			setup: func(_ context.Context, cancel context.CancelFunc, repo *mockrepo.MockBatchJobsRepository, transfer *mockusecase.MockTransferUsecase) {
+				repo.EXPECT().ClaimNext(gomock.Any()).Return(job, nil)
+				transfer.EXPECT().ResumeTransferBatch(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(context.Context, dto.BatchTransferRequest, []dto.BatchItemResult, func(dto.BatchTransferResult)) (dto.BatchTransferResult, error) {
+						cancel()
+						return result, nil
+					})
+				repo.EXPECT().Requeue(gomock.Any(), "job1", json.RawMessage(progress)).Return(nil)
+			},
+			wantProcessed: true,
+		},
+		{
+			name: "failed job",
+			setup: func(_ context.Context, _ context.CancelFunc, repo *mockrepo.MockBatchJobsRepository, transfer *mockusecase.MockTransferUsecase) {
+				repo.EXPECT().ClaimNext(gomock.Any()).Return(job, nil)
+				transfer.EXPECT().ResumeTransferBatch(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(dto.BatchTransferResult{}, ErrNotAccountOwner)
+				repo.EXPECT().Finish(gomock.Any(), "job1", model.BatchJobFailed, gomock.Nil(), ErrNotAccountOwner.Error()).Return(nil)
+			},
+			wantProcessed: true,
+		},
+		{
+			name: "claim failed",
+			setup: func(_ context.Context, _ context.CancelFunc, repo *mockrepo.MockBatchJobsRepository, _ *mockusecase.MockTransferUsecase) {
+				repo.EXPECT().ClaimNext(gomock.Any()).Return(model.BatchJob{}, sql.ErrConnDone)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mockrepo.NewMockBatchJobsRepository(ctrl)
+			transfer := mockusecase.NewMockTransferUsecase(ctrl)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			tc.setup(ctx, cancel, repo, transfer)
+
+			uc := NewBatchUsecase(repo, transfer, &utils.Config{BatchJobLease: time.Minute, BatchJobMaxAttempts: 3})
+			processed, err := uc.ProcessNext(ctx)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ProcessNext() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if processed != tc.wantProcessed {
+				t.Errorf("ProcessNext() = %v, want %v", processed, tc.wantProcessed)
+			}
+		})
+	}
+}
+
+func TestRequeueStale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mockrepo.NewMockBatchJobsRepository(ctrl)
+	repo.EXPECT().RequeueStale(gomock.Any(), gomock.Any(), 3).
+		DoAndReturn(func(_ context.Context, staleBefore time.Time, _ int) (int, int, error) {
+			if age := time.Since(staleBefore); age < time.Minute || age > time.Minute+time.Second {
+				t.Errorf("staleBefore is %s ago, want the lease", age)
+			}
+			return 2, 1, nil
+		})
+
+	uc := NewBatchUsecase(repo, nil, &utils.Config{BatchJobLease: time.Minute, BatchJobMaxAttempts: 3})
+	requeued, failed, err := uc.RequeueStale(context.Background())
+	if err != nil || requeued != 2 || failed != 1 {
+		t.Errorf("RequeueStale() = %d, %d, %v", requeued, failed, err)
+	}
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/terajari/bank-api/model"
+	"github.com/terajari/bank-api/utils"
+)
+
+// computeFee returns the fee a rule charges on amount. Percentage fees are
+// rounded half-even in minor units and then clamped to the rule's min/max.
+func computeFee(rule model.FeeRule, amount int64) int64 {
+	var fee int64
+	switch rule.Kind {
+	case model.FeeKindFlat:
+		fee = rule.FlatAmount
+	case model.FeeKindPercentage:
+		fee = utils.MulDivHalfEven(amount, rule.RateBps, 10000)
+		if fee < rule.MinAmount {
+			fee = rule.MinAmount
+		}
+		if rule.MaxAmount != nil && fee > *rule.MaxAmount {
+			fee = *rule.MaxAmount
+		}
+	}
+	if fee < 0 {
+		return 0
+	}
+	return fee
+}
+
+// quoteFees applies every active rule matching the sender's currency and
+// account type. Zero fees are left out of the breakdown.
+func (t *transferUsecase) quoteFees(ctx context.Context, sender model.Accounts, amount int64) ([]model.TransferFee, int64, error) {
+	rules, err := t.feeRulesRepo.ListActive(ctx, sender.Currency, sender.Type)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	fees := []model.TransferFee{}
+	var total int64
+	for _, rule := range rules {
+		amount := computeFee(rule, amount)
+		if amount == 0 {
+			continue
+		}
+		fees = append(fees, model.TransferFee{
+			ID:               utils.GenerateUUID(),
+			RuleID:           rule.ID,
+			Name:             rule.Name,
+			Amount:           amount,
+			RevenueAccountID: model.FeeRevenueAccountID(sender.Currency),
+		})
+		total += amount
+	}
+	return fees, total, nil
+}
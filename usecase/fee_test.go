@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/terajari/bank-api/model"
+)
+
+func TestComputeFee(t *testing.T) {
+	max := int64(5000)
+	testCases := []struct {
+		name   string
+		rule   model.FeeRule
+		amount int64
+		want   int64
+	}{
+		{
+			name:   "flat",
+			rule:   model.FeeRule{Kind: model.FeeKindFlat, FlatAmount: 2500},
+			amount: 100000,
+			want:   2500,
+		},
+		{
+			name:   "percentage",
+			rule:   model.FeeRule{Kind: model.FeeKindPercentage, RateBps: 50},
+			amount: 100000,
+			want:   500,
+		},
+		{
+			name:   "percentage rounds half even",
+			rule:   model.FeeRule{Kind: model.FeeKindPercentage, RateBps: 50},
+			amount: 100,
+			want:   0,
+		},
+		{
+			name:   "percentage below min",
+			rule:   model.FeeRule{Kind: model.FeeKindPercentage, RateBps: 50, MinAmount: 1000},
+			amount: 100000,
+			want:   1000,
+		},
+		{
+			name:   "percentage above max",
+			rule:   model.FeeRule{Kind: model.FeeKindPercentage, RateBps: 50, MaxAmount: &max},
+			amount: 10000000,
+			want:   5000,
+		},
+		{
+			name:   "unknown kind",
+			rule:   model.FeeRule{Kind: "other", FlatAmount: 100},
+			amount: 100000,
+			want:   0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := computeFee(tc.rule, tc.amount)
+			if got != tc.want {
+				t.Errorf("computeFee() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/logging"
+	"github.com/terajari/bank-api/repository"
+)
+
+// Pinger is the connection pool the readiness check pings.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+type HealthUsecase interface {
+	Readiness(ctx context.Context) dto.ReadinessResponse
+}
+
+type healthUsecase struct {
+	db            Pinger
+	schemaRepo    repository.SchemaRepository
+	schemaVersion int64
+}
+
+// NewHealthUsecase checks the database against schemaVersion, the newest
+// migration this build knows about. A newer schema is accepted so that
+// running instances stay ready while a rollout migrates ahead of them.
+func NewHealthUsecase(db Pinger, schemaRepo repository.SchemaRepository, schemaVersion int64) HealthUsecase {
+	return &healthUsecase{db: db, schemaRepo: schemaRepo, schemaVersion: schemaVersion}
+}
+
+func (h *healthUsecase) Readiness(ctx context.Context) dto.ReadinessResponse {
+	resp := dto.ReadinessResponse{
+		Status: dto.HealthStatusOK,
+		Checks: map[string]string{
+			"database":   dto.HealthStatusOK,
+			"migrations": dto.HealthStatusOK,
+		},
+	}
+	fail := func(check string, err error) {
+		resp.Status = dto.HealthStatusFailing
+		resp.Checks[check] = err.Error()
+	}
+
+	// The endpoint is public, so driver errors are logged rather than
+	// reported.
+	if err := h.db.PingContext(ctx); err != nil {
+		logging.FromContext(ctx).Error("readiness: ping failed", "error", err)
+		fail("database", errors.New("unreachable"))
+		fail("migrations", errors.New("unknown"))
+		return resp
+	}
+
+	version, dirty, err := h.schemaRepo.Version(ctx)
+	switch {
+	case err == sql.ErrNoRows:
+		fail("migrations", fmt.Errorf("no migration applied, want version %d", h.schemaVersion))
+	case err != nil:
+		logging.FromContext(ctx).Error("readiness: cannot read schema version", "error", err)
+		fail("migrations", errors.New("unknown"))
+	case dirty:
+		fail("migrations", fmt.Errorf("version %d is dirty", version))
+	case version < h.schemaVersion:
+		fail("migrations", fmt.Errorf("version %d is behind %d", version, h.schemaVersion))
+	}
+	return resp
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/terajari/bank-api/dto"
+	mockrepo "github.com/terajari/bank-api/mock/repository"
+)
+
+type pingFunc func(ctx context.Context) error
+
+func (f pingFunc) PingContext(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestReadiness(t *testing.T) {
+	const want = 20231108083015
+	pingOK := pingFunc(func(ctx context.Context) error { return nil })
+
+	testCases := []struct {
+		name  string
+		ping  pingFunc
+		setup func(repo *mockrepo.MockSchemaRepository)
+		want  dto.ReadinessResponse
+	}{
+		{
+			name: "ready",
+			ping: pingOK,
+			setup: func(repo *mockrepo.MockSchemaRepository) {
+				repo.EXPECT().Version(gomock.Any()).Return(int64(want), false, nil)
+			},
+			want: dto.ReadinessResponse{Status: "ok", Checks: map[string]string{"database": "ok", "migrations": "ok"}},
+		},
+		{
+			name: "schema ahead",
+			ping: pingOK,
+			setup: func(repo *mockrepo.MockSchemaRepository) {
+				repo.EXPECT().Version(gomock.Any()).Return(int64(want+1), false, nil)
+			},
+			want: dto.ReadinessResponse{Status: "ok", Checks: map[string]string{"database": "ok", "migrations": "ok"}},
+		},
+		{
+			name:  "database down",
+			ping:  pingFunc(func(ctx context.Context) error { return errors.New("dial tcp 10.0.0.5:5432: connection refused") }),
+			setup: func(repo *mockrepo.MockSchemaRepository) {},
+			want:  dto.ReadinessResponse{Status: "failing", Checks: map[string]string{"database": "unreachable", "migrations": "unknown"}},
+		},
+		{
+			name: "schema behind",
+			ping: pingOK,
+			setup: func(repo *mockrepo.MockSchemaRepository) {
+				repo.EXPECT().Version(gomock.Any()).Return(int64(20231107091245), false, nil)
+			},
+			want: dto.ReadinessResponse{Status: "failing", Checks: map[string]string{"database": "ok", "migrations": "version 20231107091245 is behind 20231108083015"}},
+		},
+		{
+			name: "dirty migration",
+			ping: pingOK,
+			setup: func(repo *mockrepo.MockSchemaRepository) {
+				repo.EXPECT().Version(gomock.Any()).Return(int64(want), true, nil)
+			},
+			want: dto.ReadinessResponse{Status: "failing", Checks: map[string]string{"database": "ok", "migrations": "version 20231108083015 is dirty"}},
+		},
+		{
+			name: "never migrated",
+			ping: pingOK,
+			setup: func(repo *mockrepo.MockSchemaRepository) {
+				repo.EXPECT().Version(gomock.Any()).Return(int64(0), false, sql.ErrNoRows)
+			},
+			want: dto.ReadinessResponse{Status: "failing", Checks: map[string]string{"database": "ok", "migrations": "no migration applied, want version 20231108083015"}},
+		},
+		{
+			name: "no schema_migrations table",
+			ping: pingOK,
+			setup: func(repo *mockrepo.MockSchemaRepository) {
+				repo.EXPECT().Version(gomock.Any()).Return(int64(0), false, errors.New(`relation "schema_migrations" does not exist`))
+			},
+			want: dto.ReadinessResponse{Status: "failing", Checks: map[string]string{"database": "ok", "migrations": "unknown"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mockrepo.NewMockSchemaRepository(ctrl)
+			tc.setup(repo)
+
+			got := NewHealthUsecase(tc.ping, repo, want).Readiness(context.Background())
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Readiness() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/terajari/bank-api/apperror"
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/metrics"
+	"github.com/terajari/bank-api/model"
+	"github.com/terajari/bank-api/repository"
+	"github.com/terajari/bank-api/utils"
+)
+
+// holdExpiryBatch bounds how many holds are released per transaction.
+const holdExpiryBatch = 500
+
+var (
+	ErrHoldNotFound      = apperror.New(apperror.KindNotFound, "hold_not_found", "hold not found")
+	ErrHoldNotAuthorized = apperror.New(apperror.KindConflict, "hold_not_authorized", "hold is not authorized or has expired")
+	ErrHoldTTLTooLong    = apperror.New(apperror.KindInvalid, "hold_ttl_too_long", "hold ttl exceeds the maximum")
+)
+
+// AuthorizeHold reserves the amount and the fees it would cost today. Holds
+// only pass an allow decision of the risk evaluator; anything that would need
+// a review has to be sent as a regular transfer.
+func (t *transferUsecase) AuthorizeHold(ctx context.Context, req dto.AuthorizeHoldRequest) (model.Hold, error) {
+	ttl := t.config.HoldDefaultTTL
+	if req.TtlSeconds > 0 {
+		ttl = time.Duration(req.TtlSeconds) * time.Second
+	}
+	if ttl > t.config.HoldMaxTTL {
+		return model.Hold{}, ErrHoldTTLTooLong
+	}
+
+	sender, err := getAccount(ctx, t.accountRepo, req.SenderId)
+	if err != nil {
+		return model.Hold{}, err
+	}
+	receiver, err := getAccount(ctx, t.accountRepo, req.ReceiverId)
+	if err != nil {
+		return model.Hold{}, err
+	}
+	if err := checkNotFrozen(sender, receiver); err != nil {
+		return model.Hold{}, err
+	}
+	if sender.Currency != receiver.Currency {
+		return model.Hold{}, apperror.ErrCurrencyMismatch.Withf("currency mismatch: %s != %s", sender.Currency, receiver.Currency)
+	}
+
+	_, totalFee, err := t.quoteFees(ctx, sender, req.Amount)
+	if err != nil {
+		return model.Hold{}, err
+	}
+
+	assessment, err := t.assessRisk(ctx, sender, receiver.ID, req.Amount)
+	if err != nil {
+		return model.Hold{}, err
+	}
+	if assessment.Decision != model.RiskAllow {
+		return model.Hold{}, ErrTransferDenied
+	}
+
+	return t.holdsRepo.AuthorizeTx(ctx, model.Hold{
+		ID:         utils.GenerateUUID(),
+		AccountId:  sender.ID,
+		ReceiverId: receiver.ID,
+		Amount:     req.Amount,
+		Reserved:   req.Amount + totalFee,
+		ExpiresAt:  time.Now().Add(ttl),
+	}, t.payeeCoolingOff())
+}
+
+// CaptureHold transfers req.Amount, or the full authorized amount when it is
+// zero, and releases the rest of the reservation.
+func (t *transferUsecase) CaptureHold(ctx context.Context, req dto.CaptureHoldRequest) (dto.CaptureHoldResponse, error) {
+	hold, err := t.GetHold(ctx, req.Id)
+	if err != nil {
+		return dto.CaptureHoldResponse{}, err
+	}
+	if hold.Status != model.HoldStatusAuthorized {
+		return dto.CaptureHoldResponse{}, ErrHoldNotAuthorized
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		amount = hold.Amount
+	}
+	if amount > hold.Amount {
+		return dto.CaptureHoldResponse{}, repository.ErrCaptureExceedsHold
+	}
+
+	sender, err := t.accountRepo.Get(ctx, hold.AccountId)
+	if err != nil {
+		return dto.CaptureHoldResponse{}, err
+	}
+	fees, _, err := t.quoteFees(ctx, sender, amount)
+	if err != nil {
+		return dto.CaptureHoldResponse{}, err
+	}
+
+	captured, transfer, err := t.holdsRepo.CaptureTx(ctx, hold.ID, model.Transfer{
+		ID:     utils.GenerateUUID(),
+		Amount: amount,
+	}, fees)
+	if err == sql.ErrNoRows {
+		err = ErrHoldNotAuthorized
+	}
+	metrics.ObserveTransfer(sender.Currency, transfer.Transfer.Status, amount, err)
+	if err != nil {
+		return dto.CaptureHoldResponse{}, err
+	}
+	return dto.CaptureHoldResponse{
+		Hold:     captured,
+		Transfer: transfer,
+	}, nil
+}
+
+func (t *transferUsecase) VoidHold(ctx context.Context, id string) (model.Hold, error) {
+	hold, err := t.holdsRepo.VoidTx(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.Hold{}, ErrHoldNotAuthorized
+		}
+		return model.Hold{}, err
+	}
+	return hold, nil
+}
+
+func (t *transferUsecase) GetHold(ctx context.Context, id string) (model.Hold, error) {
+	hold, err := t.holdsRepo.Get(ctx, id)
+	if err == sql.ErrNoRows {
+		return model.Hold{}, ErrHoldNotFound
+	}
+	return hold, err
+}
+
+// ExpireHolds releases every hold whose TTL has passed.
+func (t *transferUsecase) ExpireHolds(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, err := t.holdsRepo.ExpireDue(ctx, holdExpiryBatch)
+		total += n
+		if err != nil || n < holdExpiryBatch {
+			return total, err
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/model"
+	"github.com/terajari/bank-api/repository"
+	"github.com/terajari/bank-api/utils"
+)
+
+// interestDayCount is the Actual/365 Fixed day count convention.
+const interestDayCount = 365
+
+type InterestUsecase interface {
+	AccrueDaily(ctx context.Context, date time.Time, dryRun bool) (dto.InterestAccrualReport, error)
+	PostMonthly(ctx context.Context, before time.Time, dryRun bool) (dto.InterestPostingReport, error)
+	LastAccrualDate(ctx context.Context) (time.Time, bool, error)
+}
+
+type interestUsecase struct {
+	interestRepo repository.InterestRepository
+	uow          UnitOfWork
+}
+
+func NewInterestUsecase(ir repository.InterestRepository, uow UnitOfWork) InterestUsecase {
+	return &interestUsecase{interestRepo: ir, uow: uow}
+}
+
+// AccrueDaily computes one day of interest on the end-of-day balance of every
+// interest bearing account. Running it twice for the same day is a no-op.
+func (i *interestUsecase) AccrueDaily(ctx context.Context, date time.Time, dryRun bool) (dto.InterestAccrualReport, error) {
+	day := truncateDay(date)
+	report := dto.InterestAccrualReport{
+		Date:   day,
+		DryRun: dryRun,
+		Totals: map[string]int64{},
+	}
+
+	candidates, err := i.interestRepo.ListCandidates(ctx, day.AddDate(0, 0, 1))
+	if err != nil {
+		return dto.InterestAccrualReport{}, err
+	}
+
+	for _, c := range candidates {
+		if c.Balance <= 0 {
+			continue
+		}
+		amount := utils.MulDivHalfEven(c.Balance, c.RateBps, 10000*interestDayCount)
+		if amount == 0 {
+			continue
+		}
+
+		item := dto.InterestAccrualItem{
+			AccountId: c.AccountID,
+			Currency:  c.Currency,
+			Balance:   c.Balance,
+			RateBps:   c.RateBps,
+			Amount:    amount,
+		}
+		if !dryRun {
+			item.Accrued, err = i.interestRepo.CreateAccrual(ctx, model.InterestAccrual{
+				ID:          utils.GenerateUUID(),
+				AccountID:   c.AccountID,
+				AccrualDate: day,
+				Balance:     c.Balance,
+				RateBps:     c.RateBps,
+				Amount:      amount,
+			})
+			if err != nil {
+				return dto.InterestAccrualReport{}, err
+			}
+		}
+
+		report.Totals[c.Currency] += amount
+		report.Items = append(report.Items, item)
+	}
+
+	return report, nil
+}
+
+// PostMonthly pays out every accrual dated before `before` (normally the first
+// day of the current month) as one transfer per account from the system
+// interest expense account of the account's currency.
+func (i *interestUsecase) PostMonthly(ctx context.Context, before time.Time, dryRun bool) (dto.InterestPostingReport, error) {
+	until := truncateDay(before)
+	report := dto.InterestPostingReport{
+		Before: until,
+		DryRun: dryRun,
+		Totals: map[string]int64{},
+	}
+
+	postings, err := i.interestRepo.ListUnposted(ctx, until)
+	if err != nil {
+		return dto.InterestPostingReport{}, err
+	}
+
+	var failed int
+	for _, p := range postings {
+		item := dto.InterestPostingItem{
+			AccountId: p.AccountID,
+			Currency:  p.Currency,
+			Amount:    p.Amount,
+			Accruals:  p.Accruals,
+		}
+		if !dryRun {
+			item.TransferId, item.Amount, err = i.post(ctx, p, until)
+			if err != nil {
+				item.Error = err.Error()
+				failed++
+			}
+		}
+
+		report.Totals[p.Currency] += item.Amount
+		report.Items = append(report.Items, item)
+	}
+
+	if failed > 0 {
+		return report, fmt.Errorf("failed to post interest for %d of %d accounts", failed, len(postings))
+	}
+	return report, nil
+}
+
+// post marks the accruals and pays them out in one unit of work, so accruals
+// are never marked posted without their transfer.
+func (i *interestUsecase) post(ctx context.Context, p model.InterestPosting, until time.Time) (string, int64, error) {
+	transferId := utils.GenerateUUID()
+	var amount int64
+	err := i.uow.WithTx(ctx, func(repos repository.Repositories) error {
+		var err error
+		amount, err = repos.InterestRepo().MarkPosted(ctx, p.AccountID, until, transferId)
+		if err != nil || amount <= 0 {
+			return err
+		}
+
+		_, err = repos.TransferRepo().TransferTx(ctx, repository.TransferTxParams{
+			Transfer: model.Transfer{
+				ID:         transferId,
+				SenderId:   model.InterestExpenseAccountID(p.Currency),
+				ReceiverId: p.AccountID,
+				Amount:     amount,
+			},
+		})
+		return err
+	})
+	if err != nil {
+		return "", 0, err
+	}
+
+	return transferId, amount, nil
+}
+
+func (i *interestUsecase) LastAccrualDate(ctx context.Context) (time.Time, bool, error) {
+	return i.interestRepo.LastAccrualDate(ctx)
+}
+
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
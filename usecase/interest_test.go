@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/terajari/bank-api/dto"
+	mockrepo "github.com/terajari/bank-api/mock/repository"
+	mockusecase "github.com/terajari/bank-api/mock/usecase"
+	"github.com/terajari/bank-api/model"
+	"github.com/terajari/bank-api/repository"
+)
+
+func TestAccrueDaily(t *testing.T) {
+	date := time.Date(2023, 11, 5, 15, 30, 0, 0, time.UTC)
+	day := time.Date(2023, 11, 5, 0, 0, 0, 0, time.UTC)
+	candidates := []model.InterestCandidate{
+		{AccountID: "acc1", Currency: "IDR", Balance: 1000000, RateBps: 150},
+		{AccountID: "acc2", Currency: "IDR", Balance: 100, RateBps: 150},
+		{AccountID: "acc3", Currency: "USD", Balance: -5000, RateBps: 150},
+		{AccountID: "acc4", Currency: "USD", Balance: 3650000, RateBps: 200},
+	}
+
+	testCases := []struct {
+		name       string
+		dryRun     bool
+		setup      func(repo *mockrepo.MockInterestRepository)
+		wantItems  int
+		wantTotals map[string]int64
+		wantErr    error
+	}{
+		{
+			name: "accrues positive balances",
+			setup: func(repo *mockrepo.MockInterestRepository) {
+				repo.EXPECT().ListCandidates(gomock.Any(), day.AddDate(0, 0, 1)).Return(candidates, nil)
+				repo.EXPECT().CreateAccrual(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, a model.InterestAccrual) (bool, error) {
+						if a.AccountID != "acc1" || a.Amount != 41 || !a.AccrualDate.Equal(day) {
+							t.Errorf("unexpected accrual %+v", a)
+						}
+						return true, nil
+					})
+				repo.EXPECT().CreateAccrual(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, a model.InterestAccrual) (bool, error) {
+						if a.AccountID != "acc4" || a.Amount != 200 {
+							t.Errorf("unexpected accrual %+v", a)
+						}
+						return false, nil
+					})
+			},
+			wantItems:  2,
+			wantTotals: map[string]int64{"IDR": 41, "USD": 200},
+		},
+		{
+			name:   "dry run",
+			dryRun: true,
+			setup: func(repo *mockrepo.MockInterestRepository) {
+				repo.EXPECT().ListCandidates(gomock.Any(), day.AddDate(0, 0, 1)).Return(candidates, nil)
+			},
+			wantItems:  2,
+			wantTotals: map[string]int64{"IDR": 41, "USD": 200},
+		},
+		{
+			name: "accrual failed",
+			setup: func(repo *mockrepo.MockInterestRepository) {
+				repo.EXPECT().ListCandidates(gomock.Any(), gomock.Any()).Return(candidates, nil)
+				repo.EXPECT().CreateAccrual(gomock.Any(), gomock.Any()).Return(false, sql.ErrConnDone)
+			},
+			wantErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mockrepo.NewMockInterestRepository(ctrl)
+			tc.setup(repo)
+
+			report, err := NewInterestUsecase(repo, mockusecase.NewMockUnitOfWork(ctrl)).AccrueDaily(context.Background(), date, tc.dryRun)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("AccrueDaily() error = %v, want %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(report.Items) != tc.wantItems {
+				t.Fatalf("Items = %+v", report.Items)
+			}
+			for currency, want := range tc.wantTotals {
+				if report.Totals[currency] != want {
+					t.Errorf("Totals[%s] = %d, want %d", currency, report.Totals[currency], want)
+				}
+			}
+			if !tc.dryRun && (!report.Items[0].Accrued || report.Items[1].Accrued) {
+				t.Errorf("Accrued flags = %+v", report.Items)
+			}
+		})
+	}
+}
+
+func TestPostMonthly(t *testing.T) {
+	before := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	postings := []model.InterestPosting{
+		{AccountID: "acc1", Currency: "IDR", Amount: 1230, Accruals: 30},
+	}
+
+	type mocks struct {
+		uow       *mockusecase.MockUnitOfWork
+		repos     *mockrepo.MockRepositories
+		interest  *mockrepo.MockInterestRepository
+		transfers *mockrepo.MockTransferRepository
+	}
+	runTx := func(m mocks) {
+		m.uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(repository.Repositories) error) error {
+				return fn(m.repos)
+			})
+		m.repos.EXPECT().InterestRepo().Return(m.interest).AnyTimes()
+		m.repos.EXPECT().TransferRepo().Return(m.transfers).AnyTimes()
+	}
+
+	testCases := []struct {
+		name       string
+		dryRun     bool
+		setup      func(m mocks)
+		wantAmount int64
+		wantPosted bool
+		wantErr    bool
+	}{
+		{
+			name: "posts marked amount in one unit of work",
+			setup: func(m mocks) {
+				m.interest.EXPECT().ListUnposted(gomock.Any(), before).Return(postings, nil)
+				runTx(m)
+				var transferId string
+				m.interest.EXPECT().MarkPosted(gomock.Any(), "acc1", before, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, _ time.Time, id string) (int64, error) {
+						transferId = id
+						return 1200, nil
+					})
+				m.transfers.EXPECT().TransferTx(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, arg repository.TransferTxParams) (dto.MakeTransferResponse, error) {
+						tr := arg.Transfer
+						if tr.ID != transferId || tr.SenderId != model.InterestExpenseAccountID("IDR") || tr.ReceiverId != "acc1" || tr.Amount != 1200 {
+							t.Errorf("unexpected transfer %+v", tr)
+						}
+						return dto.MakeTransferResponse{}, nil
+					})
+			},
+			wantAmount: 1200,
+			wantPosted: true,
+		},
+		{
+			name: "already posted",
+			setup: func(m mocks) {
+				m.interest.EXPECT().ListUnposted(gomock.Any(), before).Return(postings, nil)
+				runTx(m)
+				m.interest.EXPECT().MarkPosted(gomock.Any(), "acc1", before, gomock.Any()).Return(int64(0), nil)
+			},
+			wantPosted: true,
+		},
+		{
+			name: "transfer failed",
+			setup: func(m mocks) {
+				m.interest.EXPECT().ListUnposted(gomock.Any(), before).Return(postings, nil)
+				runTx(m)
+				m.interest.EXPECT().MarkPosted(gomock.Any(), "acc1", before, gomock.Any()).Return(int64(1230), nil)
+				m.transfers.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Return(dto.MakeTransferResponse{}, repository.ErrInsufficientFunds)
+			},
+			wantErr: true,
+		},
+		{
+			name:   "dry run",
+			dryRun: true,
+			setup: func(m mocks) {
+				m.interest.EXPECT().ListUnposted(gomock.Any(), before).Return(postings, nil)
+			},
+			wantAmount: 1230,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks{
+				uow:       mockusecase.NewMockUnitOfWork(ctrl),
+				repos:     mockrepo.NewMockRepositories(ctrl),
+				interest:  mockrepo.NewMockInterestRepository(ctrl),
+				transfers: mockrepo.NewMockTransferRepository(ctrl),
+			}
+			tc.setup(m)
+
+			report, err := NewInterestUsecase(m.interest, m.uow).PostMonthly(context.Background(), before, tc.dryRun)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("PostMonthly() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if len(report.Items) != 1 {
+				t.Fatalf("Items = %+v", report.Items)
+			}
+			item := report.Items[0]
+			if tc.wantErr {
+				if item.Error == "" || item.TransferId != "" || item.Amount != 0 {
+					t.Errorf("failed item = %+v", item)
+				}
+				return
+			}
+			if item.Amount != tc.wantAmount || report.Totals["IDR"] != tc.wantAmount {
+				t.Errorf("Amount = %d, Totals = %v, want %d", item.Amount, report.Totals, tc.wantAmount)
+			}
+			if (item.TransferId != "") != tc.wantPosted {
+				t.Errorf("TransferId = %q, want posted %v", item.TransferId, tc.wantPosted)
+			}
+		})
+	}
+}
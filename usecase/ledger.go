@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/logging"
+	"github.com/terajari/bank-api/metrics"
+	"github.com/terajari/bank-api/model"
+	"github.com/terajari/bank-api/repository"
+)
+
+type LedgerUsecase interface {
+	Verify(ctx context.Context) (dto.LedgerReport, error)
+}
+
+type ledgerUsecase struct {
+	repo repository.LedgerRepository
+}
+
+func NewLedgerUsecase(repo repository.LedgerRepository) LedgerUsecase {
+	return &ledgerUsecase{repo: repo}
+}
+
+// Verify recomputes every balance from its entries, checks that every
+// transfer has exactly its two entries and that the transfers of every
+// currency sum to zero. It only reads: a problem is reported, logged and
+// counted in the ledger metrics, and left for an operator to resolve.
+func (l *ledgerUsecase) Verify(ctx context.Context) (report dto.LedgerReport, err error) {
+	start := time.Now()
+	defer func() { metrics.ObserveLedgerReport(report, err) }()
+
+	check, err := l.repo.Check(ctx)
+	if err != nil {
+		return dto.LedgerReport{}, err
+	}
+
+	report = dto.LedgerReport{
+		CheckedAt:      start.UTC(),
+		DurationMs:     time.Since(start).Milliseconds(),
+		BalanceDrifts:  check.BalanceDrifts,
+		TransferIssues: check.TransferIssues,
+	}
+	if report.BalanceDrifts == nil {
+		report.BalanceDrifts = []model.BalanceDrift{}
+	}
+	if report.TransferIssues == nil {
+		report.TransferIssues = []model.TransferIssue{}
+	}
+	report.CurrencyTotals = make([]dto.LedgerCurrencyTotal, len(check.CurrencyTotals))
+	for i, total := range check.CurrencyTotals {
+		report.CurrencyTotals[i] = dto.LedgerCurrencyTotal{CurrencyTotal: total, Imbalance: total.Imbalance()}
+	}
+	imbalanced := report.Imbalanced()
+	report.Consistent = len(report.BalanceDrifts) == 0 && len(report.TransferIssues) == 0 && len(imbalanced) == 0
+
+	logger := logging.FromContext(ctx)
+	if !report.Consistent {
+		// Only the counts: the report itself can be long and is returned.
+		logger.Error("ledger: inconsistent", "balance_drifts", len(report.BalanceDrifts), "transfer_issues", len(report.TransferIssues), "imbalanced_currencies", len(imbalanced))
+		return report, nil
+	}
+	logger.Info("ledger: consistent", "duration_ms", report.DurationMs)
+	return report, nil
+}
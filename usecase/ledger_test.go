@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/terajari/bank-api/metrics"
+	mockrepo "github.com/terajari/bank-api/mock/repository"
+	"github.com/terajari/bank-api/model"
+)
+
+func TestVerifyLedger(t *testing.T) {
+	balanced := []model.CurrencyTotal{{Currency: "IDR"}}
+
+	testCases := []struct {
+		name           string
+		check          model.LedgerCheck
+		err            error
+		wantConsistent bool
+		wantProblems   map[string]float64
+		wantErr        error
+	}{
+		{
+			name:           "consistent",
+			check:          model.LedgerCheck{CurrencyTotals: balanced},
+			wantConsistent: true,
+			wantProblems:   map[string]float64{metrics.LedgerCheckBalance: 0, metrics.LedgerCheckTransfer: 0, metrics.LedgerCheckCurrency: 0},
+		},
+		{
+			name: "balance drift",
+			check: model.LedgerCheck{
+				BalanceDrifts:  []model.BalanceDrift{{AccountID: "acc1", Currency: "IDR", Balance: 6000, EntriesTotal: 5000}},
+				CurrencyTotals: balanced,
+			},
+			wantProblems: map[string]float64{metrics.LedgerCheckBalance: 1, metrics.LedgerCheckTransfer: 0, metrics.LedgerCheckCurrency: 0},
+		},
+		{
+			name: "transfer with one entry",
+			check: model.LedgerCheck{
+				TransferIssues: []model.TransferIssue{{TransferID: "tr1", Status: model.TransferStatusCompleted, Amount: 1000, Entries: 1, SenderEntries: 1}},
+				CurrencyTotals: []model.CurrencyTotal{{Currency: "IDR", Balances: -1000, Entries: -1000}},
+			},
+			wantProblems: map[string]float64{metrics.LedgerCheckBalance: 0, metrics.LedgerCheckTransfer: 1, metrics.LedgerCheckCurrency: 1},
+		},
+		{name: "check failed", err: sql.ErrConnDone, wantErr: sql.ErrConnDone},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mockrepo.NewMockLedgerRepository(ctrl)
+			repo.EXPECT().Check(gomock.Any()).Return(tc.check, tc.err)
+
+			report, err := NewLedgerUsecase(repo).Verify(context.Background())
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if report.Consistent != tc.wantConsistent {
+				t.Errorf("Consistent = %v, want %v", report.Consistent, tc.wantConsistent)
+			}
+			if report.BalanceDrifts == nil || report.TransferIssues == nil {
+				t.Errorf("report lists must not be nil: %+v", report)
+			}
+			if len(report.CurrencyTotals) != len(tc.check.CurrencyTotals) {
+				t.Fatalf("CurrencyTotals = %+v", report.CurrencyTotals)
+			}
+			for i, total := range report.CurrencyTotals {
+				if want := tc.check.CurrencyTotals[i].Imbalance(); total.Imbalance != want {
+					t.Errorf("%s imbalance = %d, want %d", total.Currency, total.Imbalance, want)
+				}
+			}
+			for check, want := range tc.wantProblems {
+				if got := testutil.ToFloat64(metrics.LedgerProblems.WithLabelValues(check)); got != want {
+					t.Errorf("ledger_problems{check=%q} = %v, want %v", check, got, want)
+				}
+			}
+		})
+	}
+}
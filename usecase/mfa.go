@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/terajari/bank-api/apperror"
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/model"
+	"github.com/terajari/bank-api/repository"
+	"github.com/terajari/bank-api/token"
+	"github.com/terajari/bank-api/utils"
+)
+
+const recoveryCodeCount = 10
+
+var (
+	ErrMfaNotEnabled     = apperror.New(apperror.KindInvalid, "mfa_not_enabled", "mfa is not enabled")
+	ErrMfaAlreadyEnabled = apperror.New(apperror.KindConflict, "mfa_already_enabled", "mfa is already enabled")
+	ErrInvalidMfaCode    = apperror.New(apperror.KindUnauthorized, "invalid_mfa_code", "invalid mfa code")
+	ErrMfaLocked         = apperror.New(apperror.KindRateLimited, "mfa_locked", "too many invalid mfa codes, try again later")
+	ErrMfaChallengeUsed  = apperror.New(apperror.KindUnauthorized, "mfa_challenge_used", "mfa challenge has already been used")
+)
+
+type MfaUsecase interface {
+	Enroll(ctx context.Context, username string) (dto.EnrollMfaResponse, error)
+	Confirm(ctx context.Context, req dto.ConfirmMfaRequest) (dto.ConfirmMfaResponse, error)
+	IsEnabled(ctx context.Context, username string) (bool, error)
+	VerifyChallenge(ctx context.Context, challenge *token.Payload, code string) error
+	VerifyStepUp(ctx context.Context, username, code string) error
+}
+
+type mfaUsecase struct {
+	repo        repository.MfaRepository
+	issuer      string
+	maxAttempts int
+	lockout     time.Duration
+}
+
+func NewMfaUsecase(repo repository.MfaRepository, cfg *utils.Config) MfaUsecase {
+	return &mfaUsecase{
+		repo:        repo,
+		issuer:      cfg.MfaIssuer,
+		maxAttempts: cfg.MfaMaxAttempts,
+		lockout:     cfg.MfaLockout,
+	}
+}
+
+func (m *mfaUsecase) Enroll(ctx context.Context, username string) (dto.EnrollMfaResponse, error) {
+	enabled, err := m.IsEnabled(ctx, username)
+	if err != nil {
+		return dto.EnrollMfaResponse{}, err
+	}
+	if enabled {
+		return dto.EnrollMfaResponse{}, ErrMfaAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return dto.EnrollMfaResponse{}, err
+	}
+
+	mfa, err := m.repo.Upsert(ctx, model.UserMfa{
+		Username: username,
+		Secret:   secret,
+	})
+	if err != nil {
+		return dto.EnrollMfaResponse{}, err
+	}
+
+	return dto.EnrollMfaResponse{
+		Secret:          mfa.Secret,
+		ProvisioningUri: utils.TOTPProvisioningURI(m.issuer, mfa.Username, mfa.Secret),
+	}, nil
+}
+
+func (m *mfaUsecase) Confirm(ctx context.Context, req dto.ConfirmMfaRequest) (dto.ConfirmMfaResponse, error) {
+	mfa, err := m.repo.Get(ctx, req.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return dto.ConfirmMfaResponse{}, ErrMfaNotEnabled
+		}
+		return dto.ConfirmMfaResponse{}, err
+	}
+	if mfa.Enabled {
+		return dto.ConfirmMfaResponse{}, ErrMfaAlreadyEnabled
+	}
+
+	step, ok := utils.ValidateTOTP(mfa.Secret, req.Code, time.Now())
+	if !ok {
+		return dto.ConfirmMfaResponse{}, ErrInvalidMfaCode
+	}
+
+	codes, err := utils.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return dto.ConfirmMfaResponse{}, err
+	}
+	recoveryCodes := make([]model.RecoveryCode, 0, len(codes))
+	for _, code := range codes {
+		recoveryCodes = append(recoveryCodes, model.RecoveryCode{
+			ID:       utils.GenerateUUID(),
+			Username: mfa.Username,
+			CodeHash: utils.HashRecoveryCode(code),
+		})
+	}
+
+	if err := m.repo.Enable(ctx, mfa.Username, step, recoveryCodes); err != nil {
+		if err == sql.ErrNoRows {
+			return dto.ConfirmMfaResponse{}, ErrMfaAlreadyEnabled
+		}
+		return dto.ConfirmMfaResponse{}, err
+	}
+
+	return dto.ConfirmMfaResponse{
+		RecoveryCodes: codes,
+	}, nil
+}
+
+func (m *mfaUsecase) IsEnabled(ctx context.Context, username string) (bool, error) {
+	mfa, err := m.repo.Get(ctx, username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return mfa.Enabled, nil
+}
+
+// VerifyChallenge completes a login challenge with either a current TOTP code
+// or an unused recovery code. Each challenge is accepted at most once.
+func (m *mfaUsecase) VerifyChallenge(ctx context.Context, challenge *token.Payload, code string) error {
+	if err := m.verify(ctx, challenge.Username, code, true); err != nil {
+		return err
+	}
+
+	used, err := m.repo.UseChallenge(ctx, challenge.ID.String(), challenge.Username, challenge.ExpiredAt)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrMfaChallengeUsed
+	}
+	return nil
+}
+
+// VerifyStepUp accepts only a current TOTP code. Recovery codes are meant to
+// regain access to the account, not to authorize high value transfers.
+func (m *mfaUsecase) VerifyStepUp(ctx context.Context, username, code string) error {
+	return m.verify(ctx, username, code, false)
+}
+
+// verify checks code against the user's TOTP secret and, when allowRecovery
+// is set, the unused recovery codes. Each TOTP step and each recovery code is
+// accepted at most once. The user is locked out after maxAttempts codes in a
+// row were rejected.
+func (m *mfaUsecase) verify(ctx context.Context, username, code string, allowRecovery bool) error {
+	mfa, err := m.repo.Get(ctx, username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrMfaNotEnabled
+		}
+		return err
+	}
+	if !mfa.Enabled {
+		return ErrMfaNotEnabled
+	}
+
+	counted, err := m.repo.CountAttempt(ctx, username, m.maxAttempts, m.lockout)
+	if err != nil {
+		return err
+	}
+	if !counted {
+		return ErrMfaLocked
+	}
+
+	var used bool
+	if step, ok := utils.ValidateTOTP(mfa.Secret, code, time.Now()); ok {
+		used, err = m.repo.UseStep(ctx, username, step)
+	} else if allowRecovery {
+		used, err = m.repo.UseRecoveryCode(ctx, username, utils.HashRecoveryCode(code))
+	}
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidMfaCode
+	}
+
+	return m.repo.ResetAttempts(ctx, username)
+}
@@ -0,0 +1,334 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/terajari/bank-api/dto"
+	mockrepo "github.com/terajari/bank-api/mock/repository"
+	"github.com/terajari/bank-api/model"
+	"github.com/terajari/bank-api/token"
+	"github.com/terajari/bank-api/utils"
+)
+
+var mfaConfig = &utils.Config{
+	MfaIssuer:      "bank-api",
+	MfaMaxAttempts: 5,
+	MfaLockout:     15 * time.Minute,
+}
+
+func currentTOTPCode(t *testing.T, secret string) string {
+	t.Helper()
+	code, err := utils.TOTPCode(secret, utils.TOTPStep(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+func TestEnrollMfa(t *testing.T) {
+	testCases := []struct {
+		name    string
+		setup   func(repo *mockrepo.MockMfaRepository)
+		wantErr error
+	}{
+		{
+			name: "new enrollment",
+			setup: func(repo *mockrepo.MockMfaRepository) {
+				repo.EXPECT().Get(gomock.Any(), "fulan1234").Return(model.UserMfa{}, sql.ErrNoRows)
+				repo.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, mfa model.UserMfa) (model.UserMfa, error) {
+					return mfa, nil
+				})
+			},
+		},
+		{
+			name: "unconfirmed enrollment is replaced",
+			setup: func(repo *mockrepo.MockMfaRepository) {
+				repo.EXPECT().Get(gomock.Any(), "fulan1234").Return(model.UserMfa{Username: "fulan1234", Secret: "OLD"}, nil)
+				repo.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, mfa model.UserMfa) (model.UserMfa, error) {
+					return mfa, nil
+				})
+			},
+		},
+		{
+			name: "already enabled",
+			setup: func(repo *mockrepo.MockMfaRepository) {
+				repo.EXPECT().Get(gomock.Any(), "fulan1234").Return(model.UserMfa{Username: "fulan1234", Enabled: true}, nil)
+			},
+			wantErr: ErrMfaAlreadyEnabled,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mockrepo.NewMockMfaRepository(ctrl)
+			tc.setup(repo)
+
+			got, err := NewMfaUsecase(repo, mfaConfig).Enroll(context.Background(), "fulan1234")
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Enroll() error = %v, want %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Secret == "" || got.Secret == "OLD" {
+				t.Errorf("Secret = %q, want a new secret", got.Secret)
+			}
+			if got.ProvisioningUri != utils.TOTPProvisioningURI("bank-api", "fulan1234", got.Secret) {
+				t.Errorf("ProvisioningUri = %q", got.ProvisioningUri)
+			}
+		})
+	}
+}
+
+func TestConfirmMfa(t *testing.T) {
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending := model.UserMfa{Username: "fulan1234", Secret: secret}
+
+	testCases := []struct {
+		name    string
+		code    string
+		setup   func(repo *mockrepo.MockMfaRepository)
+		wantErr error
+	}{
+		{
+			name: "confirmed",
+			code: currentTOTPCode(t, secret),
+			setup: func(repo *mockrepo.MockMfaRepository) {
+				repo.EXPECT().Get(gomock.Any(), "fulan1234").Return(pending, nil)
+				repo.EXPECT().Enable(gomock.Any(), "fulan1234", gomock.Any(), gomock.Len(recoveryCodeCount)).Return(nil)
+			},
+		},
+		{
+			name: "wrong code",
+			code: "abcdef",
+			setup: func(repo *mockrepo.MockMfaRepository) {
+				repo.EXPECT().Get(gomock.Any(), "fulan1234").Return(pending, nil)
+			},
+			wantErr: ErrInvalidMfaCode,
+		},
+		{
+			name: "not enrolled",
+			code: currentTOTPCode(t, secret),
+			setup: func(repo *mockrepo.MockMfaRepository) {
+				repo.EXPECT().Get(gomock.Any(), "fulan1234").Return(model.UserMfa{}, sql.ErrNoRows)
+			},
+			wantErr: ErrMfaNotEnabled,
+		},
+		{
+			name: "already enabled",
+			code: currentTOTPCode(t, secret),
+			setup: func(repo *mockrepo.MockMfaRepository) {
+				enabled := pending
+				enabled.Enabled = true
+				repo.EXPECT().Get(gomock.Any(), "fulan1234").Return(enabled, nil)
+			},
+			wantErr: ErrMfaAlreadyEnabled,
+		},
+		{
+			name: "confirmed concurrently",
+			code: currentTOTPCode(t, secret),
+			setup: func(repo *mockrepo.MockMfaRepository) {
+				repo.EXPECT().Get(gomock.Any(), "fulan1234").Return(pending, nil)
+				repo.EXPECT().Enable(gomock.Any(), "fulan1234", gomock.Any(), gomock.Any()).Return(sql.ErrNoRows)
+			},
+			wantErr: ErrMfaAlreadyEnabled,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mockrepo.NewMockMfaRepository(ctrl)
+			tc.setup(repo)
+
+			got, err := NewMfaUsecase(repo, mfaConfig).Confirm(context.Background(), dto.ConfirmMfaRequest{Username: "fulan1234", Code: tc.code})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Confirm() error = %v, want %v", err, tc.wantErr)
+			}
+			if err == nil && len(got.RecoveryCodes) != recoveryCodeCount {
+				t.Errorf("got %d recovery codes, want %d", len(got.RecoveryCodes), recoveryCodeCount)
+			}
+		})
+	}
+}
+
+func TestVerifyMfaChallenge(t *testing.T) {
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	enabled := model.UserMfa{Username: "fulan1234", Secret: secret, Enabled: true}
+	challenge := &token.Payload{
+		ID:        uuid.New(),
+		Username:  "fulan1234",
+		Purpose:   token.PurposeMfaChallenge,
+		ExpiredAt: time.Now().Add(5 * time.Minute),
+	}
+
+	testCases := []struct {
+		name    string
+		code    string
+		setup   func(repo *mockrepo.MockMfaRepository)
+		wantErr error
+	}{
+		{
+			name: "totp code",
+			code: currentTOTPCode(t, secret),
+			setup: func(repo *mockrepo.MockMfaRepository) {
+				repo.EXPECT().Get(gomock.Any(), "fulan1234").Return(enabled, nil)
+				repo.EXPECT().CountAttempt(gomock.Any(), "fulan1234", 5, 15*time.Minute).Return(true, nil)
+				repo.EXPECT().UseStep(gomock.Any(), "fulan1234", gomock.Any()).Return(true, nil)
+				repo.EXPECT().ResetAttempts(gomock.Any(), "fulan1234").Return(nil)
+				repo.EXPECT().UseChallenge(gomock.Any(), challenge.ID.String(), "fulan1234", challenge.ExpiredAt).Return(true, nil)
+			},
+		},
+		{
+			name: "recovery code",
+			code: "abcde-fghij",
+			setup: func(repo *mockrepo.MockMfaRepository) {
+				repo.EXPECT().Get(gomock.Any(), "fulan1234").Return(enabled, nil)
+				repo.EXPECT().CountAttempt(gomock.Any(), "fulan1234", 5, 15*time.Minute).Return(true, nil)
+				repo.EXPECT().UseRecoveryCode(gomock.Any(), "fulan1234", utils.HashRecoveryCode("abcde-fghij")).Return(true, nil)
+				repo.EXPECT().ResetAttempts(gomock.Any(), "fulan1234").Return(nil)
+				repo.EXPECT().UseChallenge(gomock.Any(), challenge.ID.String(), "fulan1234", challenge.ExpiredAt).Return(true, nil)
+			},
+		},
+		{
+			name: "wrong code",
+			code: "abcdef",
+			setup: func(repo *mockrepo.MockMfaRepository) {
+				repo.EXPECT().Get(gomock.Any(), "fulan1234").Return(enabled, nil)
+				repo.EXPECT().CountAttempt(gomock.Any(), "fulan1234", 5, 15*time.Minute).Return(true, nil)
+				repo.EXPECT().UseRecoveryCode(gomock.Any(), "fulan1234", gomock.Any()).Return(false, nil)
+			},
+			wantErr: ErrInvalidMfaCode,
+		},
+		{
+			name: "reused totp step",
+			code: currentTOTPCode(t, secret),
+			setup: func(repo *mockrepo.MockMfaRepository) {
+				repo.EXPECT().Get(gomock.Any(), "fulan1234").Return(enabled, nil)
+				repo.EXPECT().CountAttempt(gomock.Any(), "fulan1234", 5, 15*time.Minute).Return(true, nil)
+				repo.EXPECT().UseStep(gomock.Any(), "fulan1234", gomock.Any()).Return(false, nil)
+			},
+			wantErr: ErrInvalidMfaCode,
+		},
+		{
+			name: "locked out",
+			code: currentTOTPCode(t, secret),
+			setup: func(repo *mockrepo.MockMfaRepository) {
+				repo.EXPECT().Get(gomock.Any(), "fulan1234").Return(enabled, nil)
+				repo.EXPECT().CountAttempt(gomock.Any(), "fulan1234", 5, 15*time.Minute).Return(false, nil)
+			},
+			wantErr: ErrMfaLocked,
+		},
+		{
+			name: "challenge already used",
+			code: currentTOTPCode(t, secret),
+			setup: func(repo *mockrepo.MockMfaRepository) {
+				repo.EXPECT().Get(gomock.Any(), "fulan1234").Return(enabled, nil)
+				repo.EXPECT().CountAttempt(gomock.Any(), "fulan1234", 5, 15*time.Minute).Return(true, nil)
+				repo.EXPECT().UseStep(gomock.Any(), "fulan1234", gomock.Any()).Return(true, nil)
+				repo.EXPECT().ResetAttempts(gomock.Any(), "fulan1234").Return(nil)
+				repo.EXPECT().UseChallenge(gomock.Any(), challenge.ID.String(), "fulan1234", challenge.ExpiredAt).Return(false, nil)
+			},
+			wantErr: ErrMfaChallengeUsed,
+		},
+		{
+			name: "not enabled",
+			code: currentTOTPCode(t, secret),
+			setup: func(repo *mockrepo.MockMfaRepository) {
+				repo.EXPECT().Get(gomock.Any(), "fulan1234").Return(model.UserMfa{Username: "fulan1234", Secret: secret}, nil)
+			},
+			wantErr: ErrMfaNotEnabled,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mockrepo.NewMockMfaRepository(ctrl)
+			tc.setup(repo)
+
+			err := NewMfaUsecase(repo, mfaConfig).VerifyChallenge(context.Background(), challenge, tc.code)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("VerifyChallenge() error = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyMfaStepUp(t *testing.T) {
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	enabled := model.UserMfa{Username: "fulan1234", Secret: secret, Enabled: true}
+
+	testCases := []struct {
+		name    string
+		code    string
+		setup   func(repo *mockrepo.MockMfaRepository)
+		wantErr error
+	}{
+		{
+			name: "totp code",
+			code: currentTOTPCode(t, secret),
+			setup: func(repo *mockrepo.MockMfaRepository) {
+				repo.EXPECT().Get(gomock.Any(), "fulan1234").Return(enabled, nil)
+				repo.EXPECT().CountAttempt(gomock.Any(), "fulan1234", 5, 15*time.Minute).Return(true, nil)
+				repo.EXPECT().UseStep(gomock.Any(), "fulan1234", gomock.Any()).Return(true, nil)
+				repo.EXPECT().ResetAttempts(gomock.Any(), "fulan1234").Return(nil)
+			},
+		},
+		{
+			name: "recovery code is refused",
+			code: "abcde-fghij",
+			setup: func(repo *mockrepo.MockMfaRepository) {
+				repo.EXPECT().Get(gomock.Any(), "fulan1234").Return(enabled, nil)
+				repo.EXPECT().CountAttempt(gomock.Any(), "fulan1234", 5, 15*time.Minute).Return(true, nil)
+			},
+			wantErr: ErrInvalidMfaCode,
+		},
+		{
+			name: "locked out",
+			code: currentTOTPCode(t, secret),
+			setup: func(repo *mockrepo.MockMfaRepository) {
+				repo.EXPECT().Get(gomock.Any(), "fulan1234").Return(enabled, nil)
+				repo.EXPECT().CountAttempt(gomock.Any(), "fulan1234", 5, 15*time.Minute).Return(false, nil)
+			},
+			wantErr: ErrMfaLocked,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mockrepo.NewMockMfaRepository(ctrl)
+			tc.setup(repo)
+
+			err := NewMfaUsecase(repo, mfaConfig).VerifyStepUp(context.Background(), "fulan1234", tc.code)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("VerifyStepUp() error = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
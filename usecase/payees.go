@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/terajari/bank-api/apperror"
+	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/model"
+	"github.com/terajari/bank-api/repository"
+	"github.com/terajari/bank-api/utils"
+)
+
+var (
+	ErrPayeeNotFound = apperror.New(apperror.KindNotFound, "payee_not_found", "payee not found")
+	ErrPayeeExists   = apperror.New(apperror.KindConflict, "payee_exists", "payee with this account or nickname already exists")
+)
+
+type PayeesUsecase interface {
+	CreatePayee(ctx context.Context, req dto.CreatePayeeRequest) (model.Payee, error)
+	GetPayee(ctx context.Context, id, owner string) (model.Payee, error)
+	ListPayees(ctx context.Context, req dto.ListPayeesRequest) ([]model.Payee, error)
+	UpdatePayee(ctx context.Context, req dto.UpdatePayeeRequest) (model.Payee, error)
+	DeletePayee(ctx context.Context, id, owner string) error
+}
+
+type payeesUsecase struct {
+	repo        repository.PayeesRepository
+	accountRepo repository.AccountsRepository
+}
+
+func NewPayeesUsecase(repo repository.PayeesRepository, accountRepo repository.AccountsRepository) PayeesUsecase {
+	return &payeesUsecase{repo: repo, accountRepo: accountRepo}
+}
+
+// CreatePayee saves an account to the owner's address book. The currency must
+// be the account's currency so a payee cannot later receive a transfer in a
+// currency the account does not hold.
+func (p *payeesUsecase) CreatePayee(ctx context.Context, req dto.CreatePayeeRequest) (model.Payee, error) {
+	account, err := getAccount(ctx, p.accountRepo, req.AccountId)
+	if err != nil {
+		return model.Payee{}, err
+	}
+	if account.Currency != req.Currency {
+		return model.Payee{}, ErrInvalidCurrency.Withf("invalid currency: payee account holds %s", account.Currency)
+	}
+
+	payee, err := p.repo.Create(ctx, model.Payee{
+		ID:        utils.GenerateUUID(),
+		Owner:     req.Owner,
+		Nickname:  req.Nickname,
+		AccountId: account.ID,
+		Currency:  account.Currency,
+	})
+	return payee, payeeError(err)
+}
+
+func (p *payeesUsecase) GetPayee(ctx context.Context, id, owner string) (model.Payee, error) {
+	payee, err := p.repo.Get(ctx, id, owner)
+	if err == sql.ErrNoRows {
+		return model.Payee{}, ErrPayeeNotFound
+	}
+	return payee, err
+}
+
+func (p *payeesUsecase) ListPayees(ctx context.Context, req dto.ListPayeesRequest) ([]model.Payee, error) {
+	size := req.Size
+	if size == 0 {
+		size = 20
+	}
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	return p.repo.List(ctx, req.Owner, size, (page-1)*size)
+}
+
+func (p *payeesUsecase) UpdatePayee(ctx context.Context, req dto.UpdatePayeeRequest) (model.Payee, error) {
+	payee, err := p.repo.UpdateNickname(ctx, req.Id, req.Owner, req.Nickname)
+	return payee, payeeError(err)
+}
+
+func (p *payeesUsecase) DeletePayee(ctx context.Context, id, owner string) error {
+	return payeeError(p.repo.Delete(ctx, id, owner))
+}
+
+// payeeError maps the repository errors of a single payee to domain errors.
+func payeeError(err error) error {
+	if err == sql.ErrNoRows {
+		return ErrPayeeNotFound
+	}
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+		return ErrPayeeExists
+	}
+	return err
+}
@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/terajari/bank-api/dto"
+	mockrepo "github.com/terajari/bank-api/mock/repository"
+	"github.com/terajari/bank-api/model"
+)
+
+func TestCreatePayee(t *testing.T) {
+	account := model.Accounts{ID: "acc2", Number: "ID4312345678901234", Owner: "other", Currency: "IDR"}
+
+	type mocks struct {
+		payees   *mockrepo.MockPayeesRepository
+		accounts *mockrepo.MockAccountsRepository
+	}
+
+	testCases := []struct {
+		name    string
+		req     dto.CreatePayeeRequest
+		setup   func(m mocks)
+		wantErr error
+	}{
+		{
+			name: "created for the owner",
+			req:  dto.CreatePayeeRequest{Owner: "fulan", Nickname: "mom", AccountId: "acc2", Currency: "IDR"},
+			setup: func(m mocks) {
+				m.accounts.EXPECT().Get(gomock.Any(), "acc2").Return(account, nil)
+				m.payees.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, payee model.Payee) (model.Payee, error) {
+					if payee.Owner != "fulan" || payee.AccountId != "acc2" || payee.Currency != "IDR" || payee.ID == "" {
+						t.Errorf("Create(%+v)", payee)
+					}
+					return payee, nil
+				})
+			},
+		},
+		{
+			name: "by account number",
+			req:  dto.CreatePayeeRequest{Owner: "fulan", Nickname: "mom", AccountId: "ID43 1234 5678 9012 34", Currency: "IDR"},
+			setup: func(m mocks) {
+				m.accounts.EXPECT().GetByNumber(gomock.Any(), "ID4312345678901234").Return(account, nil)
+				m.payees.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, payee model.Payee) (model.Payee, error) {
+					if payee.AccountId != "acc2" {
+						t.Errorf("AccountId = %q, want acc2", payee.AccountId)
+					}
+					return payee, nil
+				})
+			},
+		},
+		{
+			name: "currency does not match the account",
+			req:  dto.CreatePayeeRequest{Owner: "fulan", Nickname: "mom", AccountId: "acc2", Currency: "USD"},
+			setup: func(m mocks) {
+				m.accounts.EXPECT().Get(gomock.Any(), "acc2").Return(account, nil)
+			},
+			wantErr: ErrInvalidCurrency,
+		},
+		{
+			name: "duplicate",
+			req:  dto.CreatePayeeRequest{Owner: "fulan", Nickname: "mom", AccountId: "acc2", Currency: "IDR"},
+			setup: func(m mocks) {
+				m.accounts.EXPECT().Get(gomock.Any(), "acc2").Return(account, nil)
+				m.payees.EXPECT().Create(gomock.Any(), gomock.Any()).Return(model.Payee{}, &pq.Error{Code: "23505"})
+			},
+			wantErr: ErrPayeeExists,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks{
+				payees:   mockrepo.NewMockPayeesRepository(ctrl),
+				accounts: mockrepo.NewMockAccountsRepository(ctrl),
+			}
+			tc.setup(m)
+
+			_, err := NewPayeesUsecase(m.payees, m.accounts).CreatePayee(context.Background(), tc.req)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("CreatePayee() error = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+// The repository scopes every lookup by owner, so another user's payee is
+// not found rather than forbidden.
+func TestPayeesAreScopedToOwner(t *testing.T) {
+	payee := model.Payee{ID: "payee1", Owner: "fulan", Nickname: "mom", AccountId: "acc2", Currency: "IDR"}
+
+	testCases := []struct {
+		name    string
+		owner   string
+		setup   func(repo *mockrepo.MockPayeesRepository)
+		run     func(uc PayeesUsecase, owner string) error
+		wantErr error
+	}{
+		{
+			name:  "get",
+			owner: "fulan",
+			setup: func(repo *mockrepo.MockPayeesRepository) {
+				repo.EXPECT().Get(gomock.Any(), "payee1", "fulan").Return(payee, nil)
+			},
+			run: func(uc PayeesUsecase, owner string) error {
+				_, err := uc.GetPayee(context.Background(), "payee1", owner)
+				return err
+			},
+		},
+		{
+			name:  "get another user's payee",
+			owner: "other",
+			setup: func(repo *mockrepo.MockPayeesRepository) {
+				repo.EXPECT().Get(gomock.Any(), "payee1", "other").Return(model.Payee{}, sql.ErrNoRows)
+			},
+			run: func(uc PayeesUsecase, owner string) error {
+				_, err := uc.GetPayee(context.Background(), "payee1", owner)
+				return err
+			},
+			wantErr: ErrPayeeNotFound,
+		},
+		{
+			name:  "list",
+			owner: "fulan",
+			setup: func(repo *mockrepo.MockPayeesRepository) {
+				repo.EXPECT().List(gomock.Any(), "fulan", 20, 20).Return([]model.Payee{payee}, nil)
+			},
+			run: func(uc PayeesUsecase, owner string) error {
+				_, err := uc.ListPayees(context.Background(), dto.ListPayeesRequest{Owner: owner, Page: 2})
+				return err
+			},
+		},
+		{
+			name:  "update",
+			owner: "fulan",
+			setup: func(repo *mockrepo.MockPayeesRepository) {
+				repo.EXPECT().UpdateNickname(gomock.Any(), "payee1", "fulan", "mother").Return(payee, nil)
+			},
+			run: func(uc PayeesUsecase, owner string) error {
+				_, err := uc.UpdatePayee(context.Background(), dto.UpdatePayeeRequest{Id: "payee1", Owner: owner, Nickname: "mother"})
+				return err
+			},
+		},
+		{
+			name:  "update another user's payee",
+			owner: "other",
+			setup: func(repo *mockrepo.MockPayeesRepository) {
+				repo.EXPECT().UpdateNickname(gomock.Any(), "payee1", "other", "mother").Return(model.Payee{}, sql.ErrNoRows)
+			},
+			run: func(uc PayeesUsecase, owner string) error {
+				_, err := uc.UpdatePayee(context.Background(), dto.UpdatePayeeRequest{Id: "payee1", Owner: owner, Nickname: "mother"})
+				return err
+			},
+			wantErr: ErrPayeeNotFound,
+		},
+		{
+			name:  "delete",
+			owner: "fulan",
+			setup: func(repo *mockrepo.MockPayeesRepository) {
+				repo.EXPECT().Delete(gomock.Any(), "payee1", "fulan").Return(nil)
+			},
+			run: func(uc PayeesUsecase, owner string) error {
+				return uc.DeletePayee(context.Background(), "payee1", owner)
+			},
+		},
+		{
+			name:  "delete another user's payee",
+			owner: "other",
+			setup: func(repo *mockrepo.MockPayeesRepository) {
+				repo.EXPECT().Delete(gomock.Any(), "payee1", "other").Return(sql.ErrNoRows)
+			},
+			run: func(uc PayeesUsecase, owner string) error {
+				return uc.DeletePayee(context.Background(), "payee1", owner)
+			},
+			wantErr: ErrPayeeNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mockrepo.NewMockPayeesRepository(ctrl)
+			tc.setup(repo)
+
+			err := tc.run(NewPayeesUsecase(repo, mockrepo.NewMockAccountsRepository(ctrl)), tc.owner)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
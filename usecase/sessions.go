@@ -2,11 +2,24 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/terajari/bank-api/apperror"
 	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/metrics"
 	"github.com/terajari/bank-api/model"
 	"github.com/terajari/bank-api/repository"
+	"github.com/terajari/bank-api/token"
+	"github.com/terajari/bank-api/utils"
+)
+
+var (
+	ErrSessionNotFound = apperror.New(apperror.KindNotFound, "session_not_found", "session not found")
+	ErrSessionRevoked  = apperror.New(apperror.KindUnauthorized, "session_revoked", "session is blocked")
+	ErrSessionMismatch = apperror.New(apperror.KindUnauthorized, "session_mismatch", "refresh token does not match the session")
+	ErrSessionExpired  = apperror.New(apperror.KindUnauthorized, "session_expired", "session expired")
 )
 
 type SessionsUsecase interface {
@@ -14,15 +27,20 @@ type SessionsUsecase interface {
 	GetSessions(ctx context.Context, id uuid.UUID) (dto.SessionResponse, error)
 	UpdateBlockStatus(ctx context.Context, req dto.UpdateSessionBlockRequest) error
 	LastSession(ctx context.Context) (dto.SessionResponse, error)
+	RenewAccessToken(ctx context.Context, req dto.RenewAccessTokenRequest) (dto.RenewAccessTokenResponse, error)
 }
 
 type sessionsUsecase struct {
 	sessionsRepo repository.SessionsRepository
+	tokenMaker   token.Maker
+	config       *utils.Config
 }
 
-func NewSessionsUsecase(sessionsRepo repository.SessionsRepository) SessionsUsecase {
+func NewSessionsUsecase(sessionsRepo repository.SessionsRepository, tokenMaker token.Maker, cfg *utils.Config) SessionsUsecase {
 	return &sessionsUsecase{
 		sessionsRepo: sessionsRepo,
+		tokenMaker:   tokenMaker,
+		config:       cfg,
 	}
 }
 
@@ -55,6 +73,9 @@ func (s *sessionsUsecase) AddSessions(ctx context.Context, req dto.AddSessionsRe
 func (s *sessionsUsecase) GetSessions(ctx context.Context, uuid uuid.UUID) (dto.SessionResponse, error) {
 	ss, err := s.sessionsRepo.Get(ctx, uuid)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return dto.SessionResponse{}, ErrSessionNotFound
+		}
 		return dto.SessionResponse{}, err
 	}
 
@@ -82,6 +103,9 @@ func (s *sessionsUsecase) UpdateBlockStatus(ctx context.Context, req dto.UpdateS
 func (s *sessionsUsecase) LastSession(ctx context.Context) (dto.SessionResponse, error) {
 	ss, err := s.sessionsRepo.Last(ctx)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return dto.SessionResponse{}, apperror.ErrSessionBlocked
+		}
 		return dto.SessionResponse{}, err
 	}
 
@@ -96,3 +120,90 @@ func (s *sessionsUsecase) LastSession(ctx context.Context) (dto.SessionResponse,
 		CreatedAt:    ss.CreatedAt,
 	}, nil
 }
+
+// RenewAccessToken issues an access token for a refresh token whose session
+// is still valid and records the renewal on the session. With sliding expiry
+// the session is extended by the refresh token duration, but never beyond
+// SessionMaxLifetime after login, and the refresh token is replaced.
+func (s *sessionsUsecase) RenewAccessToken(ctx context.Context, req dto.RenewAccessTokenRequest) (dto.RenewAccessTokenResponse, error) {
+	response, err := s.renewAccessToken(ctx, req)
+	result := metrics.RenewalRenewed
+	if err != nil {
+		result = metrics.Outcome(err)
+	} else if response.RefreshToken != "" {
+		result = metrics.RenewalExtended
+	}
+	metrics.SessionRenewals.WithLabelValues(result).Inc()
+	return response, err
+}
+
+func (s *sessionsUsecase) renewAccessToken(ctx context.Context, req dto.RenewAccessTokenRequest) (dto.RenewAccessTokenResponse, error) {
+	refreshPayload, err := s.tokenMaker.VerifyToken(req.RefreshToken)
+	if err != nil {
+		return dto.RenewAccessTokenResponse{}, err
+	}
+	if refreshPayload.Purpose != "" {
+		return dto.RenewAccessTokenResponse{}, token.ErrInvalidToken
+	}
+
+	session, err := s.sessionsRepo.Get(ctx, refreshPayload.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return dto.RenewAccessTokenResponse{}, ErrSessionNotFound
+		}
+		return dto.RenewAccessTokenResponse{}, err
+	}
+	if session.IsBlocked {
+		return dto.RenewAccessTokenResponse{}, ErrSessionRevoked
+	}
+	if session.Username != refreshPayload.Username || session.RefreshToken != req.RefreshToken {
+		return dto.RenewAccessTokenResponse{}, ErrSessionMismatch
+	}
+	now := time.Now()
+	if now.After(session.ExpiresAt) {
+		return dto.RenewAccessTokenResponse{}, ErrSessionExpired
+	}
+
+	accessToken, accessPayload, err := s.tokenMaker.CreateToken(refreshPayload.Username, s.config.AccessTokenDuration)
+	if err != nil {
+		return dto.RenewAccessTokenResponse{}, err
+	}
+	response := dto.RenewAccessTokenResponse{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: accessPayload.ExpiredAt,
+	}
+
+	refreshToken, expiresAt := req.RefreshToken, session.ExpiresAt
+	if s.config.SessionSlidingExpiry {
+		slid := slidingExpiry(session.CreatedAt, now, s.config.RefreshTokenDuration, s.config.SessionMaxLifetime)
+		if slid.After(session.ExpiresAt) {
+			newToken, newPayload, err := s.tokenMaker.ReissueToken(refreshPayload, slid.Sub(now))
+			if err != nil {
+				return dto.RenewAccessTokenResponse{}, err
+			}
+			refreshToken, expiresAt = newToken, newPayload.ExpiredAt
+			response.RefreshToken = newToken
+			response.RefreshTokenExpiresAt = &newPayload.ExpiredAt
+		}
+	}
+
+	_, err = s.sessionsRepo.Renew(ctx, session.Id, req.RefreshToken, refreshToken, expiresAt)
+	if err != nil {
+		// The session was blocked or its token rotated since it was read.
+		if err == sql.ErrNoRows {
+			return dto.RenewAccessTokenResponse{}, ErrSessionMismatch
+		}
+		return dto.RenewAccessTokenResponse{}, err
+	}
+	return response, nil
+}
+
+// slidingExpiry is when a session renewed at now expires, capped at
+// maxLifetime after it was created. A maxLifetime of zero means no cap.
+func slidingExpiry(createdAt, now time.Time, duration, maxLifetime time.Duration) time.Time {
+	expiresAt := now.Add(duration)
+	if maxLifetime > 0 && expiresAt.After(createdAt.Add(maxLifetime)) {
+		return createdAt.Add(maxLifetime)
+	}
+	return expiresAt
+}
@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/terajari/bank-api/dto"
+	mockrepo "github.com/terajari/bank-api/mock/repository"
+	mocktoken "github.com/terajari/bank-api/mock/token"
+	"github.com/terajari/bank-api/model"
+	"github.com/terajari/bank-api/token"
+	"github.com/terajari/bank-api/utils"
+)
+
+func TestRenewAccessToken(t *testing.T) {
+	now := time.Now()
+	sessionId := uuid.New()
+	accessExpires := now.Add(20 * time.Minute)
+	slidExpires := now.Add(24 * time.Hour)
+	payload := &token.Payload{ID: sessionId, Username: "fulan1234", ExpiredAt: now.Add(time.Hour)}
+	session := model.Sessions{
+		Id:           sessionId,
+		Username:     "fulan1234",
+		RefreshToken: "refresh",
+		ExpiresAt:    now.Add(time.Hour),
+		CreatedAt:    now.Add(-time.Hour),
+	}
+
+	testCases := []struct {
+		name    string
+		sliding bool
+		setup   func(maker *mocktoken.MockMaker, repo *mockrepo.MockSessionsRepository)
+		want    dto.RenewAccessTokenResponse
+		wantErr error
+	}{
+		{
+			name: "renews access token",
+			setup: func(maker *mocktoken.MockMaker, repo *mockrepo.MockSessionsRepository) {
+				maker.EXPECT().VerifyToken("refresh").Return(payload, nil)
+				repo.EXPECT().Get(gomock.Any(), sessionId).Return(session, nil)
+				maker.EXPECT().CreateToken("fulan1234", 20*time.Minute).Return("access", &token.Payload{ExpiredAt: accessExpires}, nil)
+				repo.EXPECT().Renew(gomock.Any(), sessionId, "refresh", "refresh", session.ExpiresAt).Return(session, nil)
+			},
+			want: dto.RenewAccessTokenResponse{AccessToken: "access", AccessTokenExpiresAt: accessExpires},
+		},
+		{
+			name:    "sliding expiry extends session",
+			sliding: true,
+			setup: func(maker *mocktoken.MockMaker, repo *mockrepo.MockSessionsRepository) {
+				maker.EXPECT().VerifyToken("refresh").Return(payload, nil)
+				repo.EXPECT().Get(gomock.Any(), sessionId).Return(session, nil)
+				maker.EXPECT().CreateToken("fulan1234", 20*time.Minute).Return("access", &token.Payload{ExpiredAt: accessExpires}, nil)
+				maker.EXPECT().ReissueToken(payload, gomock.Any()).Return("refresh2", &token.Payload{ID: sessionId, ExpiredAt: slidExpires}, nil)
+				repo.EXPECT().Renew(gomock.Any(), sessionId, "refresh", "refresh2", slidExpires).Return(session, nil)
+			},
+			want: dto.RenewAccessTokenResponse{
+				AccessToken:           "access",
+				AccessTokenExpiresAt:  accessExpires,
+				RefreshToken:          "refresh2",
+				RefreshTokenExpiresAt: &slidExpires,
+			},
+		},
+		{
+			name:    "sliding expiry stops at max lifetime",
+			sliding: true,
+			setup: func(maker *mocktoken.MockMaker, repo *mockrepo.MockSessionsRepository) {
+				old := session
+				old.CreatedAt = now.Add(-48 * time.Hour)
+				old.ExpiresAt = now.Add(time.Hour)
+				maker.EXPECT().VerifyToken("refresh").Return(payload, nil)
+				repo.EXPECT().Get(gomock.Any(), sessionId).Return(old, nil)
+				maker.EXPECT().CreateToken("fulan1234", 20*time.Minute).Return("access", &token.Payload{ExpiredAt: accessExpires}, nil)
+				repo.EXPECT().Renew(gomock.Any(), sessionId, "refresh", "refresh", old.ExpiresAt).Return(old, nil)
+			},
+			want: dto.RenewAccessTokenResponse{AccessToken: "access", AccessTokenExpiresAt: accessExpires},
+		},
+		{
+			name: "invalid token",
+			setup: func(maker *mocktoken.MockMaker, repo *mockrepo.MockSessionsRepository) {
+				maker.EXPECT().VerifyToken("refresh").Return(nil, token.ErrInvalidToken)
+			},
+			wantErr: token.ErrInvalidToken,
+		},
+		{
+			name: "expired token",
+			setup: func(maker *mocktoken.MockMaker, repo *mockrepo.MockSessionsRepository) {
+				maker.EXPECT().VerifyToken("refresh").Return(nil, token.ErrExpiredToken)
+			},
+			wantErr: token.ErrExpiredToken,
+		},
+		{
+			name: "challenge token",
+			setup: func(maker *mocktoken.MockMaker, repo *mockrepo.MockSessionsRepository) {
+				challenge := *payload
+				challenge.Purpose = token.PurposeMfaChallenge
+				maker.EXPECT().VerifyToken("refresh").Return(&challenge, nil)
+			},
+			wantErr: token.ErrInvalidToken,
+		},
+		{
+			name: "session not found",
+			setup: func(maker *mocktoken.MockMaker, repo *mockrepo.MockSessionsRepository) {
+				maker.EXPECT().VerifyToken("refresh").Return(payload, nil)
+				repo.EXPECT().Get(gomock.Any(), sessionId).Return(model.Sessions{}, sql.ErrNoRows)
+			},
+			wantErr: ErrSessionNotFound,
+		},
+		{
+			name: "blocked session",
+			setup: func(maker *mocktoken.MockMaker, repo *mockrepo.MockSessionsRepository) {
+				blocked := session
+				blocked.IsBlocked = true
+				maker.EXPECT().VerifyToken("refresh").Return(payload, nil)
+				repo.EXPECT().Get(gomock.Any(), sessionId).Return(blocked, nil)
+			},
+			wantErr: ErrSessionRevoked,
+		},
+		{
+			name: "other user",
+			setup: func(maker *mocktoken.MockMaker, repo *mockrepo.MockSessionsRepository) {
+				other := session
+				other.Username = "other"
+				maker.EXPECT().VerifyToken("refresh").Return(payload, nil)
+				repo.EXPECT().Get(gomock.Any(), sessionId).Return(other, nil)
+			},
+			wantErr: ErrSessionMismatch,
+		},
+		{
+			name: "rotated refresh token",
+			setup: func(maker *mocktoken.MockMaker, repo *mockrepo.MockSessionsRepository) {
+				rotated := session
+				rotated.RefreshToken = "refresh2"
+				maker.EXPECT().VerifyToken("refresh").Return(payload, nil)
+				repo.EXPECT().Get(gomock.Any(), sessionId).Return(rotated, nil)
+			},
+			wantErr: ErrSessionMismatch,
+		},
+		{
+			name: "expired session",
+			setup: func(maker *mocktoken.MockMaker, repo *mockrepo.MockSessionsRepository) {
+				expired := session
+				expired.ExpiresAt = now.Add(-time.Minute)
+				maker.EXPECT().VerifyToken("refresh").Return(payload, nil)
+				repo.EXPECT().Get(gomock.Any(), sessionId).Return(expired, nil)
+			},
+			wantErr: ErrSessionExpired,
+		},
+		{
+			name: "concurrent renewal",
+			setup: func(maker *mocktoken.MockMaker, repo *mockrepo.MockSessionsRepository) {
+				maker.EXPECT().VerifyToken("refresh").Return(payload, nil)
+				repo.EXPECT().Get(gomock.Any(), sessionId).Return(session, nil)
+				maker.EXPECT().CreateToken("fulan1234", 20*time.Minute).Return("access", &token.Payload{ExpiredAt: accessExpires}, nil)
+				repo.EXPECT().Renew(gomock.Any(), sessionId, "refresh", "refresh", session.ExpiresAt).Return(model.Sessions{}, sql.ErrNoRows)
+			},
+			wantErr: ErrSessionMismatch,
+		},
+		{
+			name: "repository error",
+			setup: func(maker *mocktoken.MockMaker, repo *mockrepo.MockSessionsRepository) {
+				maker.EXPECT().VerifyToken("refresh").Return(payload, nil)
+				repo.EXPECT().Get(gomock.Any(), sessionId).Return(model.Sessions{}, sql.ErrConnDone)
+			},
+			wantErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			maker := mocktoken.NewMockMaker(ctrl)
+			repo := mockrepo.NewMockSessionsRepository(ctrl)
+			tc.setup(maker, repo)
+
+			uc := NewSessionsUsecase(repo, maker, &utils.Config{
+				AccessTokenDuration:  20 * time.Minute,
+				RefreshTokenDuration: 24 * time.Hour,
+				SessionSlidingExpiry: tc.sliding,
+				SessionMaxLifetime:   24 * time.Hour,
+			})
+			got, err := uc.RenewAccessToken(context.Background(), dto.RenewAccessTokenRequest{RefreshToken: "refresh"})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("RenewAccessToken() error = %v, want %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.AccessToken != tc.want.AccessToken || !got.AccessTokenExpiresAt.Equal(tc.want.AccessTokenExpiresAt) || got.RefreshToken != tc.want.RefreshToken {
+				t.Errorf("RenewAccessToken() = %+v, want %+v", got, tc.want)
+			}
+			if (got.RefreshTokenExpiresAt == nil) != (tc.want.RefreshTokenExpiresAt == nil) {
+				t.Errorf("RefreshTokenExpiresAt = %v, want %v", got.RefreshTokenExpiresAt, tc.want.RefreshTokenExpiresAt)
+			}
+		})
+	}
+}
+
+func TestSlidingExpiry(t *testing.T) {
+	created := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name        string
+		now         time.Time
+		maxLifetime time.Duration
+		want        time.Time
+	}{
+		{
+			name:        "within lifetime",
+			now:         created.Add(time.Hour),
+			maxLifetime: 72 * time.Hour,
+			want:        created.Add(25 * time.Hour),
+		},
+		{
+			name:        "capped",
+			now:         created.Add(60 * time.Hour),
+			maxLifetime: 72 * time.Hour,
+			want:        created.Add(72 * time.Hour),
+		},
+		{
+			name: "no cap",
+			now:  created.Add(1000 * time.Hour),
+			want: created.Add(1024 * time.Hour),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := slidingExpiry(created, tc.now, 24*time.Hour, tc.maxLifetime)
+			if !got.Equal(tc.want) {
+				t.Errorf("slidingExpiry() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -2,55 +2,342 @@ package usecase
 
 import (
 	"context"
-	"fmt"
+	"database/sql"
+	"time"
 
+	"github.com/terajari/bank-api/apperror"
 	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/logging"
+	"github.com/terajari/bank-api/metrics"
 	"github.com/terajari/bank-api/model"
 	"github.com/terajari/bank-api/repository"
+	"github.com/terajari/bank-api/tracing"
 	"github.com/terajari/bank-api/utils"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type TransferUsecase interface {
 	MakeTransfer(ctx context.Context, request dto.MakeTransferRequest) (dto.MakeTransferResponse, error)
+	MakeTransferBatch(ctx context.Context, req dto.BatchTransferRequest) (dto.BatchTransferResult, error)
+	ResumeTransferBatch(ctx context.Context, req dto.BatchTransferRequest, done []dto.BatchItemResult, progress func(dto.BatchTransferResult)) (dto.BatchTransferResult, error)
+	QuoteTransfer(ctx context.Context, request dto.TransferQuoteRequest) (dto.TransferQuoteResponse, error)
+	ListTransfers(ctx context.Context, req dto.TransferHistoryRequest) ([]dto.TransferHistoryItem, error)
+	ListPendingTransfers(ctx context.Context, req dto.ListTransfersRequest) ([]model.Transfer, error)
+	ApproveTransfer(ctx context.Context, id, reviewer string) (dto.MakeTransferResponse, error)
+	RejectTransfer(ctx context.Context, id, reviewer string) (model.Transfer, error)
+	AuthorizeHold(ctx context.Context, req dto.AuthorizeHoldRequest) (model.Hold, error)
+	CaptureHold(ctx context.Context, req dto.CaptureHoldRequest) (dto.CaptureHoldResponse, error)
+	VoidHold(ctx context.Context, id string) (model.Hold, error)
+	GetHold(ctx context.Context, id string) (model.Hold, error)
+	ExpireHolds(ctx context.Context) (int, error)
+}
+
+var (
+	ErrTransferNotFound   = apperror.New(apperror.KindNotFound, "transfer_not_found", "transfer not found")
+	ErrTransferDenied     = apperror.New(apperror.KindForbidden, "transfer_denied", "transfer denied by risk check")
+	ErrTransferNotPending = apperror.New(apperror.KindConflict, "transfer_not_pending", "transfer is not pending review")
+	ErrNotAccountOwner    = apperror.New(apperror.KindForbidden, "not_account_owner", "sender is not authorized to transfer")
+	ErrSelfApproval       = apperror.New(apperror.KindForbidden, "self_approval", "reviewers cannot approve their own transfers")
+	ErrInvalidCurrency    = apperror.ErrInvalidCurrency
+)
+
+// RiskEvaluator scores a transfer before it is committed. A review decision
+// holds the transfer until an admin approves or rejects it.
+type RiskEvaluator interface {
+	Evaluate(ctx context.Context, input model.RiskInput) (model.RiskResult, error)
 }
 
 type transferUsecase struct {
 	accountRepo  repository.AccountsRepository
 	entriesRepo  repository.EntryRepository
 	transferRepo repository.TransferRepository
+	feeRulesRepo repository.FeeRulesRepository
+	holdsRepo    repository.HoldsRepository
+	payeesRepo   repository.PayeesRepository
+	risk         RiskEvaluator
+	config       *utils.Config
 }
 
-func NewTransferUsecase(acc repository.AccountsRepository, ent repository.EntryRepository, tr repository.TransferRepository) TransferUsecase {
-	return &transferUsecase{accountRepo: acc, entriesRepo: ent, transferRepo: tr}
+func NewTransferUsecase(acc repository.AccountsRepository, ent repository.EntryRepository, tr repository.TransferRepository, fr repository.FeeRulesRepository, hr repository.HoldsRepository, pr repository.PayeesRepository, risk RiskEvaluator, cfg *utils.Config) TransferUsecase {
+	return &transferUsecase{accountRepo: acc, entriesRepo: ent, transferRepo: tr, feeRulesRepo: fr, holdsRepo: hr, payeesRepo: pr, risk: risk, config: cfg}
 }
 
-func (t *transferUsecase) MakeTransfer(ctx context.Context, request dto.MakeTransferRequest) (dto.MakeTransferResponse, error) {
-	sender, err := t.accountRepo.Get(ctx, request.SenderId)
+func (t *transferUsecase) MakeTransfer(ctx context.Context, request dto.MakeTransferRequest) (response dto.MakeTransferResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TransferUsecase.MakeTransfer",
+		attribute.String("transfer.sender_id", request.SenderId),
+		attribute.Int64("transfer.amount", request.Amount),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	sender, err := getAccount(ctx, t.accountRepo, request.SenderId)
 	if err != nil {
 		return dto.MakeTransferResponse{}, err
 	}
-	receiver, err := t.accountRepo.Get(ctx, request.ReceiverId)
+	defer func() {
+		metrics.ObserveTransfer(sender.Currency, response.Transfer.Status, request.Amount, err)
+		logger := logging.FromContext(ctx).With("sender_id", request.SenderId, "receiver_id", request.ReceiverId, "payee_id", request.PayeeId, "amount", request.Amount)
+		if err != nil {
+			logger.Info("transfer failed", "error", err)
+			return
+		}
+		logger.Info("transfer made", "transfer_id", response.Transfer.ID, "status", response.Transfer.Status)
+	}()
+
+	arg, totalFee, err := t.prepareTransfer(ctx, sender, request)
+	if err != nil {
+		return dto.MakeTransferResponse{}, err
+	}
+	if sender.AvailableBalance() < request.Amount+totalFee {
+		return dto.MakeTransferResponse{}, apperror.ErrInsufficientFunds.Withf("insufficient balance: %d < %d", sender.AvailableBalance(), request.Amount+totalFee)
+	}
+
+	response, err = t.transferRepo.TransferTx(ctx, arg)
 	if err != nil {
 		return dto.MakeTransferResponse{}, err
 	}
+	return response, nil
+}
+
+// checkNotFrozen fails with apperror.ErrAccountFrozen when one of the
+// accounts is frozen.
+func checkNotFrozen(accounts ...model.Accounts) error {
+	for _, account := range accounts {
+		if account.Frozen() {
+			return apperror.ErrAccountFrozen
+		}
+	}
+	return nil
+}
+
+// prepareTransfer validates a transfer from sender, quotes its fees and runs
+// the risk evaluation. Ownership and currency are checked when the request
+// sets them. A payee is resolved to its account. The payee cooling-off
+// applies to the receiver however it was named and is enforced by the
+// repository together with the transfer.
+func (t *transferUsecase) prepareTransfer(ctx context.Context, sender model.Accounts, request dto.MakeTransferRequest) (repository.TransferTxParams, int64, error) {
+	if request.Owner != "" && sender.Owner != request.Owner {
+		return repository.TransferTxParams{}, 0, ErrNotAccountOwner
+	}
+	if request.Currency != "" && sender.Currency != request.Currency {
+		return repository.TransferTxParams{}, 0, ErrInvalidCurrency
+	}
+
+	if request.PayeeId != "" {
+		payee, err := t.payeesRepo.Get(ctx, request.PayeeId, sender.Owner)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return repository.TransferTxParams{}, 0, ErrPayeeNotFound
+			}
+			return repository.TransferTxParams{}, 0, err
+		}
+		request.ReceiverId = payee.AccountId
+	}
 
+	receiver, err := getAccount(ctx, t.accountRepo, request.ReceiverId)
+	if err != nil {
+		return repository.TransferTxParams{}, 0, err
+	}
+	if err := checkNotFrozen(sender, receiver); err != nil {
+		return repository.TransferTxParams{}, 0, err
+	}
 	if sender.Currency != receiver.Currency {
-		return dto.MakeTransferResponse{}, fmt.Errorf("currency mismatch: %s != %s", sender.Currency, receiver.Currency)
+		return repository.TransferTxParams{}, 0, apperror.ErrCurrencyMismatch.Withf("currency mismatch: %s != %s", sender.Currency, receiver.Currency)
+	}
+
+	fees, totalFee, err := t.quoteFees(ctx, sender, request.Amount)
+	if err != nil {
+		return repository.TransferTxParams{}, 0, err
 	}
-	if sender.Balance < request.Amount {
-		return dto.MakeTransferResponse{}, fmt.Errorf("insufficient balance: %d < %d", sender.Balance, request.Amount)
+
+	assessment, err := t.assessRisk(ctx, sender, receiver.ID, request.Amount)
+	if err != nil {
+		return repository.TransferTxParams{}, 0, err
+	}
+	status := model.TransferStatusCompleted
+	switch assessment.Decision {
+	case model.RiskDeny:
+		return repository.TransferTxParams{}, 0, ErrTransferDenied
+	case model.RiskReview:
+		status = model.TransferStatusPendingReview
+	}
+
+	id := request.TransferId
+	if id == "" {
+		id = utils.GenerateUUID()
+	}
+	return repository.TransferTxParams{
+		Transfer: model.Transfer{
+			ID:          id,
+			SenderId:    sender.ID,
+			ReceiverId:  receiver.ID,
+			Amount:      request.Amount,
+			Status:      status,
+			Description: request.Description,
+			Reference:   request.Reference,
+			Metadata:    request.Metadata,
+			RiskScore:   assessment.Score,
+			RiskReasons: assessment.Reasons,
+		},
+		Fees:       fees,
+		CoolingOff: t.payeeCoolingOff(),
+	}, totalFee, nil
+}
+
+func (t *transferUsecase) payeeCoolingOff() model.PayeeCoolingOff {
+	return model.PayeeCoolingOff{
+		Period:    t.config.PayeeCoolingOff,
+		MaxAmount: t.config.PayeeCoolingOffMaxAmount,
+	}
+}
+
+func (t *transferUsecase) QuoteTransfer(ctx context.Context, request dto.TransferQuoteRequest) (dto.TransferQuoteResponse, error) {
+	sender, err := getAccount(ctx, t.accountRepo, request.SenderId)
+	if err != nil {
+		return dto.TransferQuoteResponse{}, err
+	}
+
+	fees, totalFee, err := t.quoteFees(ctx, sender, request.Amount)
+	if err != nil {
+		return dto.TransferQuoteResponse{}, err
 	}
 
-	transferId := utils.GenerateUUID()
-	response, err := t.transferRepo.TransferTx(ctx, model.Transfer{
-		ID:         transferId,
-		SenderId:   request.SenderId,
-		ReceiverId: request.ReceiverId,
+	quotes := make([]dto.FeeQuote, 0, len(fees))
+	for _, fee := range fees {
+		quotes = append(quotes, dto.FeeQuote{
+			RuleId: fee.RuleID,
+			Name:   fee.Name,
+			Amount: fee.Amount,
+		})
+	}
+
+	return dto.TransferQuoteResponse{
 		Amount:     request.Amount,
+		Currency:   sender.Currency,
+		Fees:       quotes,
+		TotalFee:   totalFee,
+		TotalDebit: request.Amount + totalFee,
+	}, nil
+}
+
+func (t *transferUsecase) assessRisk(ctx context.Context, sender model.Accounts, receiverId string, amount int64) (model.RiskResult, error) {
+	now := time.Now().UTC()
+	paid, err := t.transferRepo.HasPaid(ctx, sender.Owner, receiverId)
+	if err != nil {
+		return model.RiskResult{}, err
+	}
+	recentCount, recentAmount, err := t.transferRepo.RecentActivity(ctx, sender.ID, now.Add(-t.config.RiskVelocityWindow))
+	if err != nil {
+		return model.RiskResult{}, err
+	}
+
+	return t.risk.Evaluate(ctx, model.RiskInput{
+		Username:     sender.Owner,
+		SenderId:     sender.ID,
+		ReceiverId:   receiverId,
+		Currency:     sender.Currency,
+		Amount:       amount,
+		NewPayee:     !paid,
+		Hour:         now.Hour(),
+		RecentCount:  recentCount,
+		RecentAmount: recentAmount,
 	})
+}
+
+// ListTransfers returns the history of req.AccountId, optionally only the
+// transfers with the given reference.
+func (t *transferUsecase) ListTransfers(ctx context.Context, req dto.TransferHistoryRequest) ([]dto.TransferHistoryItem, error) {
+	size := req.Size
+	if size == 0 {
+		size = 20
+	}
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	transfers, err := t.transferRepo.List(ctx, model.TransfersFilter{
+		AccountId: req.AccountId,
+		Reference: req.Reference,
+	}, size, (page-1)*size)
 	if err != nil {
-		fmt.Println("error usecase")
+		return nil, err
+	}
+
+	history := make([]dto.TransferHistoryItem, 0, len(transfers))
+	for _, tr := range transfers {
+		history = append(history, dto.TransferHistoryItem{
+			ID:          tr.ID,
+			SenderId:    tr.SenderId,
+			ReceiverId:  tr.ReceiverId,
+			Amount:      tr.Amount,
+			Status:      tr.Status,
+			Description: tr.Description,
+			Reference:   tr.Reference,
+			Metadata:    tr.Metadata,
+			CreatedAt:   tr.CreatedAt,
+		})
+	}
+	return history, nil
+}
+
+func (t *transferUsecase) ListPendingTransfers(ctx context.Context, req dto.ListTransfersRequest) ([]model.Transfer, error) {
+	size := req.Size
+	if size == 0 {
+		size = 20
+	}
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	return t.transferRepo.ListByStatus(ctx, model.TransferStatusPendingReview, size, (page-1)*size)
+}
+
+// ApproveTransfer posts a held transfer. Fees are quoted again at approval.
+// The owner of the sender account cannot approve it, even as an admin.
+func (t *transferUsecase) ApproveTransfer(ctx context.Context, id, reviewer string) (dto.MakeTransferResponse, error) {
+	transfer, err := t.transferRepo.Get(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return dto.MakeTransferResponse{}, ErrTransferNotFound
+		}
+		return dto.MakeTransferResponse{}, err
+	}
+	if transfer.Status != model.TransferStatusPendingReview {
+		return dto.MakeTransferResponse{}, ErrTransferNotPending
+	}
+
+	sender, err := t.accountRepo.Get(ctx, transfer.SenderId)
+	if err != nil {
+		return dto.MakeTransferResponse{}, err
+	}
+	if sender.Owner == reviewer {
+		return dto.MakeTransferResponse{}, ErrSelfApproval
+	}
+	fees, totalFee, err := t.quoteFees(ctx, sender, transfer.Amount)
+	if err != nil {
+		return dto.MakeTransferResponse{}, err
+	}
+	if sender.AvailableBalance() < transfer.Amount+totalFee {
+		return dto.MakeTransferResponse{}, apperror.ErrInsufficientFunds.Withf("insufficient balance: %d < %d", sender.AvailableBalance(), transfer.Amount+totalFee)
+	}
+
+	response, err := t.transferRepo.ApproveTx(ctx, id, reviewer, fees)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return dto.MakeTransferResponse{}, ErrTransferNotPending
+		}
 		return dto.MakeTransferResponse{}, err
 	}
+	metrics.ObserveTransfer(sender.Currency, metrics.TransferApproved, transfer.Amount, nil)
 	return response, nil
 }
+
+func (t *transferUsecase) RejectTransfer(ctx context.Context, id, reviewer string) (model.Transfer, error) {
+	transfer, err := t.transferRepo.Reject(ctx, id, reviewer)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.Transfer{}, ErrTransferNotPending
+		}
+		return model.Transfer{}, err
+	}
+	return transfer, nil
+}
@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/terajari/bank-api/apperror"
+	"github.com/terajari/bank-api/dto"
+	mockrepo "github.com/terajari/bank-api/mock/repository"
+	"github.com/terajari/bank-api/model"
+	"github.com/terajari/bank-api/repository"
+	"github.com/terajari/bank-api/utils"
+)
+
+func TestApproveTransfer(t *testing.T) {
+	pending := model.Transfer{ID: "tr1", SenderId: "acc1", ReceiverId: "acc2", Amount: 1000, Status: model.TransferStatusPendingReview}
+	sender := model.Accounts{ID: "acc1", Owner: "fulan", Balance: 5000, Currency: "IDR"}
+
+	type mocks struct {
+		accounts  *mockrepo.MockAccountsRepository
+		transfers *mockrepo.MockTransferRepository
+		fees      *mockrepo.MockFeeRulesRepository
+	}
+
+	testCases := []struct {
+		name     string
+		reviewer string
+		setup    func(m mocks)
+		wantErr  error
+	}{
+		{
+			name:     "approved",
+			reviewer: "admin",
+			setup: func(m mocks) {
+				m.transfers.EXPECT().Get(gomock.Any(), "tr1").Return(pending, nil)
+				m.accounts.EXPECT().Get(gomock.Any(), "acc1").Return(sender, nil)
+				m.fees.EXPECT().ListActive(gomock.Any(), "IDR", gomock.Any()).Return(nil, nil)
+				m.transfers.EXPECT().ApproveTx(gomock.Any(), "tr1", "admin", gomock.Any()).
+					Return(dto.MakeTransferResponse{Transfer: model.Transfer{ID: "tr1", Status: model.TransferStatusCompleted}}, nil)
+			},
+		},
+		{
+			name:     "own transfer",
+			reviewer: "fulan",
+			setup: func(m mocks) {
+				m.transfers.EXPECT().Get(gomock.Any(), "tr1").Return(pending, nil)
+				m.accounts.EXPECT().Get(gomock.Any(), "acc1").Return(sender, nil)
+			},
+			wantErr: ErrSelfApproval,
+		},
+		{
+			name:     "not found",
+			reviewer: "admin",
+			setup: func(m mocks) {
+				m.transfers.EXPECT().Get(gomock.Any(), "tr1").Return(model.Transfer{}, sql.ErrNoRows)
+			},
+			wantErr: ErrTransferNotFound,
+		},
+		{
+			name:     "already reviewed",
+			reviewer: "admin",
+			setup: func(m mocks) {
+				completed := pending
+				completed.Status = model.TransferStatusCompleted
+				m.transfers.EXPECT().Get(gomock.Any(), "tr1").Return(completed, nil)
+			},
+			wantErr: ErrTransferNotPending,
+		},
+		{
+			name:     "insufficient funds",
+			reviewer: "admin",
+			setup: func(m mocks) {
+				poor := sender
+				poor.Balance = 500
+				m.transfers.EXPECT().Get(gomock.Any(), "tr1").Return(pending, nil)
+				m.accounts.EXPECT().Get(gomock.Any(), "acc1").Return(poor, nil)
+				m.fees.EXPECT().ListActive(gomock.Any(), "IDR", gomock.Any()).Return(nil, nil)
+			},
+			wantErr: apperror.ErrInsufficientFunds,
+		},
+		{
+			name:     "receiver frozen while pending",
+			reviewer: "admin",
+			setup: func(m mocks) {
+				m.transfers.EXPECT().Get(gomock.Any(), "tr1").Return(pending, nil)
+				m.accounts.EXPECT().Get(gomock.Any(), "acc1").Return(sender, nil)
+				m.fees.EXPECT().ListActive(gomock.Any(), "IDR", gomock.Any()).Return(nil, nil)
+				m.transfers.EXPECT().ApproveTx(gomock.Any(), "tr1", "admin", gomock.Any()).Return(dto.MakeTransferResponse{}, repository.ErrAccountFrozen)
+			},
+			wantErr: apperror.ErrAccountFrozen,
+		},
+		{
+			name:     "reviewed concurrently",
+			reviewer: "admin",
+			setup: func(m mocks) {
+				m.transfers.EXPECT().Get(gomock.Any(), "tr1").Return(pending, nil)
+				m.accounts.EXPECT().Get(gomock.Any(), "acc1").Return(sender, nil)
+				m.fees.EXPECT().ListActive(gomock.Any(), "IDR", gomock.Any()).Return(nil, nil)
+				m.transfers.EXPECT().ApproveTx(gomock.Any(), "tr1", "admin", gomock.Any()).Return(dto.MakeTransferResponse{}, sql.ErrNoRows)
+			},
+			wantErr: ErrTransferNotPending,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks{
+				accounts:  mockrepo.NewMockAccountsRepository(ctrl),
+				transfers: mockrepo.NewMockTransferRepository(ctrl),
+				fees:      mockrepo.NewMockFeeRulesRepository(ctrl),
+			}
+			tc.setup(m)
+
+			uc := NewTransferUsecase(m.accounts, nil, m.transfers, m.fees, nil, nil, nil, &utils.Config{})
+			got, err := uc.ApproveTransfer(context.Background(), "tr1", tc.reviewer)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("ApproveTransfer() error = %v, want %v", err, tc.wantErr)
+			}
+			if err == nil && got.Transfer.Status != model.TransferStatusCompleted {
+				t.Errorf("status = %q, want %q", got.Transfer.Status, model.TransferStatusCompleted)
+			}
+		})
+	}
+}
+
+func TestRejectTransfer(t *testing.T) {
+	testCases := []struct {
+		name    string
+		setup   func(transfers *mockrepo.MockTransferRepository)
+		wantErr error
+	}{
+		{
+			name: "rejected",
+			setup: func(transfers *mockrepo.MockTransferRepository) {
+				transfers.EXPECT().Reject(gomock.Any(), "tr1", "admin").
+					Return(model.Transfer{ID: "tr1", Status: model.TransferStatusRejected}, nil)
+			},
+		},
+		{
+			name: "not pending",
+			setup: func(transfers *mockrepo.MockTransferRepository) {
+				transfers.EXPECT().Reject(gomock.Any(), "tr1", "admin").Return(model.Transfer{}, sql.ErrNoRows)
+			},
+			wantErr: ErrTransferNotPending,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			transfers := mockrepo.NewMockTransferRepository(ctrl)
+			tc.setup(transfers)
+
+			uc := NewTransferUsecase(nil, nil, transfers, nil, nil, nil, nil, &utils.Config{})
+			got, err := uc.RejectTransfer(context.Background(), "tr1", "admin")
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("RejectTransfer() error = %v, want %v", err, tc.wantErr)
+			}
+			if err == nil && got.Status != model.TransferStatusRejected {
+				t.Errorf("status = %q, want %q", got.Status, model.TransferStatusRejected)
+			}
+		})
+	}
+}
+
+func TestPrepareTransferToPayee(t *testing.T) {
+	sender := model.Accounts{ID: "acc1", Owner: "fulan", Balance: 5000, Currency: "IDR"}
+	req := dto.MakeTransferRequest{SenderId: "acc1", PayeeId: "payee1", Amount: 1000, Currency: "IDR", Owner: "fulan"}
+
+	type mocks struct {
+		accounts *mockrepo.MockAccountsRepository
+		payees   *mockrepo.MockPayeesRepository
+	}
+
+	testCases := []struct {
+		name    string
+		setup   func(m mocks)
+		wantErr error
+	}{
+		{
+			name: "another user's payee",
+			setup: func(m mocks) {
+				m.payees.EXPECT().Get(gomock.Any(), "payee1", "fulan").Return(model.Payee{}, sql.ErrNoRows)
+			},
+			wantErr: ErrPayeeNotFound,
+		},
+		{
+			name: "payee account in another currency",
+			setup: func(m mocks) {
+				m.payees.EXPECT().Get(gomock.Any(), "payee1", "fulan").
+					Return(model.Payee{ID: "payee1", Owner: "fulan", AccountId: "acc2", Currency: "USD"}, nil)
+				m.accounts.EXPECT().Get(gomock.Any(), "acc2").Return(model.Accounts{ID: "acc2", Owner: "other", Currency: "USD"}, nil)
+			},
+			wantErr: apperror.ErrCurrencyMismatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks{
+				accounts: mockrepo.NewMockAccountsRepository(ctrl),
+				payees:   mockrepo.NewMockPayeesRepository(ctrl),
+			}
+			tc.setup(m)
+
+			uc := &transferUsecase{accountRepo: m.accounts, payeesRepo: m.payees, config: &utils.Config{}}
+			if _, _, err := uc.prepareTransfer(context.Background(), sender, req); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("prepareTransfer() error = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
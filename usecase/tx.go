@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/terajari/bank-api/repository"
+)
+
+// UnitOfWork runs fn with repositories bound to a single transaction that is
+// committed only when fn returns nil.
+type UnitOfWork interface {
+	WithTx(ctx context.Context, fn func(repos repository.Repositories) error) error
+}
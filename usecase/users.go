@@ -2,9 +2,13 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 
+	"github.com/terajari/bank-api/apperror"
 	"github.com/terajari/bank-api/dto"
+	"github.com/terajari/bank-api/logging"
+	"github.com/terajari/bank-api/metrics"
 	"github.com/terajari/bank-api/model"
 	"github.com/terajari/bank-api/repository"
 	"github.com/terajari/bank-api/utils"
@@ -13,29 +17,37 @@ import (
 type UsersUsecase interface {
 	CreateUser(ctx context.Context, req dto.CreateUserRequest) (dto.UserReponse, error)
 	Login(ctx context.Context, req dto.LoginUserRequest) (model.Users, error)
+	GetUser(ctx context.Context, username string) (model.Users, error)
 }
 
 type usersUsecase struct {
-	repo repository.UsersRepository
+	repo   repository.UsersRepository
+	hasher utils.PasswordHasher
 }
 
-func NewUsersUsecase(repo repository.UsersRepository) UsersUsecase {
+func NewUsersUsecase(repo repository.UsersRepository, hasher utils.PasswordHasher) UsersUsecase {
 	return &usersUsecase{
-		repo: repo,
+		repo:   repo,
+		hasher: hasher,
 	}
 }
 
 func (u *usersUsecase) CreateUser(ctx context.Context, req dto.CreateUserRequest) (dto.UserReponse, error) {
-	hashedPwd, err := utils.HashPasswrod(req.Password)
+	hashedPwd, err := u.hasher.Hash(req.Password)
 	if err != nil {
 		return dto.UserReponse{}, err
 	}
 
+	role := req.Role
+	if role == "" {
+		role = model.RoleCustomer
+	}
 	user, err := u.repo.Create(ctx, model.Users{
 		Username:       req.Username,
 		HashedPassword: hashedPwd,
 		FullName:       req.FullName,
 		Email:          req.Email,
+		Role:           role,
 	})
 	if err != nil {
 		return dto.UserReponse{}, err
@@ -44,21 +56,63 @@ func (u *usersUsecase) CreateUser(ctx context.Context, req dto.CreateUserRequest
 		Username:     user.Username,
 		FullName:     user.FullName,
 		Email:        user.Email,
+		Role:         user.Role,
 		PwdChangedAt: user.PasswordChangedAt,
 		CreatedAt:    user.CreatedAt,
 	}, nil
 }
 
 func (u *usersUsecase) Login(ctx context.Context, req dto.LoginUserRequest) (model.Users, error) {
+	user, err := u.login(ctx, req)
+	result := metrics.LoginSuccess
+	if errors.Is(err, apperror.ErrInvalidCredentials) {
+		result = metrics.LoginFailure
+	} else if err != nil {
+		result = metrics.OutcomeError
+	}
+	metrics.Logins.WithLabelValues(result).Inc()
+	return user, err
+}
+
+func (u *usersUsecase) login(ctx context.Context, req dto.LoginUserRequest) (model.Users, error) {
 	user, err := u.repo.Get(ctx, req.Username)
 	if err != nil {
-		return model.Users{}, errors.New("username or password incorrect")
+		if err == sql.ErrNoRows {
+			return model.Users{}, apperror.ErrInvalidCredentials
+		}
+		return model.Users{}, err
 	}
 
-	err = utils.CheckPwd(req.Password, user.HashedPassword)
+	err = u.hasher.Check(req.Password, user.HashedPassword)
 	if err != nil {
-		return model.Users{}, errors.New("username or password incorrect")
+		if errors.Is(err, utils.ErrPasswordMismatch) {
+			return model.Users{}, apperror.ErrInvalidCredentials
+		}
+		return model.Users{}, err
+	}
+
+	if u.hasher.NeedsRehash(user.HashedPassword) {
+		u.rehash(ctx, &user, req.Password)
 	}
 
 	return user, nil
 }
+
+func (u *usersUsecase) GetUser(ctx context.Context, username string) (model.Users, error) {
+	return u.repo.Get(ctx, username)
+}
+
+// rehash upgrades the stored hash to the current algorithm and parameters.
+// A failure is logged only, the login itself already succeeded.
+func (u *usersUsecase) rehash(ctx context.Context, user *model.Users, pwd string) {
+	hashedPwd, err := u.hasher.Hash(pwd)
+	if err != nil {
+		logging.FromContext(ctx).Error("cannot rehash password", "username", user.Username, "error", err)
+		return
+	}
+	if err := u.repo.UpdateHashedPassword(ctx, user.Username, hashedPwd); err != nil {
+		logging.FromContext(ctx).Error("cannot store rehashed password", "username", user.Username, "error", err)
+		return
+	}
+	user.HashedPassword = hashedPwd
+}
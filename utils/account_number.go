@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+
+	"github.com/terajari/bank-api/apperror"
+)
+
+// Account numbers follow the IBAN layout of ISO 13616: a two letter prefix,
+// two mod-97 check digits and a numeric basic account number.
+const (
+	AccountNumberPrefix = "ID"
+	accountNumberBBAN   = 14
+	AccountNumberLength = len(AccountNumberPrefix) + 2 + accountNumberBBAN
+)
+
+var ErrInvalidAccountNumber = apperror.New(apperror.KindInvalid, "invalid_account_number", "invalid account number")
+
+// GenerateAccountNumber returns a random account number with valid check digits.
+func GenerateAccountNumber() (string, error) {
+	bban := make([]byte, accountNumberBBAN)
+	for i := range bban {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		bban[i] = byte('0' + n.Int64())
+	}
+	check := 98 - mod97(string(bban)+AccountNumberPrefix+"00")
+	return AccountNumberPrefix + string(rune('0'+check/10)) + string(rune('0'+check%10)) + string(bban), nil
+}
+
+// NormalizeAccountNumber removes the spaces and dashes people type to group
+// the digits and upper-cases the prefix.
+func NormalizeAccountNumber(s string) string {
+	s = strings.ToUpper(s)
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// IsAccountNumber reports whether s is meant as an account number, as opposed
+// to an account ID. Account IDs never start with the prefix, so anything that
+// does is an account number, possibly mistyped. It does not verify the length
+// or the check digits.
+func IsAccountNumber(s string) bool {
+	return strings.HasPrefix(NormalizeAccountNumber(s), AccountNumberPrefix)
+}
+
+// ParseAccountNumber normalizes s and verifies its length and check digits.
+func ParseAccountNumber(s string) (string, error) {
+	s = NormalizeAccountNumber(s)
+	if len(s) != AccountNumberLength || !IsAccountNumber(s) {
+		return "", ErrInvalidAccountNumber
+	}
+	for _, r := range s[len(AccountNumberPrefix):] {
+		if r < '0' || r > '9' {
+			return "", ErrInvalidAccountNumber
+		}
+	}
+	rearranged := s[len(AccountNumberPrefix)+2:] + s[:len(AccountNumberPrefix)+2]
+	if mod97(rearranged) != 1 {
+		return "", ErrInvalidAccountNumber
+	}
+	return s, nil
+}
+
+// mod97 computes the ISO 7064 MOD 97-10 remainder of s, where letters count
+// as two digits (A = 10 ... Z = 35).
+func mod97(s string) int {
+	rem := 0
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			rem = (rem*10 + int(r-'0')) % 97
+		case r >= 'A' && r <= 'Z':
+			rem = (rem*100 + int(r-'A'+10)) % 97
+		}
+	}
+	return rem
+}
@@ -0,0 +1,69 @@
+package utils
+
+import "testing"
+
+func TestGenerateAccountNumber(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		number, err := GenerateAccountNumber()
+		if err != nil {
+			t.Fatalf("GenerateAccountNumber() error = %v", err)
+		}
+		if len(number) != AccountNumberLength {
+			t.Fatalf("GenerateAccountNumber() = %q, want length %d", number, AccountNumberLength)
+		}
+		if _, err := ParseAccountNumber(number); err != nil {
+			t.Fatalf("ParseAccountNumber(%q) error = %v", number, err)
+		}
+	}
+}
+
+func TestIsAccountNumber(t *testing.T) {
+	testCases := []struct {
+		input string
+		want  bool
+	}{
+		{input: "ID4312345678901234", want: true},
+		{input: "id43 1234-5678 9012 34", want: true},
+		// A digit too few is still meant as an account number, so it is
+		// rejected by ParseAccountNumber instead of looked up as an ID.
+		{input: "ID431234567890123", want: true},
+		{input: "cf4177e5-9a09-47a7-89c3-e6143a32a2d7", want: false},
+		{input: "sys-settlement-idr", want: false},
+	}
+
+	for _, tc := range testCases {
+		if got := IsAccountNumber(tc.input); got != tc.want {
+			t.Errorf("IsAccountNumber(%q) = %v, want %v", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestParseAccountNumber(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "valid", input: "ID43123456789012 34", want: "ID4312345678901234"},
+		{name: "grouped lower case", input: "id43 1234-5678 9012 34", want: "ID4312345678901234"},
+		{name: "single digit typo", input: "ID4312345678901244", wantErr: true},
+		{name: "swapped digits", input: "ID4321345678901234", wantErr: true},
+		{name: "too short", input: "ID931234", wantErr: true},
+		{name: "digit missing", input: "ID431234567890123", wantErr: true},
+		{name: "letters in number", input: "ID43123456789012AB", wantErr: true},
+		{name: "uuid", input: "cf4177e5-9a09-47a7-89c3-e6143a32a2d7", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseAccountNumber(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseAccountNumber(%q) error = %v, wantErr %v", tc.input, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("ParseAccountNumber(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
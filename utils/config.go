@@ -1,6 +1,10 @@
 package utils
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -9,20 +13,268 @@ import (
 type Config struct {
 	DBSource             string        `mapstructure:"DB_SOURCE"`
 	DBDriver             string        `mapstructure:"DB_DRIVER"`
+	DBMaxOpenConns       int           `mapstructure:"DB_MAX_OPEN_CONNS"`
+	DBMaxIdleConns       int           `mapstructure:"DB_MAX_IDLE_CONNS"`
+	DBConnMaxLifetime    time.Duration `mapstructure:"DB_CONN_MAX_LIFETIME"`
+	DBConnMaxIdleTime    time.Duration `mapstructure:"DB_CONN_MAX_IDLE_TIME"`
+	DBStatementTimeout   time.Duration `mapstructure:"DB_STATEMENT_TIMEOUT"`
+	DBConnectTimeout     time.Duration `mapstructure:"DB_CONNECT_TIMEOUT"`
+	DBConnectBackoff     time.Duration `mapstructure:"DB_CONNECT_BACKOFF"`
+	AutoMigrate          bool          `mapstructure:"AUTO_MIGRATE"`
+	MigrationLockTimeout time.Duration `mapstructure:"MIGRATION_LOCK_TIMEOUT"`
 	HTTPServer           string        `mapstructure:"HTTP_SERVER"`
 	TokenSymmtricKey     string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
 	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
 	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	MfaIssuer            string        `mapstructure:"MFA_ISSUER"`
+	MfaChallengeDuration time.Duration `mapstructure:"MFA_CHALLENGE_DURATION"`
+	MfaStepUpAmount      int64         `mapstructure:"MFA_STEP_UP_AMOUNT"`
+	MfaMaxAttempts       int           `mapstructure:"MFA_MAX_ATTEMPTS"`
+	MfaLockout           time.Duration `mapstructure:"MFA_LOCKOUT"`
+
+	PasswordHashAlgorithm string `mapstructure:"PASSWORD_HASH_ALGORITHM"`
+	BcryptCost            int    `mapstructure:"BCRYPT_COST"`
+	Argon2Memory          uint32 `mapstructure:"ARGON2_MEMORY"`
+	Argon2Iterations      uint32 `mapstructure:"ARGON2_ITERATIONS"`
+	Argon2Parallelism     uint8  `mapstructure:"ARGON2_PARALLELISM"`
+	Argon2SaltLength      uint32 `mapstructure:"ARGON2_SALT_LENGTH"`
+	Argon2KeyLength       uint32 `mapstructure:"ARGON2_KEY_LENGTH"`
+	PasswordMinLength     int    `mapstructure:"PASSWORD_MIN_LENGTH"`
+	PasswordMaxLength     int    `mapstructure:"PASSWORD_MAX_LENGTH"`
+	PasswordBreachedList  string `mapstructure:"PASSWORD_BREACHED_LIST"`
+
+	InterestAccrualEnabled bool `mapstructure:"INTEREST_ACCRUAL_ENABLED"`
+	InterestDryRun         bool `mapstructure:"INTEREST_DRY_RUN"`
+
+	RiskRulesFile      string        `mapstructure:"RISK_RULES_FILE"`
+	RiskVelocityWindow time.Duration `mapstructure:"RISK_VELOCITY_WINDOW"`
+
+	HoldDefaultTTL     time.Duration `mapstructure:"HOLD_DEFAULT_TTL"`
+	HoldMaxTTL         time.Duration `mapstructure:"HOLD_MAX_TTL"`
+	HoldExpiryInterval time.Duration `mapstructure:"HOLD_EXPIRY_INTERVAL"`
+
+	BatchMaxItems       int           `mapstructure:"BATCH_MAX_ITEMS"`
+	BatchAsyncThreshold int           `mapstructure:"BATCH_ASYNC_THRESHOLD"`
+	BatchPollInterval   time.Duration `mapstructure:"BATCH_POLL_INTERVAL"`
+	BatchJobLease       time.Duration `mapstructure:"BATCH_JOB_LEASE"`
+	BatchJobMaxAttempts int           `mapstructure:"BATCH_JOB_MAX_ATTEMPTS"`
+
+	LedgerVerifyInterval time.Duration `mapstructure:"LEDGER_VERIFY_INTERVAL"`
+
+	PayeeCoolingOff          time.Duration `mapstructure:"PAYEE_COOLING_OFF"`
+	PayeeCoolingOffMaxAmount int64         `mapstructure:"PAYEE_COOLING_OFF_MAX_AMOUNT"`
+
+	SessionSlidingExpiry bool          `mapstructure:"SESSION_SLIDING_EXPIRY"`
+	SessionMaxLifetime   time.Duration `mapstructure:"SESSION_MAX_LIFETIME"`
+
+	HTTPReadTimeout       time.Duration `mapstructure:"HTTP_READ_TIMEOUT"`
+	HTTPReadHeaderTimeout time.Duration `mapstructure:"HTTP_READ_HEADER_TIMEOUT"`
+	HTTPWriteTimeout      time.Duration `mapstructure:"HTTP_WRITE_TIMEOUT"`
+	HTTPIdleTimeout       time.Duration `mapstructure:"HTTP_IDLE_TIMEOUT"`
+	HTTPMaxHeaderBytes    int           `mapstructure:"HTTP_MAX_HEADER_BYTES"`
+	HTTPMaxBodyBytes      int64         `mapstructure:"HTTP_MAX_BODY_BYTES"`
+	ShutdownTimeout       time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+	ShutdownDrainDelay    time.Duration `mapstructure:"SHUTDOWN_DRAIN_DELAY"`
+
+	LogLevel string `mapstructure:"LOG_LEVEL"`
+
+	TracingExporter     string  `mapstructure:"TRACING_EXPORTER"`
+	TracingFile         string  `mapstructure:"TRACING_FILE"`
+	TracingOTLPEndpoint string  `mapstructure:"TRACING_OTLP_ENDPOINT"`
+	TracingOTLPInsecure bool    `mapstructure:"TRACING_OTLP_INSECURE"`
+	TracingSampleRatio  float64 `mapstructure:"TRACING_SAMPLE_RATIO"`
 }
 
 func LoadConfig(filepath string) (config Config, err error) {
 	viper.SetConfigFile(filepath)
 	viper.AutomaticEnv()
 
+	viper.SetDefault("DB_MAX_OPEN_CONNS", 25)
+	viper.SetDefault("DB_MAX_IDLE_CONNS", 10)
+	viper.SetDefault("DB_CONN_MAX_LIFETIME", 30*time.Minute)
+	viper.SetDefault("DB_CONN_MAX_IDLE_TIME", 5*time.Minute)
+	viper.SetDefault("DB_STATEMENT_TIMEOUT", 30*time.Second)
+	viper.SetDefault("DB_CONNECT_TIMEOUT", 30*time.Second)
+	viper.SetDefault("DB_CONNECT_BACKOFF", 500*time.Millisecond)
+	viper.SetDefault("AUTO_MIGRATE", false)
+	viper.SetDefault("MIGRATION_LOCK_TIMEOUT", 5*time.Minute)
+
+	viper.SetDefault("MFA_ISSUER", "bank-api")
+	viper.SetDefault("MFA_CHALLENGE_DURATION", 5*time.Minute)
+	viper.SetDefault("MFA_STEP_UP_AMOUNT", 0)
+	viper.SetDefault("MFA_MAX_ATTEMPTS", 5)
+	viper.SetDefault("MFA_LOCKOUT", 15*time.Minute)
+
+	viper.SetDefault("PASSWORD_HASH_ALGORITHM", HashAlgorithmArgon2id)
+	viper.SetDefault("BCRYPT_COST", 10)
+	viper.SetDefault("ARGON2_MEMORY", 64*1024)
+	viper.SetDefault("ARGON2_ITERATIONS", 3)
+	viper.SetDefault("ARGON2_PARALLELISM", 2)
+	viper.SetDefault("ARGON2_SALT_LENGTH", 16)
+	viper.SetDefault("ARGON2_KEY_LENGTH", 32)
+	viper.SetDefault("PASSWORD_MIN_LENGTH", 8)
+	viper.SetDefault("PASSWORD_MAX_LENGTH", 72)
+	viper.SetDefault("PASSWORD_BREACHED_LIST", "")
+
+	viper.SetDefault("INTEREST_ACCRUAL_ENABLED", false)
+	viper.SetDefault("INTEREST_DRY_RUN", false)
+
+	viper.SetDefault("RISK_RULES_FILE", "")
+	viper.SetDefault("RISK_VELOCITY_WINDOW", time.Hour)
+
+	viper.SetDefault("HOLD_DEFAULT_TTL", 7*24*time.Hour)
+	viper.SetDefault("HOLD_MAX_TTL", 30*24*time.Hour)
+	viper.SetDefault("HOLD_EXPIRY_INTERVAL", time.Minute)
+
+	viper.SetDefault("BATCH_MAX_ITEMS", 1000)
+	viper.SetDefault("BATCH_ASYNC_THRESHOLD", 50)
+	viper.SetDefault("BATCH_POLL_INTERVAL", 2*time.Second)
+	viper.SetDefault("BATCH_JOB_LEASE", time.Minute)
+	viper.SetDefault("BATCH_JOB_MAX_ATTEMPTS", 3)
+
+	viper.SetDefault("LEDGER_VERIFY_INTERVAL", 0)
+
+	viper.SetDefault("PAYEE_COOLING_OFF", 24*time.Hour)
+	viper.SetDefault("PAYEE_COOLING_OFF_MAX_AMOUNT", 100000)
+
+	viper.SetDefault("SESSION_SLIDING_EXPIRY", false)
+	viper.SetDefault("SESSION_MAX_LIFETIME", 30*24*time.Hour)
+
+	viper.SetDefault("HTTP_READ_TIMEOUT", 15*time.Second)
+	viper.SetDefault("HTTP_READ_HEADER_TIMEOUT", 5*time.Second)
+	viper.SetDefault("HTTP_WRITE_TIMEOUT", time.Minute)
+	viper.SetDefault("HTTP_IDLE_TIMEOUT", 2*time.Minute)
+	viper.SetDefault("HTTP_MAX_HEADER_BYTES", 1<<20)
+	viper.SetDefault("HTTP_MAX_BODY_BYTES", 1<<20)
+	viper.SetDefault("SHUTDOWN_TIMEOUT", 30*time.Second)
+	viper.SetDefault("SHUTDOWN_DRAIN_DELAY", 0)
+
+	viper.SetDefault("LOG_LEVEL", "info")
+
+	viper.SetDefault("TRACING_EXPORTER", "none")
+	viper.SetDefault("TRACING_FILE", "")
+	viper.SetDefault("TRACING_OTLP_ENDPOINT", "localhost:4318")
+	viper.SetDefault("TRACING_OTLP_INSECURE", false)
+	viper.SetDefault("TRACING_SAMPLE_RATIO", 1.0)
+
 	err = viper.ReadInConfig()
 	if err != nil {
 		return
 	}
 	err = viper.Unmarshal(&config)
+	if err != nil {
+		return
+	}
+	err = config.Validate()
 	return
 }
+
+// supportedDBDriver is the only driver the repositories' SQL is written for.
+const supportedDBDriver = "postgres"
+
+// Validate reports every setting that cannot work, so that a misconfigured
+// deployment fails on startup rather than on its first query.
+func (c Config) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	switch c.DBDriver {
+	case "":
+		invalid("DB_DRIVER is required, use %q", supportedDBDriver)
+	case supportedDBDriver:
+	default:
+		invalid("DB_DRIVER %q is not supported, use %q", c.DBDriver, supportedDBDriver)
+	}
+	if err := validateDBSource(c.DBSource); err != nil {
+		errs = append(errs, err)
+	}
+
+	if c.DBMaxOpenConns < 0 {
+		invalid("DB_MAX_OPEN_CONNS must not be negative, got %d", c.DBMaxOpenConns)
+	}
+	if c.DBMaxIdleConns < 0 {
+		invalid("DB_MAX_IDLE_CONNS must not be negative, got %d", c.DBMaxIdleConns)
+	}
+	if c.DBMaxOpenConns > 0 && c.DBMaxIdleConns > c.DBMaxOpenConns {
+		invalid("DB_MAX_IDLE_CONNS (%d) must not exceed DB_MAX_OPEN_CONNS (%d)", c.DBMaxIdleConns, c.DBMaxOpenConns)
+	}
+	for _, d := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"DB_CONN_MAX_LIFETIME", c.DBConnMaxLifetime},
+		{"DB_CONN_MAX_IDLE_TIME", c.DBConnMaxIdleTime},
+		{"DB_STATEMENT_TIMEOUT", c.DBStatementTimeout},
+		{"DB_CONNECT_TIMEOUT", c.DBConnectTimeout},
+		{"DB_CONNECT_BACKOFF", c.DBConnectBackoff},
+		{"MIGRATION_LOCK_TIMEOUT", c.MigrationLockTimeout},
+		{"LEDGER_VERIFY_INTERVAL", c.LedgerVerifyInterval},
+	} {
+		if d.value < 0 {
+			invalid("%s must not be negative, got %s", d.name, d.value)
+		}
+	}
+	if c.DBConnectTimeout > 0 && c.DBConnectBackoff <= 0 {
+		invalid("DB_CONNECT_BACKOFF must be positive when DB_CONNECT_TIMEOUT is set")
+	}
+	if c.BatchJobLease < time.Second {
+		invalid("BATCH_JOB_LEASE must be at least 1s, got %s", c.BatchJobLease)
+	}
+	if c.BatchJobMaxAttempts < 1 {
+		invalid("BATCH_JOB_MAX_ATTEMPTS must be at least 1, got %d", c.BatchJobMaxAttempts)
+	}
+	if c.MfaMaxAttempts < 1 {
+		invalid("MFA_MAX_ATTEMPTS must be at least 1, got %d", c.MfaMaxAttempts)
+	}
+	if c.MfaLockout < time.Second {
+		invalid("MFA_LOCKOUT must be at least 1s, got %s", c.MfaLockout)
+	}
+	if c.DBStatementTimeout > 0 && c.DBStatementTimeout < time.Millisecond {
+		invalid("DB_STATEMENT_TIMEOUT must be at least 1ms, got %s", c.DBStatementTimeout)
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateDBSource accepts a postgres:// or postgresql:// URL or a
+// space-separated list of key=value settings. The URL must name a host and a
+// database.
+func validateDBSource(source string) error {
+	if strings.TrimSpace(source) == "" {
+		return errors.New("DB_SOURCE is required")
+	}
+	if strings.Contains(source, "${") {
+		return errors.New("DB_SOURCE contains an unexpanded ${...} variable")
+	}
+
+	scheme, _, isURL := strings.Cut(source, "://")
+	if !isURL {
+		for _, setting := range strings.Fields(source) {
+			if key, _, ok := strings.Cut(setting, "="); !ok || key == "" {
+				return fmt.Errorf("DB_SOURCE is neither a postgres:// URL nor key=value settings: %q is not a setting", setting)
+			}
+		}
+		return nil
+	}
+
+	if scheme != "postgres" && scheme != "postgresql" {
+		return fmt.Errorf("DB_SOURCE scheme %q is not supported, use postgresql://", scheme)
+	}
+	u, err := url.Parse(source)
+	if err != nil {
+		// The error quotes the URL, password included.
+		return errors.New("DB_SOURCE is not a valid URL")
+	}
+	if u.Hostname() == "" {
+		return errors.New("DB_SOURCE has no host")
+	}
+	if strings.Trim(u.Path, "/") == "" {
+		return errors.New("DB_SOURCE has no database name")
+	}
+	if _, err := url.ParseQuery(u.RawQuery); err != nil {
+		return errors.New("DB_SOURCE has a malformed query string")
+	}
+	return nil
+}
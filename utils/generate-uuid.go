@@ -7,3 +7,10 @@ import (
 func GenerateUUID() string {
 	return uuid.New().String()
 }
+
+// NameUUID derives a UUID from name, the same name always gives the same
+// UUID. It is the UUID of a record that must be created at most once, e.g.
+// the transfer of a batch item.
+func NameUUID(name string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte("bank-api:"+name)).String()
+}
@@ -1,20 +1,172 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"strings"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-func HashPasswrod(pwd string) (string, error) {
-	hashedPwd, err := bcrypt.GenerateFromPassword([]byte(pwd), bcrypt.DefaultCost)
+const (
+	HashAlgorithmBcrypt   = "bcrypt"
+	HashAlgorithmArgon2id = "argon2id"
+)
+
+var ErrPasswordMismatch = errors.New("password does not match")
+
+type PasswordHasher interface {
+	Hash(pwd string) (string, error)
+	Check(pwd, hashedPwd string) error
+	NeedsRehash(hashedPwd string) bool
+}
+
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// passwordHasher hashes with the configured algorithm and verifies any
+// supported PHC-formatted hash, so stored hashes can be upgraded on login.
+type passwordHasher struct {
+	algorithm  string
+	bcryptCost int
+	argon2     Argon2Params
+}
+
+func NewPasswordHasher(config *Config) (PasswordHasher, error) {
+	hasher := &passwordHasher{
+		algorithm:  config.PasswordHashAlgorithm,
+		bcryptCost: config.BcryptCost,
+		argon2: Argon2Params{
+			Memory:      config.Argon2Memory,
+			Iterations:  config.Argon2Iterations,
+			Parallelism: config.Argon2Parallelism,
+			SaltLength:  config.Argon2SaltLength,
+			KeyLength:   config.Argon2KeyLength,
+		},
+	}
+
+	switch hasher.algorithm {
+	case HashAlgorithmBcrypt:
+		if hasher.bcryptCost < bcrypt.MinCost || hasher.bcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("invalid bcrypt cost: must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	case HashAlgorithmArgon2id:
+		p := hasher.argon2
+		if p.Memory == 0 || p.Iterations == 0 || p.Parallelism == 0 || p.SaltLength == 0 || p.KeyLength == 0 {
+			return nil, fmt.Errorf("invalid argon2id parameters: memory, iterations, parallelism, salt and key length must be positive")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm: %s", hasher.algorithm)
+	}
+
+	return hasher, nil
+}
+
+func (h *passwordHasher) Hash(pwd string) (string, error) {
+	if h.algorithm == HashAlgorithmArgon2id {
+		return h.hashArgon2id(pwd)
+	}
+
+	hashedPwd, err := bcrypt.GenerateFromPassword([]byte(pwd), h.bcryptCost)
 	if err != nil {
 		return "", fmt.Errorf("failed to hash password")
 	}
 	return string(hashedPwd), nil
 }
 
-func CheckPwd(pwd, hashedPwd string) error {
-	return bcrypt.
-		CompareHashAndPassword([]byte(hashedPwd), []byte(pwd))
+func (h *passwordHasher) Check(pwd, hashedPwd string) error {
+	if strings.HasPrefix(hashedPwd, "$"+HashAlgorithmArgon2id+"$") {
+		params, salt, key, err := decodeArgon2id(hashedPwd)
+		if err != nil {
+			return err
+		}
+		other := argon2.IDKey([]byte(pwd), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+		if subtle.ConstantTimeCompare(key, other) != 1 {
+			return ErrPasswordMismatch
+		}
+		return nil
+	}
+
+	err := bcrypt.CompareHashAndPassword([]byte(hashedPwd), []byte(pwd))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrPasswordMismatch
+	}
+	return err
+}
+
+// NeedsRehash reports whether hashedPwd was produced by another algorithm or
+// with parameters that differ from the current configuration.
+func (h *passwordHasher) NeedsRehash(hashedPwd string) bool {
+	if h.algorithm == HashAlgorithmArgon2id {
+		params, salt, key, err := decodeArgon2id(hashedPwd)
+		if err != nil {
+			return true
+		}
+		params.SaltLength = uint32(len(salt))
+		params.KeyLength = uint32(len(key))
+		return params != h.argon2
+	}
+
+	cost, err := bcrypt.Cost([]byte(hashedPwd))
+	if err != nil {
+		return true
+	}
+	return cost != h.bcryptCost
+}
+
+func (h *passwordHasher) hashArgon2id(pwd string) (string, error) {
+	salt := make([]byte, h.argon2.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to hash password")
+	}
+
+	key := argon2.IDKey([]byte(pwd), salt, h.argon2.Iterations, h.argon2.Memory, h.argon2.Parallelism, h.argon2.KeyLength)
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		HashAlgorithmArgon2id, argon2.Version,
+		h.argon2.Memory, h.argon2.Iterations, h.argon2.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// decodeArgon2id parses a PHC string such as $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>.
+func decodeArgon2id(hashedPwd string) (Argon2Params, []byte, []byte, error) {
+	invalid := fmt.Errorf("invalid argon2id hash")
+
+	parts := strings.Split(hashedPwd, "$")
+	if len(parts) != 6 || parts[1] != HashAlgorithmArgon2id {
+		return Argon2Params{}, nil, nil, invalid
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return Argon2Params{}, nil, nil, invalid
+	}
+
+	var params Argon2Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return Argon2Params{}, nil, nil, invalid
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Argon2Params{}, nil, nil, invalid
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return Argon2Params{}, nil, nil, invalid
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+
+	return params, salt, key, nil
 }
@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testHasher(t *testing.T, algorithm string, bcryptCost int, memory uint32) PasswordHasher {
+	t.Helper()
+	hasher, err := NewPasswordHasher(&Config{
+		PasswordHashAlgorithm: algorithm,
+		BcryptCost:            bcryptCost,
+		Argon2Memory:          memory,
+		Argon2Iterations:      1,
+		Argon2Parallelism:     1,
+		Argon2SaltLength:      16,
+		Argon2KeyLength:       32,
+	})
+	if err != nil {
+		t.Fatalf("NewPasswordHasher() error = %v", err)
+	}
+	return hasher
+}
+
+func TestPasswordHasher(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm string
+		prefix    string
+	}{
+		{name: "argon2id", algorithm: HashAlgorithmArgon2id, prefix: "$argon2id$v=19$m=1024,t=1,p=1$"},
+		{name: "bcrypt", algorithm: HashAlgorithmBcrypt, prefix: "$2a$04$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hasher := testHasher(t, tt.algorithm, 4, 1024)
+
+			hashed, err := hasher.Hash("hardpassword1234")
+			if err != nil {
+				t.Fatalf("Hash() error = %v", err)
+			}
+			if !strings.HasPrefix(hashed, tt.prefix) {
+				t.Errorf("Hash() got = %v, want prefix %v", hashed, tt.prefix)
+			}
+			if err := hasher.Check("hardpassword1234", hashed); err != nil {
+				t.Errorf("Check() error = %v", err)
+			}
+			if err := hasher.Check("wrongpassword", hashed); !errors.Is(err, ErrPasswordMismatch) {
+				t.Errorf("Check() error = %v, want %v", err, ErrPasswordMismatch)
+			}
+			if hasher.NeedsRehash(hashed) {
+				t.Errorf("NeedsRehash() = true for a current hash")
+			}
+		})
+	}
+}
+
+func TestPasswordHasherNeedsRehash(t *testing.T) {
+	argon := testHasher(t, HashAlgorithmArgon2id, 4, 1024)
+	bcryptHasher := testHasher(t, HashAlgorithmBcrypt, 4, 1024)
+
+	bcryptHash, err := bcryptHasher.Hash("hardpassword1234")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	weakArgonHash, err := testHasher(t, HashAlgorithmArgon2id, 4, 512).Hash("hardpassword1234")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		hasher PasswordHasher
+		hashed string
+		want   bool
+	}{
+		{name: "bcrypt hash with argon2id configured", hasher: argon, hashed: bcryptHash, want: true},
+		{name: "argon2id hash with outdated memory", hasher: argon, hashed: weakArgonHash, want: true},
+		{name: "bcrypt hash with higher cost configured", hasher: testHasher(t, HashAlgorithmBcrypt, 5, 1024), hashed: bcryptHash, want: true},
+		{name: "argon2id hash with bcrypt configured", hasher: bcryptHasher, hashed: weakArgonHash, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hasher.NeedsRehash(tt.hashed); got != tt.want {
+				t.Errorf("NeedsRehash() got = %v, want %v", got, tt.want)
+			}
+			if err := tt.hasher.Check("hardpassword1234", tt.hashed); err != nil {
+				t.Errorf("Check() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+type PasswordPolicy struct {
+	MinLength int
+	MaxLength int
+	breached  map[string]struct{}
+}
+
+// NewPasswordPolicy loads the optional breached-password list. Each line is
+// either a plain password or a SHA-1 hex digest as published by
+// haveibeenpwned ("HASH" or "HASH:count").
+func NewPasswordPolicy(minLength, maxLength int, breachedListPath string) (*PasswordPolicy, error) {
+	if minLength <= 0 || maxLength < minLength {
+		return nil, fmt.Errorf("invalid password policy: length must be between 1 and max length")
+	}
+
+	policy := &PasswordPolicy{
+		MinLength: minLength,
+		MaxLength: maxLength,
+		breached:  map[string]struct{}{},
+	}
+	if breachedListPath == "" {
+		return policy, nil
+	}
+
+	file, err := os.Open(breachedListPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open breached password list: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if digest, _, _ := strings.Cut(line, ":"); isSHA1Hex(digest) {
+			policy.breached[strings.ToUpper(digest)] = struct{}{}
+			continue
+		}
+		policy.breached[breachedKey(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read breached password list: %w", err)
+	}
+
+	return policy, nil
+}
+
+func (p *PasswordPolicy) Validate(pwd string) error {
+	if utf8.RuneCountInString(pwd) < p.MinLength {
+		return fmt.Errorf("password must be at least %d characters", p.MinLength)
+	}
+	// MaxLength counts bytes because bcrypt only accepts up to 72 bytes.
+	if len(pwd) > p.MaxLength {
+		return fmt.Errorf("password must be at most %d bytes", p.MaxLength)
+	}
+	if _, ok := p.breached[breachedKey(pwd)]; ok {
+		return fmt.Errorf("password appears in a list of breached passwords")
+	}
+	return nil
+}
+
+func breachedKey(pwd string) string {
+	sum := sha1.Sum([]byte(pwd))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func isSHA1Hex(s string) bool {
+	if len(s) != sha1.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
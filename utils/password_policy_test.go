@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPasswordPolicy(t *testing.T) {
+	list := filepath.Join(t.TempDir(), "breached.txt")
+	// "password1234" as plain text and the SHA-1 of "qwerty123456" in haveibeenpwned format.
+	content := "password1234\nF3BA381B6BAEF526BF70FF220B1DA4906989224B:42\n"
+	if err := os.WriteFile(list, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := NewPasswordPolicy(8, 72, list)
+	if err != nil {
+		t.Fatalf("NewPasswordPolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		pwd     string
+		wantErr bool
+	}{
+		{name: "valid password", pwd: "hardpassword1234", wantErr: false},
+		{name: "too short", pwd: "short", wantErr: true},
+		{name: "too long", pwd: string(make([]byte, 73)), wantErr: true},
+		{name: "breached plain entry", pwd: "password1234", wantErr: true},
+		{name: "breached sha1 entry", pwd: "qwerty123456", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Validate(tt.pwd)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package utils
+
+import "math/big"
+
+// MulDivHalfEven returns value*num/den rounded half to even (banker's rounding).
+// Intermediate results use arbitrary precision so large balances cannot overflow.
+func MulDivHalfEven(value, num, den int64) int64 {
+	if den < 0 {
+		num, den = -num, -den
+	}
+
+	product := new(big.Int).Mul(big.NewInt(value), big.NewInt(num))
+	divisor := big.NewInt(den)
+	quo, rem := new(big.Int).QuoRem(product, divisor, new(big.Int))
+
+	twiceRem := new(big.Int).Abs(rem)
+	twiceRem.Lsh(twiceRem, 1)
+	switch twiceRem.Cmp(divisor) {
+	case 1:
+		quo.Add(quo, big.NewInt(int64(product.Sign())))
+	case 0:
+		if quo.Bit(0) == 1 {
+			quo.Add(quo, big.NewInt(int64(product.Sign())))
+		}
+	}
+
+	return quo.Int64()
+}
@@ -0,0 +1,29 @@
+package utils
+
+import "testing"
+
+func TestMulDivHalfEven(t *testing.T) {
+	tests := []struct {
+		name            string
+		value, num, den int64
+		want            int64
+	}{
+		{name: "exact", value: 100, num: 3, den: 4, want: 75},
+		{name: "round down", value: 10, num: 1, den: 4, want: 2},
+		{name: "round up", value: 10, num: 3, den: 4, want: 8},
+		{name: "half to even down", value: 5, num: 1, den: 2, want: 2},
+		{name: "half to even up", value: 7, num: 1, den: 2, want: 4},
+		{name: "negative half to even", value: -5, num: 1, den: 2, want: -2},
+		{name: "negative round", value: -10, num: 3, den: 4, want: -8},
+		{name: "daily interest", value: 1_000_000, num: 150, den: 10000 * 365, want: 41},
+		{name: "no overflow", value: 9_000_000_000_000_000, num: 10000, den: 20000, want: 4_500_000_000_000_000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MulDivHalfEven(tt.value, tt.num, tt.den); got != tt.want {
+				t.Errorf("MulDivHalfEven() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
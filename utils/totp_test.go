@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// RFC 6238 appendix B vectors for the SHA1 seed "12345678901234567890", truncated to 6 digits.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	tests := []struct {
+		name string
+		unix int64
+		want string
+	}{
+		{name: "t=59", unix: 59, want: "287082"},
+		{name: "t=1111111109", unix: 1111111109, want: "081804"},
+		{name: "t=1111111111", unix: 1111111111, want: "050471"},
+		{name: "t=1234567890", unix: 1234567890, want: "005924"},
+		{name: "t=2000000000", unix: 2000000000, want: "279037"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TOTPCode(rfc6238Secret, TOTPStep(time.Unix(tt.unix, 0)))
+			if err != nil {
+				t.Fatalf("TOTPCode() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("TOTPCode() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+
+	tests := []struct {
+		name   string
+		code   string
+		wantOk bool
+	}{
+		{name: "current step", code: "081804", wantOk: true},
+		{name: "previous step within skew", code: mustTOTP(t, TOTPStep(now)-1), wantOk: true},
+		{name: "step outside skew", code: mustTOTP(t, TOTPStep(now)-2), wantOk: false},
+		{name: "wrong length", code: "81804", wantOk: false},
+		{name: "wrong code", code: "000000", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok := ValidateTOTP(rfc6238Secret, tt.code, now)
+			if ok != tt.wantOk {
+				t.Errorf("ValidateTOTP() ok = %v, want %v", ok, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestHashRecoveryCode(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(3)
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCodes() error = %v", err)
+	}
+	if len(codes) != 3 {
+		t.Fatalf("GenerateRecoveryCodes() got %d codes, want 3", len(codes))
+	}
+	for _, code := range codes {
+		if HashRecoveryCode(code) != HashRecoveryCode(" "+code[:5]+code[6:]+" ") {
+			t.Errorf("HashRecoveryCode() is not normalized for %v", code)
+		}
+	}
+}
+
+func mustTOTP(t *testing.T, step int64) string {
+	t.Helper()
+	code, err := TOTPCode(rfc6238Secret, step)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
@@ -0,0 +1,15 @@
+package validators
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/terajari/bank-api/utils"
+)
+
+func Password(policy *utils.PasswordPolicy) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		if pwd, ok := fl.Field().Interface().(string); ok {
+			return policy.Validate(pwd) == nil
+		}
+		return false
+	}
+}
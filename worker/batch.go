@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/terajari/bank-api/logging"
+	"github.com/terajari/bank-api/usecase"
+)
+
+type BatchWorker struct {
+	usecase  usecase.BatchUsecase
+	interval time.Duration
+}
+
+func NewBatchWorker(uc usecase.BatchUsecase, interval time.Duration) *BatchWorker {
+	return &BatchWorker{
+		usecase:  uc,
+		interval: interval,
+	}
+}
+
+// Run drains the queue of batch jobs, then polls every interval until ctx is
+// done. Every poll first requeues the jobs abandoned by a dead worker.
+func (w *BatchWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		requeued, failed, err := w.usecase.RequeueStale(ctx)
+		if err != nil {
+			logging.FromContext(ctx).Error("batch: requeuing stale jobs failed", "error", err)
+		} else if requeued > 0 || failed > 0 {
+			logging.FromContext(ctx).Warn("batch: stale jobs found", "requeued", requeued, "failed", failed)
+		}
+
+		for ctx.Err() == nil {
+			processed, err := w.usecase.ProcessNext(ctx)
+			if err != nil {
+				logging.FromContext(ctx).Error("batch: processing failed", "error", err)
+			}
+			if !processed {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"context"
+
+	"github.com/terajari/bank-api/logging"
+)
+
+// Group runs background workers until they are stopped. Workers are stopped
+// one at a time in the reverse order they were started, each one having
+// returned before the next is cancelled.
+type Group struct {
+	workers []*running
+}
+
+type running struct {
+	name   string
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Go starts run in its own goroutine with a context that Stop cancels.
+func (g *Group) Go(ctx context.Context, name string, run func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(logging.With(ctx, "worker", name))
+	w := &running{name: name, cancel: cancel, done: make(chan struct{})}
+	g.workers = append(g.workers, w)
+	go func() {
+		defer close(w.done)
+		run(ctx)
+	}()
+}
+
+// Stop cancels the workers and waits for them to return. It gives up when
+// ctx is done, leaving the remaining workers cancelled but possibly running.
+func (g *Group) Stop(ctx context.Context) error {
+	for i := len(g.workers) - 1; i >= 0; i-- {
+		w := g.workers[i]
+		w.cancel()
+		select {
+		case <-w.done:
+			logging.FromContext(ctx).Info("worker stopped", "worker", w.name)
+		case <-ctx.Done():
+			for _, rest := range g.workers[:i] {
+				rest.cancel()
+			}
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGroupStop(t *testing.T) {
+	var mu sync.Mutex
+	var stopped []string
+	run := func(name string) func(ctx context.Context) {
+		return func(ctx context.Context) {
+			<-ctx.Done()
+			mu.Lock()
+			stopped = append(stopped, name)
+			mu.Unlock()
+		}
+	}
+
+	var g Group
+	g.Go(context.Background(), "interest", run("interest"))
+	g.Go(context.Background(), "holds", run("holds"))
+	g.Go(context.Background(), "batch", run("batch"))
+
+	if err := g.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	want := []string{"batch", "holds", "interest"}
+	if !reflect.DeepEqual(stopped, want) {
+		t.Errorf("stopped = %v, want %v", stopped, want)
+	}
+}
+
+func TestGroupStopTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	cancelled := make(chan struct{})
+	var g Group
+	g.Go(context.Background(), "first", func(ctx context.Context) {
+		<-ctx.Done()
+		close(cancelled)
+	})
+	g.Go(context.Background(), "stuck", func(ctx context.Context) {
+		<-release
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := g.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Stop() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("remaining worker was not cancelled")
+	}
+}
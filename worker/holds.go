@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/terajari/bank-api/logging"
+	"github.com/terajari/bank-api/usecase"
+)
+
+type HoldExpiryWorker struct {
+	usecase  usecase.TransferUsecase
+	interval time.Duration
+}
+
+func NewHoldExpiryWorker(uc usecase.TransferUsecase, interval time.Duration) *HoldExpiryWorker {
+	return &HoldExpiryWorker{
+		usecase:  uc,
+		interval: interval,
+	}
+}
+
+// Run releases expired holds every interval until ctx is done.
+func (w *HoldExpiryWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		n, err := w.usecase.ExpireHolds(ctx)
+		if err != nil {
+			logging.FromContext(ctx).Error("holds: expiry failed", "error", err)
+		}
+		if n > 0 {
+			logging.FromContext(ctx).Info("holds: expired holds", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/terajari/bank-api/logging"
+	"github.com/terajari/bank-api/usecase"
+)
+
+// maxCatchUpDays bounds how many missed days are accrued after downtime.
+const maxCatchUpDays = 31
+
+type InterestWorker struct {
+	usecase usecase.InterestUsecase
+	dryRun  bool
+	now     func() time.Time
+}
+
+func NewInterestWorker(uc usecase.InterestUsecase, dryRun bool) *InterestWorker {
+	return &InterestWorker{
+		usecase: uc,
+		dryRun:  dryRun,
+		now:     time.Now,
+	}
+}
+
+// Run accrues interest for every completed day and posts the previous months
+// right away, then again shortly after each UTC midnight until ctx is done.
+func (w *InterestWorker) Run(ctx context.Context) {
+	for {
+		w.runOnce(ctx)
+
+		now := w.now().UTC()
+		next := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 5, 0, 0, time.UTC)
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+func (w *InterestWorker) runOnce(ctx context.Context) {
+	today := w.now().UTC().Truncate(24 * time.Hour)
+	yesterday := today.AddDate(0, 0, -1)
+
+	from := yesterday
+	last, ok, err := w.usecase.LastAccrualDate(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("interest: cannot read last accrual date", "error", err)
+		return
+	}
+	if ok && !w.dryRun {
+		from = last.AddDate(0, 0, 1)
+		if oldest := yesterday.AddDate(0, 0, -maxCatchUpDays+1); from.Before(oldest) {
+			from = oldest
+		}
+	}
+
+	for day := from; !day.After(yesterday); day = day.AddDate(0, 0, 1) {
+		report, err := w.usecase.AccrueDaily(ctx, day, w.dryRun)
+		if err != nil {
+			logging.FromContext(ctx).Error("interest: accrual failed", "day", day.Format(time.DateOnly), "error", err)
+			return
+		}
+		w.log(ctx, "accrual", report)
+	}
+
+	firstOfMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	report, err := w.usecase.PostMonthly(ctx, firstOfMonth, w.dryRun)
+	if err != nil {
+		logging.FromContext(ctx).Error("interest: posting failed", "before", firstOfMonth.Format(time.DateOnly), "error", err)
+	}
+	w.log(ctx, "posting", report)
+}
+
+func (w *InterestWorker) log(ctx context.Context, kind string, report any) {
+	logging.FromContext(ctx).Info("interest: report", "kind", kind, "dry_run", w.dryRun, "report", report)
+}
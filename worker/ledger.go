@@ -0,0 +1,41 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/terajari/bank-api/logging"
+	"github.com/terajari/bank-api/usecase"
+)
+
+type LedgerWorker struct {
+	usecase  usecase.LedgerUsecase
+	interval time.Duration
+}
+
+func NewLedgerWorker(uc usecase.LedgerUsecase, interval time.Duration) *LedgerWorker {
+	return &LedgerWorker{
+		usecase:  uc,
+		interval: interval,
+	}
+}
+
+// Run verifies the ledger every interval until ctx is done. The first
+// verification waits an interval too, so that replicas restarting together
+// do not all scan the ledger at once. The usecase logs what it finds and
+// updates the ledger metrics.
+func (w *LedgerWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := w.usecase.Verify(ctx); err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Error("ledger: verification failed", "error", err)
+		}
+	}
+}